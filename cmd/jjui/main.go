@@ -48,6 +48,8 @@ var (
 	editConfig      bool
 	installLuaTypes bool
 	help            bool
+	diffEditor      bool
+	mergeTool       bool
 )
 
 func init() {
@@ -61,6 +63,8 @@ func init() {
 	flag.BoolVar(&editConfig, "config", false, "Open configuration file in $EDITOR")
 	flag.BoolVar(&installLuaTypes, "install-lua-types", false, "Write Lua type definitions to config directory for LuaLS autocomplete")
 	flag.BoolVar(&help, "help", false, "Show help information")
+	flag.BoolVar(&diffEditor, "diff-editor", false, "Run as jj diff editor: jjui --diff-editor $left $right")
+	flag.BoolVar(&mergeTool, "merge-tool", false, "Run as jj merge tool: jjui --merge-tool $base $left $right $output")
 
 	flag.Usage = func() {
 		fmt.Printf("Usage: jjui [flags] [location]\n")
@@ -116,6 +120,10 @@ func run() int {
 		return 0
 	case editConfig:
 		return config.Edit()
+	case diffEditor:
+		return runDiffEditor(askpassServer, flag.Args())
+	case mergeTool:
+		return runMergeTool(askpassServer, flag.Args())
 	}
 
 	var location string
@@ -152,17 +160,66 @@ func run() int {
 	appContext := context.NewAppContext(rootLocation, askpassServer)
 	defer appContext.Histories.Flush()
 
+	if err := scripting.InitVM(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing Lua VM: %v\n", err)
+		return 1
+	}
+	defer scripting.CloseVM(appContext)
+
+	if !loadConfig(appContext, rootLocation) {
+		return 1
+	}
+
+	if period >= 0 {
+		config.Current.UI.AutoRefreshInterval = period
+	}
+	if limit > 0 {
+		config.Current.Limit = limit
+	}
+	if revset != "" {
+		appContext.DefaultRevset = revset
+	} else if config.Current.Revisions.Revset != "" {
+		appContext.DefaultRevset = config.Current.Revisions.Revset
+	} else {
+		appContext.DefaultRevset = appContext.JJConfig.Revsets.Log
+	}
+	appContext.CurrentRevset = appContext.DefaultRevset
+
+	p := tea.NewProgram(ui.New(appContext), tea.WithInput(os.Stdin))
+	if config.Current.Ssh.HijackAskpass {
+		if err := askpassServer.StartListening(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: ssh.hijack_askpass: %v\n", err)
+			return 1
+		}
+		defer askpassServer.Close()
+
+		go askpassServer.Serve(showPassword(p.Send))
+
+		// uncomment the line below to show a fake prompt upon startup
+		// go showPassword(p.Send)("test", "Enter PIN for 'ssh': ", make(<-chan struct{}))
+	}
+	if _, err := p.Run(); err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// loadConfig loads the configuration files and config.lua scripts, the ones of
+// the repository at rootLocation overriding the global ones, and sets up the
+// theme. Problems are reported on stderr.
+func loadConfig(appContext *context.MainContext, rootLocation string) bool {
 	if output, err := config.LoadConfigFile(); err == nil {
 		if err := config.Current.Load(string(output), config.GetConfigDir()); err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
-			return 1
+			return false
 		}
 		for _, warning := range config.DeprecatedConfigWarnings(string(output)) {
 			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 		}
 	} else if !errors.Is(err, fs.ErrNotExist) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return 1
+		return false
 	}
 	// if JJUI_CONFIG_DIR is set, skip loading repository config as env config takes precedence over both global and repo-local configs. Otherwise load repo-local config which overrides global config on conflicts.
 	if config.EnvConfigDir() == "" {
@@ -170,32 +227,26 @@ func run() int {
 			repoConfigDir := filepath.Join(rootLocation, ".jjui")
 			if err := config.Current.Load(string(output), repoConfigDir); err != nil {
 				fmt.Fprintf(os.Stderr, "Error loading repository configuration: %v\n", err)
-				return 1
+				return false
 			}
 			for _, warning := range config.DeprecatedConfigWarnings(string(output)) {
 				fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 			}
 		} else if !errors.Is(err, fs.ErrNotExist) {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return 1
+			return false
 		}
 	}
 
-	if err := scripting.InitVM(appContext); err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing Lua VM: %v\n", err)
-		return 1
-	}
-	defer scripting.CloseVM(appContext)
-
 	if luaSource, err := config.LoadLuaConfigFile(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config.lua: %v\n", err)
-		return 1
+		return false
 	} else if luaSource != "" {
 		appContext.TerminalHasDarkBackground = lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
 		appContext.TerminalThemeDetected = true
 		if err := scripting.RunSetup(appContext, config.Current, luaSource); err != nil {
 			fmt.Fprintf(os.Stderr, "Error in config.lua: %v\n", err)
-			return 1
+			return false
 		}
 	}
 
@@ -203,7 +254,7 @@ func run() int {
 	if config.EnvConfigDir() == "" {
 		if luaSource, err := config.LoadRepoLuaConfigFile(rootLocation); err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading repository config.lua: %v\n", err)
-			return 1
+			return false
 		} else if luaSource != "" {
 			if !appContext.TerminalThemeDetected {
 				appContext.TerminalHasDarkBackground = lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
@@ -211,58 +262,24 @@ func run() int {
 			}
 			if err := scripting.RunSetup(appContext, config.Current, luaSource); err != nil {
 				fmt.Fprintf(os.Stderr, "Error in repository config.lua: %v\n", err)
-				return 1
+				return false
 			}
 		}
 	}
 
-	var theme map[string]config.Color
 	if !appContext.TerminalThemeDetected {
 		appContext.TerminalHasDarkBackground = lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
 		appContext.TerminalThemeDetected = true
 	}
 
-	theme, err = config.ResolveTheme(appContext.TerminalHasDarkBackground, appContext.JJConfig.GetApplicableColors())
+	theme, err := config.ResolveTheme(appContext.TerminalHasDarkBackground, appContext.JJConfig.GetApplicableColors())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading theme: %v\n", err)
-		return 1
+		return false
 	}
 
 	common.DefaultPalette.Update(theme)
-
-	if period >= 0 {
-		config.Current.UI.AutoRefreshInterval = period
-	}
-	if limit > 0 {
-		config.Current.Limit = limit
-	}
-	if revset != "" {
-		appContext.DefaultRevset = revset
-	} else if config.Current.Revisions.Revset != "" {
-		appContext.DefaultRevset = config.Current.Revisions.Revset
-	} else {
-		appContext.DefaultRevset = appContext.JJConfig.Revsets.Log
-	}
-	appContext.CurrentRevset = appContext.DefaultRevset
-
-	p := tea.NewProgram(ui.New(appContext), tea.WithInput(os.Stdin))
-	if config.Current.Ssh.HijackAskpass {
-		if err := askpassServer.StartListening(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: ssh.hijack_askpass: %v\n", err)
-			return 1
-		}
-		defer askpassServer.Close()
-
-		go askpassServer.Serve(showPassword(p.Send))
-
-		// uncomment the line below to show a fake prompt upon startup
-		// go showPassword(p.Send)("test", "Enter PIN for 'ssh': ", make(<-chan struct{}))
-	}
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
-		return 1
-	}
-	return 0
+	return true
 }
 
 func showPassword(send func(tea.Msg)) func(name, prompt string, done <-chan struct{}) []byte {
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/scripting"
+	"github.com/idursun/jjui/internal/ui"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/diffedit"
+	"github.com/idursun/jjui/internal/ui/mergetool"
+)
+
+// loadToolConfig loads the configuration like jjui does when it runs on its
+// own, for when it runs as an external tool of jj. jj starts tools from within
+// the workspace, so the repository is found from the current directory. Only
+// commands that leave the working copy alone are run, as jj holds it while
+// the tool runs.
+func loadToolConfig(askpassServer *askpass.Server) (*context.MainContext, bool) {
+	log.SetOutput(io.Discard)
+	location, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: couldn't determine the current directory: %v.\n", err)
+		return nil, false
+	}
+	rootLocation, err := getJJRootDir(location)
+	if err != nil {
+		rootLocation = location
+	}
+
+	appContext := context.NewAppContext(rootLocation, askpassServer)
+	if err := scripting.InitVM(appContext); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing Lua VM: %v\n", err)
+		return nil, false
+	}
+	if !loadConfig(appContext, rootLocation) {
+		scripting.CloseVM(appContext)
+		return nil, false
+	}
+	return appContext, true
+}
+
+func runDiffEditor(askpassServer *askpass.Server, args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: jjui --diff-editor <left> <right>\n")
+		return 2
+	}
+	appContext, ok := loadToolConfig(askpassServer)
+	if !ok {
+		return 1
+	}
+	defer scripting.CloseVM(appContext)
+
+	model, err := diffedit.New(args[0], args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	p := tea.NewProgram(ui.NewStandalone(model), tea.WithInput(os.Stdin))
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		return 1
+	}
+	if err := model.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !model.Applied() {
+		// a non-zero exit tells jj to abandon the edit
+		return 1
+	}
+	return 0
+}
+
+func runMergeTool(askpassServer *askpass.Server, args []string) int {
+	if len(args) != 4 {
+		fmt.Fprintf(os.Stderr, "Usage: jjui --merge-tool <base> <left> <right> <output>\n")
+		return 2
+	}
+	appContext, ok := loadToolConfig(askpassServer)
+	if !ok {
+		return 1
+	}
+	defer scripting.CloseVM(appContext)
+
+	model, err := mergetool.New(args[0], args[1], args[2], args[3])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	p := tea.NewProgram(ui.NewStandalone(model), tea.WithInput(os.Stdin))
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		return 1
+	}
+	if err := model.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !model.Applied() {
+		// a non-zero exit tells jj to leave the file conflicted
+		return 1
+	}
+	return 0
+}
@@ -244,7 +244,15 @@ func GetSuggestExecMode(c *Config) (SuggestMode, error) {
 }
 
 type GitConfig struct {
-	DefaultRemote string `toml:"default_remote"`
+	DefaultRemote   string                `toml:"default_remote"`
+	PreviewPush     []string              `toml:"preview_push"`
+	BackgroundFetch BackgroundFetchConfig `toml:"background_fetch"`
+}
+
+type BackgroundFetchConfig struct {
+	Interval int      `toml:"interval"`
+	Remotes  []string `toml:"remotes"`
+	Badge    bool     `toml:"badge"`
 }
 
 func GetGitDefaultRemote(c *Config) string {
@@ -73,6 +73,16 @@ function wait_close() end
 ---Yield and wait for revisions to be updated
 function wait_refresh() end
 
+---@class jjui.annotate
+---@field cancel fun()
+---@field diff fun()
+---@field jump_to_revision fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field page_down fun()
+---@field page_up fun()
+---@field close fun()
+
 ---@class jjui.bookmarks
 ---@field apply fun()
 ---@field bookmark_delete fun()
@@ -83,6 +93,7 @@ function wait_refresh() end
 ---@field cancel fun()
 ---@field cycle_remotes fun()
 ---@field cycle_remotes_back fun()
+---@field cycle_sync_filter fun()
 ---@field filter fun()
 ---@field move_down fun()
 ---@field move_up fun()
@@ -104,24 +115,122 @@ function wait_refresh() end
 ---@field move_down fun()
 ---@field move_up fun()
 
+---@class jjui.compare_files
+---@field cancel fun()
+---@field diff fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field swap fun()
+---@field close fun()
+
+---@class jjui.conflicts
+---@field apply fun()
+---@field cancel fun()
+---@field diff fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field resolve fun()
+---@field take_ours fun()
+---@field take_theirs fun()
+---@field close fun()
+
 ---@class jjui.diff
+---@field quick_search jjui.diff.quick_search
 ---@field half_page_down fun()
 ---@field half_page_up fun()
 ---@field left fun()
 ---@field move_bottom fun()
 ---@field move_top fun()
+---@field next_file fun()
+---@field next_hunk fun()
 ---@field page_down fun()
 ---@field page_up fun()
+---@field prev_file fun()
+---@field prev_hunk fun()
 ---@field right fun()
 ---@field scroll_down fun()
 ---@field scroll_up fun()
 ---@field show fun(value?: string|{content: string})
+---@field toggle_collapse fun()
+---@field toggle_collapse_all fun()
+---@field toggle_file_list fun()
+---@field toggle_side_by_side fun()
 ---@field toggle_wrap fun()
 
+---@class jjui.diff.quick_search
+---@field clear fun()
+---@field next fun()
+---@field prev fun()
+
+---@class jjui.diff_editor
+---@field apply fun()
+---@field cancel fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field next_file fun()
+---@field next_hunk fun()
+---@field page_down fun()
+---@field page_up fun()
+---@field prev_file fun()
+---@field prev_hunk fun()
+---@field toggle fun()
+---@field toggle_all fun()
+---@field toggle_collapse fun()
+---@field close fun()
+
+---@class jjui.divergence
+---@field cancel fun()
+---@field interdiff fun()
+---@field keep fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field next_version fun()
+---@field prev_version fun()
+---@field squash fun()
+---@field close fun()
+
+---@class jjui.explode
+---@field template jjui.explode.template
+---@field apply fun()
+---@field by_directory fun()
+---@field by_file fun()
+---@field cancel fun()
+---@field edit_template fun()
+---@field group_1 fun()
+---@field group_2 fun()
+---@field group_3 fun()
+---@field group_4 fun()
+---@field group_5 fun()
+---@field group_6 fun()
+---@field group_7 fun()
+---@field group_8 fun()
+---@field group_9 fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field ungroup fun()
+---@field close fun()
+
+---@class jjui.explode.template
+---@field apply fun()
+---@field cancel fun()
+---@field close fun()
+
+---@class jjui.file_history
+---@field cancel fun()
+---@field diff_scroll_down fun()
+---@field diff_scroll_up fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field page_down fun()
+---@field page_up fun()
+---@field restore fun()
+---@field close fun()
+
 ---@class jjui.file_search
 ---@field apply fun()
 ---@field cancel fun()
 ---@field edit fun()
+---@field file_history fun()
 ---@field move_down fun()
 ---@field move_up fun()
 ---@field page_down fun()
@@ -132,6 +241,8 @@ function wait_refresh() end
 ---@field close fun()
 
 ---@class jjui.git
+---@field push_preview jjui.git.push_preview
+---@field remotes jjui.git.remotes
 ---@field apply fun()
 ---@field cancel fun()
 ---@field cycle_remotes fun()
@@ -144,6 +255,38 @@ function wait_refresh() end
 ---@field page_up fun()
 ---@field push fun()
 ---@field quit fun()
+---@field remotes fun()
+---@field close fun()
+
+---@class jjui.git.push_preview
+---@field apply fun()
+---@field cancel fun()
+---@field next fun()
+---@field prev fun()
+---@field close fun()
+
+---@class jjui.git.remotes
+---@field confirmation jjui.git.remotes.confirmation
+---@field input jjui.git.remotes.input
+---@field add fun()
+---@field cancel fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field remove fun()
+---@field rename fun()
+---@field set_url fun()
+---@field close fun()
+
+---@class jjui.git.remotes.confirmation
+---@field apply fun()
+---@field cancel fun()
+---@field next fun()
+---@field prev fun()
+---@field close fun()
+
+---@class jjui.git.remotes.input
+---@field apply fun()
+---@field cancel fun()
 ---@field close fun()
 
 ---@class jjui.help
@@ -163,10 +306,43 @@ function wait_refresh() end
 ---@field cancel fun()
 ---@field close fun()
 
+---@class jjui.merge_tool
+---@field edit jjui.merge_tool.edit
+---@field apply fun()
+---@field cancel fun()
+---@field edit fun()
+---@field next_conflict fun()
+---@field prev_conflict fun()
+---@field scroll_down fun()
+---@field scroll_up fun()
+---@field take_base fun()
+---@field take_both fun()
+---@field take_left fun()
+---@field take_right fun()
+---@field unresolve fun()
+---@field close fun()
+
+---@class jjui.merge_tool.edit
+---@field accept fun()
+---@field cancel fun()
+---@field close fun()
+
+---@class jjui.op_diff
+---@field apply fun()
+---@field cancel fun()
+---@field diff fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field close fun()
+
 ---@class jjui.oplog
+---@field filter jjui.oplog.filter
 ---@field quick_search jjui.oplog.quick_search
 ---@field close fun()
+---@field compare fun()
 ---@field diff fun()
+---@field filter fun()
+---@field mark fun()
 ---@field move_down fun()
 ---@field move_up fun()
 ---@field page_down fun()
@@ -174,6 +350,12 @@ function wait_refresh() end
 ---@field quit fun()
 ---@field restore fun()
 ---@field revert fun()
+---@field toggle_collapse fun()
+
+---@class jjui.oplog.filter
+---@field apply fun()
+---@field cancel fun()
+---@field close fun()
 
 ---@class jjui.oplog.quick_search
 ---@field clear fun()
@@ -185,6 +367,29 @@ function wait_refresh() end
 ---@field cancel fun()
 ---@field close fun()
 
+---@class jjui.progress
+---@field cancel fun()
+---@field close fun()
+
+---@class jjui.rebase_plan
+---@field reword jjui.rebase_plan.reword
+---@field apply fun()
+---@field cancel fun()
+---@field drop fun()
+---@field move_down fun()
+---@field move_entry_down fun()
+---@field move_entry_up fun()
+---@field move_up fun()
+---@field pick fun()
+---@field reword fun()
+---@field squash fun()
+---@field close fun()
+
+---@class jjui.rebase_plan.reword
+---@field accept fun()
+---@field cancel fun()
+---@field close fun()
+
 ---@class jjui.redo
 ---@field apply fun()
 ---@field cancel fun()
@@ -196,6 +401,7 @@ function wait_refresh() end
 ---@field abandon jjui.revisions.abandon
 ---@field absorb jjui.revisions.absorb
 ---@field ace_jump jjui.revisions.ace_jump
+---@field compare jjui.revisions.compare
 ---@field details jjui.revisions.details
 ---@field duplicate jjui.revisions.duplicate
 ---@field evolog jjui.revisions.evolog
@@ -205,6 +411,7 @@ function wait_refresh() end
 ---@field revert jjui.revisions.revert
 ---@field set_bookmark jjui.revisions.set_bookmark
 ---@field set_parents jjui.revisions.set_parents
+---@field set_tag jjui.revisions.set_tag
 ---@field squash jjui.revisions.squash
 ---@field target_picker jjui.revisions.target_picker
 ---@field ace_jump fun()
@@ -225,6 +432,7 @@ function wait_refresh() end
 ---@field new fun()
 ---@field open_abandon fun()
 ---@field open_absorb fun()
+---@field open_compare fun()
 ---@field open_details fun()
 ---@field open_duplicate fun()
 ---@field open_evolog fun()
@@ -233,6 +441,7 @@ function wait_refresh() end
 ---@field open_revert fun()
 ---@field open_set_bookmark fun(args: {value?: string})
 ---@field open_set_parents fun()
+---@field open_set_tag fun()
 ---@field open_squash fun()
 ---@field page_down fun()
 ---@field page_up fun()
@@ -265,17 +474,27 @@ function wait_refresh() end
 ---@field cancel fun()
 ---@field close fun()
 
+---@class jjui.revisions.compare
+---@field apply fun()
+---@field cancel fun()
+---@field target_picker fun()
+---@field close fun()
+
 ---@class jjui.revisions.details
 ---@field confirmation jjui.revisions.details.confirmation
 ---@field absorb fun()
+---@field annotate fun()
 ---@field cancel fun()
 ---@field diff fun()
+---@field explode fun()
+---@field file_history fun()
 ---@field move_down fun()
 ---@field move_up fun()
 ---@field page_down fun()
 ---@field page_up fun()
 ---@field quit fun()
 ---@field refresh fun()
+---@field resolve fun()
 ---@field restore fun()
 ---@field revisions_changing_file fun()
 ---@field select_file fun(value?: string|{file: string})
@@ -307,6 +526,8 @@ function wait_refresh() end
 ---@field apply fun(args: {force?: boolean})
 ---@field cancel fun()
 ---@field diff fun()
+---@field interdiff fun()
+---@field mark fun()
 ---@field move_down fun()
 ---@field move_up fun()
 ---@field page_down fun()
@@ -369,6 +590,13 @@ function wait_refresh() end
 ---@field toggle_select fun()
 ---@field close fun()
 
+---@class jjui.revisions.set_tag
+---@field apply fun()
+---@field autocomplete fun()
+---@field autocomplete_back fun()
+---@field cancel fun()
+---@field close fun()
+
 ---@class jjui.revisions.squash
 ---@field ace_jump fun()
 ---@field apply fun(args: {force?: boolean})
@@ -403,6 +631,25 @@ function wait_refresh() end
 ---@field set fun(value?: string|{value: string})
 ---@field close fun()
 
+---@class jjui.sparse
+---@field picker jjui.sparse.picker
+---@field add fun()
+---@field apply fun()
+---@field cancel fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field remove fun()
+---@field reset fun()
+---@field close fun()
+
+---@class jjui.sparse.picker
+---@field apply fun()
+---@field cancel fun()
+---@field force_apply fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field close fun()
+
 ---@class jjui.status
 ---@field input jjui.status.input
 
@@ -416,6 +663,15 @@ function wait_refresh() end
 ---@field page_up fun()
 ---@field close fun()
 
+---@class jjui.tags
+---@field apply fun()
+---@field cancel fun()
+---@field delete fun()
+---@field move fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field close fun()
+
 ---@class jjui.ui
 ---@field preview jjui.ui.preview
 ---@field cancel fun()
@@ -425,12 +681,18 @@ function wait_refresh() end
 ---@field file_search_toggle fun()
 ---@field open_bookmarks fun()
 ---@field open_command_history fun()
+---@field open_conflicts fun()
+---@field open_divergence fun()
 ---@field open_git fun()
 ---@field open_help fun()
 ---@field open_oplog fun()
+---@field open_rebase_plan fun()
 ---@field open_redo fun()
 ---@field open_revset fun()
+---@field open_sparse fun()
+---@field open_tags fun()
 ---@field open_undo fun()
+---@field open_workspaces fun()
 ---@field preview_expand fun()
 ---@field preview_half_page_down fun()
 ---@field preview_half_page_up fun()
@@ -439,8 +701,11 @@ function wait_refresh() end
 ---@field preview_shrink fun()
 ---@field preview_toggle fun()
 ---@field preview_toggle_bottom fun()
+---@field preview_toggle_side_by_side fun()
 ---@field quick_search fun()
 ---@field quit fun()
+---@field show_compare fun(args: {from: string, to: string})
+---@field show_op_diff fun(args: {from: string, to: string})
 ---@field suspend fun()
 ---@field close fun()
 
@@ -454,24 +719,64 @@ function wait_refresh() end
 ---@field prev fun()
 ---@field close fun()
 
+---@class jjui.workspaces
+---@field confirmation jjui.workspaces.confirmation
+---@field input jjui.workspaces.input
+---@field add fun()
+---@field apply fun()
+---@field cancel fun()
+---@field check_stale fun()
+---@field forget fun()
+---@field move_down fun()
+---@field move_up fun()
+---@field rename fun()
+---@field update_stale fun()
+---@field close fun()
+
+---@class jjui.workspaces.confirmation
+---@field apply fun()
+---@field cancel fun()
+---@field next fun()
+---@field prev fun()
+---@field close fun()
+
+---@class jjui.workspaces.input
+---@field apply fun()
+---@field cancel fun()
+---@field close fun()
+
 ---@class jjui
 ---@field revisions jjui.revisions
 ---@field revset jjui.revset
 ---@field context jjui.context
+---@field annotate jjui.annotate
 ---@field bookmarks jjui.bookmarks
 ---@field choose jjui.choose
 ---@field command_history jjui.command_history
+---@field compare_files jjui.compare_files
+---@field conflicts jjui.conflicts
 ---@field diff jjui.diff
+---@field diff_editor jjui.diff_editor
+---@field divergence jjui.divergence
+---@field explode jjui.explode
+---@field file_history jjui.file_history
 ---@field file_search jjui.file_search
 ---@field git jjui.git
 ---@field help jjui.help
 ---@field input jjui.input
+---@field merge_tool jjui.merge_tool
+---@field op_diff jjui.op_diff
 ---@field oplog jjui.oplog
 ---@field password jjui.password
+---@field progress jjui.progress
+---@field rebase_plan jjui.rebase_plan
 ---@field redo jjui.redo
+---@field sparse jjui.sparse
 ---@field status jjui.status
+---@field tags jjui.tags
 ---@field ui jjui.ui
 ---@field undo jjui.undo
+---@field workspaces jjui.workspaces
 ---@field builtin jjui.builtin
 ---@field jj_async fun(...: string|string[])
 ---@field jj_interactive fun(...: string|string[])
@@ -486,22 +791,36 @@ function wait_refresh() end
 ---@field wait_refresh fun()
 
 ---@class jjui.builtin
+---@field annotate jjui.annotate
 ---@field bookmarks jjui.bookmarks
 ---@field choose jjui.choose
 ---@field command_history jjui.command_history
+---@field compare_files jjui.compare_files
+---@field conflicts jjui.conflicts
 ---@field diff jjui.diff
+---@field diff_editor jjui.diff_editor
+---@field divergence jjui.divergence
+---@field explode jjui.explode
+---@field file_history jjui.file_history
 ---@field file_search jjui.file_search
 ---@field git jjui.git
 ---@field help jjui.help
 ---@field input jjui.input
+---@field merge_tool jjui.merge_tool
+---@field op_diff jjui.op_diff
 ---@field oplog jjui.oplog
 ---@field password jjui.password
+---@field progress jjui.progress
+---@field rebase_plan jjui.rebase_plan
 ---@field redo jjui.redo
 ---@field revisions jjui.revisions
 ---@field revset jjui.revset
+---@field sparse jjui.sparse
 ---@field status jjui.status
+---@field tags jjui.tags
 ---@field ui jjui.ui
 ---@field undo jjui.undo
+---@field workspaces jjui.workspaces
 
 ---@type jjui
 jjui = {}
@@ -98,3 +98,23 @@ func ParseBookmarkListOutput(output string) []Bookmark {
 	}
 	return bookmarks
 }
+
+type TrackedBookmark struct {
+	Name     string
+	Remote   string
+	CommitId string
+}
+
+// ParseTrackedBookmarks parses the output of BookmarkListTracked, leaving out
+// the bookmarks of the git pseudo remote
+func ParseTrackedBookmarks(output string) []TrackedBookmark {
+	var bookmarks []TrackedBookmark
+	for line := range strings.SplitSeq(output, "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 3 || parts[1] == "git" {
+			continue
+		}
+		bookmarks = append(bookmarks, TrackedBookmark{Name: parts[0], Remote: parts[1], CommitId: parts[2]})
+	}
+	return bookmarks
+}
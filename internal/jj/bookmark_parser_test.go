@@ -117,3 +117,11 @@ feature;origin;true;false;false;b`,
 		})
 	}
 }
+
+func TestParseTrackedBookmarks(t *testing.T) {
+	output := "main\torigin\tabc123\nmain\tgit\tabc123\nfeature\tupstream\t\n"
+	assert.Equal(t, []TrackedBookmark{
+		{Name: "main", Remote: "origin", CommitId: "abc123"},
+		{Name: "feature", Remote: "upstream", CommitId: ""},
+	}, ParseTrackedBookmarks(output))
+}
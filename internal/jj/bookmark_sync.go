@@ -0,0 +1,118 @@
+package jj
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BookmarkSync is how far a local bookmark and one of the remote bookmarks it
+// tracks have drifted apart
+type BookmarkSync struct {
+	Name     string
+	Remote   string
+	CommitId string
+	Ahead    int
+	Behind   int
+}
+
+// Unpushed reports whether the local bookmark has commits the remote does not
+func (s BookmarkSync) Unpushed() bool {
+	return s.Ahead > 0
+}
+
+// Unpulled reports whether the remote has commits the local bookmark does not
+func (s BookmarkSync) Unpulled() bool {
+	return s.Behind > 0
+}
+
+func (s BookmarkSync) Diverged() bool {
+	return s.Ahead > 0 && s.Behind > 0
+}
+
+// Counts renders the counts like ↑2↓1, leaving out the zero ones
+func (s BookmarkSync) Counts() string {
+	var counts string
+	if s.Ahead > 0 {
+		counts += "↑" + strconv.Itoa(s.Ahead)
+	}
+	if s.Behind > 0 {
+		counts += "↓" + strconv.Itoa(s.Behind)
+	}
+	return counts
+}
+
+// OutOfSync returns the local bookmarks along with the tracked remotes they
+// point to a different commit than, which are the ones worth counting.
+// Conflicted bookmarks are left out as they have no single target.
+func OutOfSync(bookmarks []Bookmark) []BookmarkSync {
+	var pairs []BookmarkSync
+	for _, b := range bookmarks {
+		if b.Local == nil || b.Conflict || b.Local.CommitId == "" {
+			continue
+		}
+		for _, remote := range b.Remotes {
+			if remote.Tracked && remote.CommitId != "" && remote.CommitId != b.Local.CommitId {
+				pairs = append(pairs, BookmarkSync{Name: b.Name, Remote: remote.Remote, CommitId: b.Local.CommitId})
+			}
+		}
+	}
+	return pairs
+}
+
+// BookmarksAheadBehind lists the commits only one side of each of the syncs
+// can reach, all in one go. For every such commit it prints the index of the
+// syncs it counts for, followed by + when the local bookmark reaches it and by
+// - when the remote one does.
+func BookmarksAheadBehind(syncs []BookmarkSync) CommandArgs {
+	ranges := make([]string, 0, len(syncs))
+	var template strings.Builder
+	for i, s := range syncs {
+		local := fmt.Sprintf("bookmarks(%s)", exactStringPattern(s.Name))
+		upstream := fmt.Sprintf("remote_bookmarks(%s, %s)", exactStringPattern(s.Name), exactStringPattern(s.Remote))
+		ahead := fmt.Sprintf("%s..%s", upstream, local)
+		behind := fmt.Sprintf("%s..%s", local, upstream)
+		ranges = append(ranges, "("+ahead+")", "("+behind+")")
+		fmt.Fprintf(&template, `if(self.contained_in(%s), "%d+ ") ++ if(self.contained_in(%s), "%d- ") ++ `, strconv.Quote(ahead), i, strconv.Quote(behind), i)
+	}
+	template.WriteString(`"\n"`)
+	return []string{"log", "-r", strings.Join(ranges, " | "), "--no-graph", "--template", template.String(), "--color", "never", "--ignore-working-copy"}
+}
+
+// ParseAheadBehind fills in the counts of syncs from the output of
+// BookmarksAheadBehind
+func ParseAheadBehind(output string, syncs []BookmarkSync) {
+	for mark := range strings.FieldsSeq(output) {
+		last := len(mark) - 1
+		if last < 1 {
+			continue
+		}
+		i, err := strconv.Atoi(mark[:last])
+		if err != nil || i < 0 || i >= len(syncs) {
+			continue
+		}
+		switch mark[last] {
+		case '+':
+			syncs[i].Ahead++
+		case '-':
+			syncs[i].Behind++
+		}
+	}
+}
+
+// SyncLabel renders the counts of a bookmark against its remotes, naming the
+// remotes only when there is more than one
+func SyncLabel(syncs []BookmarkSync) string {
+	var labels []string
+	for _, s := range syncs {
+		counts := s.Counts()
+		if counts == "" {
+			continue
+		}
+		if len(syncs) > 1 {
+			counts = s.Remote + counts
+		}
+		labels = append(labels, counts)
+	}
+	return strings.Join(labels, " ")
+}
@@ -0,0 +1,50 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutOfSync(t *testing.T) {
+	output := `main;.;false;false;false;a1
+main;origin;true;false;false;b2
+main;upstream;false;false;false;c3
+docs;.;false;false;false;d4
+docs;origin;true;false;false;d4
+wip;.;false;true;false;
+wip;origin;true;false;false;e5`
+	assert.Equal(t, []BookmarkSync{
+		{Name: "main", Remote: "origin", CommitId: "a1"},
+	}, OutOfSync(ParseBookmarkListOutput(output)))
+}
+
+func TestParseAheadBehind(t *testing.T) {
+	syncs := []BookmarkSync{{Name: "main", Remote: "origin"}, {Name: "dev", Remote: "origin"}}
+	ParseAheadBehind("0+ \n0+ 1- \n0- \n1- \ngarbage 7+\n", syncs)
+	assert.Equal(t, 2, syncs[0].Ahead)
+	assert.Equal(t, 1, syncs[0].Behind)
+	assert.Equal(t, 0, syncs[1].Ahead)
+	assert.Equal(t, 2, syncs[1].Behind)
+}
+
+func TestBookmarksAheadBehind(t *testing.T) {
+	args := BookmarksAheadBehind([]BookmarkSync{{Name: "main", Remote: "origin"}, {Name: "dev", Remote: "upstream"}})
+	assert.Equal(t, `(remote_bookmarks(exact:"main", exact:"origin")..bookmarks(exact:"main")) | (bookmarks(exact:"main")..remote_bookmarks(exact:"main", exact:"origin")) | `+
+		`(remote_bookmarks(exact:"dev", exact:"upstream")..bookmarks(exact:"dev")) | (bookmarks(exact:"dev")..remote_bookmarks(exact:"dev", exact:"upstream"))`, args[2])
+	assert.Contains(t, args[5], `"1- "`)
+}
+
+func TestBookmarkSync_Counts(t *testing.T) {
+	assert.Equal(t, "↑2↓1", BookmarkSync{Ahead: 2, Behind: 1}.Counts())
+	assert.Equal(t, "↓3", BookmarkSync{Behind: 3}.Counts())
+	assert.Equal(t, "", BookmarkSync{}.Counts())
+}
+
+func TestSyncLabel(t *testing.T) {
+	assert.Equal(t, "↑1", SyncLabel([]BookmarkSync{{Remote: "origin", Ahead: 1}}))
+	assert.Equal(t, "origin↑1 upstream↑1↓2", SyncLabel([]BookmarkSync{
+		{Remote: "origin", Ahead: 1},
+		{Remote: "upstream", Ahead: 1, Behind: 2},
+	}))
+}
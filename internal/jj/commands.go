@@ -68,6 +68,12 @@ func New(revisions SelectedRevisions) CommandArgs {
 	return args
 }
 
+// NewInsertBefore creates an empty revision with message between revision and
+// its parents, without editing it
+func NewInsertBefore(revision string, message string) CommandArgs {
+	return []string{"new", "--no-edit", "--insert-before", revision, "-m", message}
+}
+
 func CommitWorkingCopy() CommandArgs {
 	return []string{"commit"}
 }
@@ -117,6 +123,11 @@ func SetDescription(revision string, description string, ignoreImmutable bool) C
 	}
 }
 
+// Reword sets the description of revision to message
+func Reword(revision string, message string) CommandArgs {
+	return []string{"describe", "-r", revision, "-m", message}
+}
+
 func GetDescription(revision string) CommandArgs {
 	return []string{"log", "-r", revision, "--template", "description", "--no-graph", "--ignore-working-copy", "--color", "never", "--quiet"}
 }
@@ -141,6 +152,21 @@ func Diff(revision string, fileName string, extraArgs ...string) CommandArgs {
 	return args
 }
 
+// DiffRange shows the changes between the trees of two revisions, or of a
+// single file in them when fileName is set
+func DiffRange(from string, to string, fileName string) CommandArgs {
+	args := []string{"diff", "--from", from, "--to", to, "--color", "always", "--ignore-working-copy"}
+	if fileName != "" {
+		args = append(args, EscapeFileName(fileName))
+	}
+	return args
+}
+
+// DiffRangeSummary lists the files changed between two revisions
+func DiffRangeSummary(from string, to string) CommandArgs {
+	return []string{"diff", "--from", from, "--to", to, "--summary", "--color", "never", "--ignore-working-copy"}
+}
+
 func Restore(revision string, files []string, interactive bool) CommandArgs {
 	args := []string{"restore", "-c", revision}
 	if interactive {
@@ -159,6 +185,63 @@ func RestoreEvolog(from string, into string) CommandArgs {
 	return args
 }
 
+// RestoreFile restores the contents of file at revision from into revision into
+func RestoreFile(from string, into string, file string) CommandArgs {
+	return []string{"restore", "--from", from, "--into", into, EscapeFileName(file)}
+}
+
+// BuiltinMergeTool is the name jjui registers itself under with `jj resolve`
+const BuiltinMergeTool = "jjui"
+
+// BuiltinMergeToolConfig returns the --config values that make program
+// (the jjui executable) available to `jj resolve` as a merge tool.
+func BuiltinMergeToolConfig(program string) []string {
+	return []string{
+		fmt.Sprintf("merge-tools.%s.program=%s", BuiltinMergeTool, strconv.Quote(program)),
+		fmt.Sprintf(`merge-tools.%s.merge-args=["--merge-tool", "$base", "$left", "$right", "$output"]`, BuiltinMergeTool),
+	}
+}
+
+// ConflictedRevisions lists the revisions with conflicts one per line, with
+// the change id, commit id and description separated by tabs
+func ConflictedRevisions() CommandArgs {
+	const template = `change_id.shortest() ++ "\t" ++ commit_id.shortest() ++ "\t" ++ description.first_line() ++ "\n"`
+	return []string{"log", "-r", "conflicts()", "--no-graph", "--template", template, "--color", "never", "--ignore-working-copy"}
+}
+
+// ResolveList lists the conflicted files of revision
+func ResolveList(revision string) CommandArgs {
+	return []string{"resolve", "--list", "-r", revision, "--color", "never", "--ignore-working-copy"}
+}
+
+func Resolve(revision string, file string, tool string, configs ...string) CommandArgs {
+	args := []string{"resolve", "-r", revision}
+	for _, c := range configs {
+		args = append(args, "--config", c)
+	}
+	if tool != "" {
+		args = append(args, "--tool", tool)
+	}
+	return append(args, EscapeFileName(file))
+}
+
+// FileLog lists the revisions in revset, one per line with the change id,
+// commit id, author, age, the path file was renamed from (if the revision
+// renamed it) and the first line of the description, separated by tabs.
+func FileLog(revset string, file string) CommandArgs {
+	const template = `change_id.shortest(8) ++ "\t" ++ commit_id.shortest(8) ++ "\t" ++ author.name() ++ "\t" ++ author.timestamp().ago() ++ "\t" ++ self.diff().files().filter(|e| e.path() == "%s" && e.status() == "renamed").map(|e| e.source().path()).join("") ++ "\t" ++ description.first_line() ++ "\n"`
+	escaped := strings.ReplaceAll(strings.ReplaceAll(file, "\\", "\\\\"), "\"", "\\\"")
+	return []string{"log", "-r", revset, "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy", "-T", fmt.Sprintf(template, escaped)}
+}
+
+// FileAnnotate lists the lines of file at revision, each prefixed with the
+// change id, commit id, author, timestamp (unix seconds) and line number of
+// the change that last modified it, separated by tabs.
+func FileAnnotate(revision string, file string) CommandArgs {
+	const template = `commit.change_id().shortest(8) ++ "\t" ++ commit.commit_id().shortest(8) ++ "\t" ++ commit.author().name() ++ "\t" ++ commit.author().timestamp().format("%s") ++ "\t" ++ line_number ++ "\t" ++ content`
+	return []string{"file", "annotate", "-r", revision, "--color", "never", "--ignore-working-copy", "-T", template, EscapeFileName(file)}
+}
+
 func Undo() CommandArgs {
 	return []string{"undo"}
 }
@@ -192,6 +275,12 @@ func BookmarkMove(revision string, bookmark string, extraFlags ...string) Comman
 	return args
 }
 
+// BookmarkMoveFrom moves every bookmark pointing at one of the from revisions
+// onto revision
+func BookmarkMoveFrom(from SelectedRevisions, revision string) CommandArgs {
+	return []string{"bookmark", "move", "--from", strings.Join(from.GetIds(), "|"), "--to", revision, "--allow-backwards"}
+}
+
 func BookmarkDelete(name string) CommandArgs {
 	return []string{"bookmark", "delete", exactStringPattern(name)}
 }
@@ -259,10 +348,46 @@ func BookmarkListAll() CommandArgs {
 	return []string{"bookmark", "list", "-a", "--template", allBookmarkTemplate, "--color", "never", "--ignore-working-copy"}
 }
 
+// BookmarkListTracked lists the tracked remote bookmarks one per line, with
+// the name, the remote and the full commit id separated by tabs. The commit id
+// is empty for conflicted bookmarks.
+func BookmarkListTracked() CommandArgs {
+	const template = `if(remote, if(tracked, name ++ "\t" ++ remote ++ "\t" ++ if(normal_target, normal_target.commit_id()) ++ "\n"))`
+	return []string{"bookmark", "list", "-a", "--template", template, "--color", "never", "--ignore-working-copy"}
+}
+
 func TagList() CommandArgs {
 	return []string{"tag", "list", "--template", "name ++ '\n'", "--color", "never", "--ignore-working-copy"}
 }
 
+// TagListAll lists the tags one per line, with the name, and the change id,
+// commit id and description of the tagged commit separated by tabs. The
+// commit fields are empty for conflicted tags.
+func TagListAll() CommandArgs {
+	const template = `name ++ "\t" ++ if(conflict, "conflict") ++ "\t" ++ if(normal_target, normal_target.change_id().shortest() ++ "\t" ++ normal_target.commit_id().shortest() ++ "\t" ++ normal_target.description().first_line(), "\t\t") ++ "\n"`
+	return []string{"tag", "list", "--template", template, "--color", "never", "--ignore-working-copy"}
+}
+
+// TagSet points the tag name at revision, allowMove being needed when the tag
+// already exists
+func TagSet(revision string, name string, allowMove bool) CommandArgs {
+	args := []string{"tag", "set", "-r", revision}
+	if allowMove {
+		args = append(args, "--allow-move")
+	}
+	return append(args, name)
+}
+
+func TagDelete(name string) CommandArgs {
+	return []string{"tag", "delete", exactStringPattern(name)}
+}
+
+// GitPushTag pushes the tag name to remote. jj git push only pushes
+// bookmarks, so this runs git itself, which works in colocated repositories.
+func GitPushTag(remote string, name string) CommandArgs {
+	return []string{"util", "exec", "--", "git", "push", remote, "refs/tags/" + name}
+}
+
 func GitFetch(flags ...string) CommandArgs {
 	args := []string{"git", "fetch"}
 	if flags != nil {
@@ -283,6 +408,86 @@ func GitRemoteList() CommandArgs {
 	return []string{"git", "remote", "list"}
 }
 
+func GitRemoteAdd(name string, url string) CommandArgs {
+	return []string{"git", "remote", "add", name, url}
+}
+
+func GitRemoteRemove(name string) CommandArgs {
+	return []string{"git", "remote", "remove", name}
+}
+
+func GitRemoteRename(oldName string, newName string) CommandArgs {
+	return []string{"git", "remote", "rename", oldName, newName}
+}
+
+func GitRemoteSetUrl(name string, url string) CommandArgs {
+	return []string{"git", "remote", "set-url", name, url}
+}
+
+// WorkspaceList lists the workspaces one per line, with the name, "@" for the
+// current one, and the change id, commit id and description of the
+// working-copy commit separated by tabs
+func WorkspaceList() CommandArgs {
+	const template = `name ++ "\t" ++ if(target.current_working_copy(), "@") ++ "\t" ++ target.change_id().shortest() ++ if(target.divergent(), "/" ++ target.change_offset()) ++ "\t" ++ target.commit_id().shortest() ++ "\t" ++ target.description().first_line() ++ "\n"`
+	return []string{"workspace", "list", "--color", "never", "--quiet", "--ignore-working-copy", "-T", template}
+}
+
+func WorkspaceRoot(name string) CommandArgs {
+	return []string{"workspace", "root", "--name", name, "--ignore-working-copy"}
+}
+
+// WorkspaceSnapshot snapshots the working copy of the workspace at root,
+// failing if the working copy is stale
+func WorkspaceSnapshot(root string) CommandArgs {
+	return withRepository([]string{"log", "-r", "@", "--no-graph", "--color", "never", "--quiet", "-T", `""`}, root)
+}
+
+func WorkspaceAdd(path string, revision string) CommandArgs {
+	return []string{"workspace", "add", "-r", revision, path}
+}
+
+func WorkspaceForget(name string) CommandArgs {
+	return []string{"workspace", "forget", name}
+}
+
+func WorkspaceUpdateStale(root string) CommandArgs {
+	return withRepository([]string{"workspace", "update-stale"}, root)
+}
+
+func WorkspaceRename(root string, name string) CommandArgs {
+	return withRepository([]string{"workspace", "rename", name}, root)
+}
+
+// withRepository runs args in the workspace at root, or in the current one
+// if root is empty
+func withRepository(args []string, root string) CommandArgs {
+	if root == "" {
+		return args
+	}
+	return append(args, "--repository", root)
+}
+
+func SparseList() CommandArgs {
+	return []string{"sparse", "list", "--color", "never", "--quiet"}
+}
+
+// SparseSet adds and removes sparse patterns of the working copy
+func SparseSet(add []string, remove []string) CommandArgs {
+	args := []string{"sparse", "set"}
+	for _, path := range add {
+		args = append(args, "--add", path)
+	}
+	for _, path := range remove {
+		args = append(args, "--remove", path)
+	}
+	return args
+}
+
+// SparseReset makes the working copy include all files again
+func SparseReset() CommandArgs {
+	return []string{"sparse", "reset"}
+}
+
 func Rebase(from SelectedRevisions, sourcePrefix string, to string, target string, skipEmptied bool, ignoreImmutable bool) CommandArgs {
 	args := []string{"rebase"}
 	args = append(args, from.AsPrefixedArgs(sourcePrefix)...)
@@ -365,6 +570,25 @@ func Evolog(revision string) CommandArgs {
 	return []string{"evolog", "-r", revision, "--color", "always", "--quiet", "--ignore-working-copy", "--template", template}
 }
 
+// DivergentCommits lists the commits of divergent changes one per line, with
+// the change id, change offset, commit id, author, age and description
+// separated by tabs
+func DivergentCommits() CommandArgs {
+	const template = `change_id.shortest() ++ "\t" ++ change_offset ++ "\t" ++ commit_id.shortest() ++ "\t" ++ author.name() ++ "\t" ++ committer.timestamp().ago() ++ "\t" ++ description.first_line() ++ "\n"`
+	return []string{"log", "-r", "divergent()", "--no-graph", "--template", template, "--color", "never", "--quiet", "--ignore-working-copy"}
+}
+
+// EvologEntries lists the previous versions of revision one per line, newest
+// first, with the commit id, age and description separated by tabs
+func EvologEntries(revision string, limit int) CommandArgs {
+	const template = `commit.commit_id().shortest() ++ "\t" ++ commit.committer().timestamp().ago() ++ "\t" ++ commit.description().first_line() ++ "\n"`
+	return []string{"evolog", "-r", revision, "--no-graph", "--limit", strconv.Itoa(limit), "--template", template, "--color", "never", "--quiet", "--ignore-working-copy"}
+}
+
+func Interdiff(from string, to string) CommandArgs {
+	return []string{"interdiff", "--from", from, "--to", to, "--color", "always", "--ignore-working-copy"}
+}
+
 func Args(args ...string) CommandArgs {
 	return args
 }
@@ -418,10 +642,30 @@ func OpLog(limit int) CommandArgs {
 	return args
 }
 
+// OpLogEntries lists operations one per line in the format read by ParseOpLog.
+// The graph is kept and a NUL separates it from the fields of each operation.
+func OpLogEntries(limit int) CommandArgs {
+	const template = `"\0" ++ id.short() ++ "\t" ++ user ++ "\t" ++ time.start().format("%Y-%m-%dT%H:%M:%S%z") ++ "\t" ++ description.first_line() ++ "\t" ++ tags.lines().join("\t") ++ "\n"`
+	args := []string{"op", "log", "--color", "never", "--quiet", "--ignore-working-copy", "--template", template}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	return args
+}
+
 func OpShow(operationId string) CommandArgs {
 	return []string{"op", "show", operationId, "--color", "always", "--ignore-working-copy"}
 }
 
+// OpDiff compares the repo at two operations. Colors and the graph are left
+// out when the output is meant to be parsed with ParseOpDiff.
+func OpDiff(from string, to string, color bool) CommandArgs {
+	if color {
+		return []string{"op", "diff", "--from", from, "--to", to, "--color", "always", "--ignore-working-copy"}
+	}
+	return []string{"op", "diff", "--from", from, "--to", to, "--no-graph", "--color", "never", "--ignore-working-copy"}
+}
+
 func OpRestore(operationId string) CommandArgs {
 	return []string{"op", "restore", operationId}
 }
@@ -460,6 +704,35 @@ func FilesInRevision(revision *Commit) CommandArgs {
 	return args
 }
 
+// StackLog lists revset oldest first with the change id, commit id, comma
+// separated parent change ids and the description of each revision separated
+// by tabs. Revisions end with a NUL as descriptions span several lines.
+func StackLog(revset string) CommandArgs {
+	const template = `change_id.shortest(8) ++ "\t" ++ commit_id.shortest(8) ++ "\t" ++ parents.map(|p| p.change_id().shortest(8)).join(",") ++ "\t" ++ description ++ "\0"`
+	return []string{"log", "-r", revset, "--reversed", "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy", "-T", template}
+}
+
+// GraphLog lists the revisions of the revset along with the change ids of
+// their parents, newest first.
+func GraphLog(revset string, limit int) CommandArgs {
+	const template = `change_id.shortest() ++ if(divergent, "/" ++ change_offset) ++ "\t" ++ parents.map(|p| p.change_id().shortest() ++ if(p.divergent(), "/" ++ p.change_offset())).join(",") ++ "\t" ++ description.first_line() ++ "\n"`
+	args := []string{"log", "--no-graph", "--color", "never", "--quiet", "--ignore-working-copy"}
+	if revset != "" {
+		args = append(args, "-r", revset)
+	}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	return append(args, "-T", template)
+}
+
+// GetFullIdsFromRevset is like GetIdsFromRevset but lists whole change ids,
+// which stay unambiguous while new revisions are being created
+func GetFullIdsFromRevset(revset string) CommandArgs {
+	const template = `change_id ++ if(divergent, "/" ++ change_offset) ++ "\n"`
+	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", template}
+}
+
 func GetIdsFromRevset(revset string) CommandArgs {
 	const template = `change_id.shortest() ++ if(divergent, "/" ++ change_offset) ++ "\n"`
 	return []string{"log", "-r", revset, "--color", "never", "--no-graph", "--quiet", "--ignore-working-copy", "--template", template}
@@ -16,3 +16,26 @@ func TestBookmarkPatternCommandsUseExactStringPatterns(t *testing.T) {
 	assert.Equal(t, CommandArgs{"bookmark", "track", `exact:"1.3.63-+-json-length-\"fix\"\\branch"`, "--remote", `exact:"origin+backup"`}, BookmarkTrack(name, remote))
 	assert.Equal(t, CommandArgs{"bookmark", "untrack", `exact:"1.3.63-+-json-length-\"fix\"\\branch"`, "--remote", `exact:"origin+backup"`}, BookmarkUntrack(name, remote))
 }
+
+func TestResolveWithBuiltinMergeTool(t *testing.T) {
+	args := Resolve("abc123", "a.txt", BuiltinMergeTool, BuiltinMergeToolConfig("/usr/bin/jjui")...)
+	assert.Equal(t, CommandArgs{
+		"resolve", "-r", "abc123",
+		"--config", `merge-tools.jjui.program="/usr/bin/jjui"`,
+		"--config", `merge-tools.jjui.merge-args=["--merge-tool", "$base", "$left", "$right", "$output"]`,
+		"--tool", "jjui",
+		`file:"a.txt"`,
+	}, args)
+}
+
+func TestFileAnnotate(t *testing.T) {
+	args := FileAnnotate("abc123", `dir/a "b".txt`)
+	assert.Equal(t, CommandArgs{"file", "annotate", "-r", "abc123"}, args[:4])
+	assert.Equal(t, `file:"dir/a \"b\".txt"`, args[len(args)-1])
+}
+
+func TestFileLogQuotesPathInTemplate(t *testing.T) {
+	args := FileLog("files(\"a.txt\")", `dir/a "b".txt`)
+	assert.Equal(t, CommandArgs{"log", "-r", `files("a.txt")`}, args[:3])
+	assert.Contains(t, args[len(args)-1], `e.path() == "dir/a \"b\".txt"`)
+}
@@ -0,0 +1,45 @@
+package jj
+
+import (
+	"regexp"
+	"strings"
+)
+
+type ConflictedRevision struct {
+	ChangeId    string
+	CommitId    string
+	Description string
+}
+
+// ParseConflictedRevisions parses the output of ConflictedRevisions
+func ParseConflictedRevisions(output string) []ConflictedRevision {
+	var revisions []ConflictedRevision
+	for line := range strings.SplitSeq(output, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		revisions = append(revisions, ConflictedRevision{ChangeId: parts[0], CommitId: parts[1], Description: parts[2]})
+	}
+	return revisions
+}
+
+type ConflictedFile struct {
+	Path string
+	// Description is how jj describes the conflict, like "2-sided conflict"
+	Description string
+}
+
+var resolveListLine = regexp.MustCompile(`^(.*?)\s+(\d+-sided conflict.*)$`)
+
+// ParseResolveListOutput parses the output of ResolveList, where the paths
+// are padded to line up the conflict descriptions
+func ParseResolveListOutput(output string) []ConflictedFile {
+	var files []ConflictedFile
+	for line := range strings.SplitSeq(output, "\n") {
+		if match := resolveListLine.FindStringSubmatch(line); match != nil {
+			files = append(files, ConflictedFile{Path: match[1], Description: match[2]})
+		}
+	}
+	return files
+}
@@ -0,0 +1,26 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConflictedRevisions(t *testing.T) {
+	output := "kx\t1a2b\tfix the parser\nqz\t9f8e\t\n"
+	assert.Equal(t, []ConflictedRevision{
+		{ChangeId: "kx", CommitId: "1a2b", Description: "fix the parser"},
+		{ChangeId: "qz", CommitId: "9f8e", Description: ""},
+	}, ParseConflictedRevisions(output))
+}
+
+func TestParseResolveListOutput(t *testing.T) {
+	output := `src/main.go       2-sided conflict
+docs/read me.md   2-sided conflict including 1 deletion
+build.sh          3-sided conflict including an executable`
+	assert.Equal(t, []ConflictedFile{
+		{Path: "src/main.go", Description: "2-sided conflict"},
+		{Path: "docs/read me.md", Description: "2-sided conflict including 1 deletion"},
+		{Path: "build.sh", Description: "3-sided conflict including an executable"},
+	}, ParseResolveListOutput(output))
+}
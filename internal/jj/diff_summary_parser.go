@@ -0,0 +1,44 @@
+package jj
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+type ChangedFile struct {
+	// Status is the letter jj prints for the change: A, D, M, R or C
+	Status byte
+	// Name is the path as printed, like "src/{a.go => b.go}" for renames
+	Name string
+	// Path is where the file ends up
+	Path string
+}
+
+var renamedPath = regexp.MustCompile(`\{[^}]*? => \s*([^}]*?)\s*\}`)
+
+// RenameTarget returns the path a file ends up at from the way jj prints
+// renames and copies, like "src/{a.go => b.go}"
+func RenameTarget(name string) string {
+	if !strings.Contains(name, "{") {
+		return name
+	}
+	return path.Clean(renamedPath.ReplaceAllString(name, "$1"))
+}
+
+// ParseDiffSummary parses the output of DiffRangeSummary
+func ParseDiffSummary(output string) []ChangedFile {
+	var files []ChangedFile
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < 3 || line[1] != ' ' {
+			continue
+		}
+		file := ChangedFile{Status: line[0], Name: line[2:], Path: line[2:]}
+		if file.Status == 'R' || file.Status == 'C' {
+			file.Path = RenameTarget(file.Path)
+		}
+		files = append(files, file)
+	}
+	return files
+}
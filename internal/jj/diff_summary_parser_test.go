@@ -0,0 +1,17 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDiffSummary(t *testing.T) {
+	output := "M src/main.go\nA docs/read me.md\nD old.txt\nR src/{parser.go => parse/parser.go}\n"
+	assert.Equal(t, []ChangedFile{
+		{Status: 'M', Name: "src/main.go", Path: "src/main.go"},
+		{Status: 'A', Name: "docs/read me.md", Path: "docs/read me.md"},
+		{Status: 'D', Name: "old.txt", Path: "old.txt"},
+		{Status: 'R', Name: "src/{parser.go => parse/parser.go}", Path: "src/parse/parser.go"},
+	}, ParseDiffSummary(output))
+}
@@ -0,0 +1,72 @@
+package jj
+
+import "strings"
+
+type DivergentCommit struct {
+	ChangeId    string
+	Offset      string
+	CommitId    string
+	Author      string
+	Age         string
+	Description string
+}
+
+// Revision is the change id with the offset that tells the commit apart from
+// the other commits of the change
+func (c DivergentCommit) Revision() string {
+	return c.ChangeId + "/" + c.Offset
+}
+
+// DivergentChange is a change with more than one visible commit
+type DivergentChange struct {
+	ChangeId string
+	Commits  []DivergentCommit
+}
+
+// ParseDivergentCommits parses the output of DivergentCommits, grouping the
+// commits by change in the order the changes first appear
+func ParseDivergentCommits(output string) []DivergentChange {
+	var changes []DivergentChange
+	index := map[string]int{}
+	for line := range strings.SplitSeq(output, "\n") {
+		parts := strings.SplitN(line, "\t", 6)
+		if len(parts) < 6 {
+			continue
+		}
+		commit := DivergentCommit{
+			ChangeId:    parts[0],
+			Offset:      parts[1],
+			CommitId:    parts[2],
+			Author:      parts[3],
+			Age:         parts[4],
+			Description: parts[5],
+		}
+		i, ok := index[commit.ChangeId]
+		if !ok {
+			i = len(changes)
+			index[commit.ChangeId] = i
+			changes = append(changes, DivergentChange{ChangeId: commit.ChangeId})
+		}
+		changes[i].Commits = append(changes[i].Commits, commit)
+	}
+	return changes
+}
+
+type EvologEntry struct {
+	CommitId    string
+	Age         string
+	Description string
+}
+
+// ParseEvologEntries parses the output of EvologEntries
+func ParseEvologEntries(output string) []EvologEntry {
+	var entries []EvologEntry
+	for line := range strings.SplitSeq(output, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		entries = append(entries, EvologEntry{CommitId: parts[0], Age: parts[1], Description: parts[2]})
+	}
+	return entries
+}
@@ -0,0 +1,27 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDivergentCommits(t *testing.T) {
+	output := "kx\t0\t1a2b\tAda\t2 hours ago\tfix the parser\n" +
+		"qz\t0\t3c4d\tAda\t1 day ago\t\n" +
+		"kx\t1\t5e6f\tBob\t3 hours ago\tfix the parser properly\n"
+	changes := ParseDivergentCommits(output)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "kx", changes[0].ChangeId)
+	assert.Equal(t, []string{"kx/0", "kx/1"}, []string{changes[0].Commits[0].Revision(), changes[0].Commits[1].Revision()})
+	assert.Equal(t, "Bob", changes[0].Commits[1].Author)
+	assert.Len(t, changes[1].Commits, 1)
+}
+
+func TestParseEvologEntries(t *testing.T) {
+	output := "1a2b\t2 hours ago\tfix the parser\n9f8e\t3 hours ago\t\n"
+	assert.Equal(t, []EvologEntry{
+		{CommitId: "1a2b", Age: "2 hours ago", Description: "fix the parser"},
+		{CommitId: "9f8e", Age: "3 hours ago", Description: ""},
+	}, ParseEvologEntries(output))
+}
@@ -0,0 +1,76 @@
+package jj
+
+import (
+	"strings"
+)
+
+// OpDiffEntry is a commit that appeared or disappeared between two
+// operations, either on its own or as the target of a bookmark or tag
+type OpDiffEntry struct {
+	// Section is the heading the entry is listed under, like "Changed commits"
+	Section string
+	// Ref is the bookmark or tag that moved, empty for commit entries
+	Ref         string
+	Added       bool
+	ChangeId    string
+	CommitId    string
+	Description string
+}
+
+// Absent tells if the entry stands for a ref that did not exist on one side
+func (e OpDiffEntry) Absent() bool {
+	return e.CommitId == ""
+}
+
+// ParseOpDiff parses the output of OpDiff without colors and graph
+func ParseOpDiff(output string) []OpDiffEntry {
+	var entries []OpDiffEntry
+	var section, ref string
+	for line := range strings.SplitSeq(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "From operation:") || strings.HasPrefix(trimmed, "To operation:"):
+			continue
+		case strings.HasPrefix(trimmed, "Changed ") && strings.HasSuffix(trimmed, ":"):
+			section = strings.TrimSuffix(trimmed, ":")
+			ref = ""
+			continue
+		case strings.HasPrefix(trimmed, "+ ") || strings.HasPrefix(trimmed, "- "):
+			if section == "" {
+				continue
+			}
+			entry := parseOpDiffEntry(trimmed[2:])
+			entry.Section = section
+			entry.Ref = ref
+			entry.Added = trimmed[0] == '+'
+			entries = append(entries, entry)
+		case strings.HasSuffix(trimmed, ":") && isRefSection(section):
+			ref = strings.TrimSuffix(trimmed, ":")
+		}
+	}
+	return entries
+}
+
+func isRefSection(section string) bool {
+	return strings.HasSuffix(section, "bookmarks") || strings.HasSuffix(section, "tags")
+}
+
+// parseOpDiffEntry parses what follows the sign of a line: the change id
+// and commit id then the description, or "(absent)". Remote bookmark lines
+// start with whether the bookmark is tracked.
+func parseOpDiffEntry(text string) OpDiffEntry {
+	fields := strings.Fields(text)
+	if len(fields) > 0 && (fields[0] == "tracked" || fields[0] == "untracked") {
+		fields = fields[1:]
+	}
+	if len(fields) < 2 || strings.HasPrefix(fields[0], "(") {
+		return OpDiffEntry{Description: strings.Join(fields, " ")}
+	}
+	return OpDiffEntry{
+		ChangeId:    fields[0],
+		CommitId:    fields[1],
+		Description: strings.Join(fields[2:], " "),
+	}
+}
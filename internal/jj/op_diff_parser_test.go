@@ -0,0 +1,36 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const opDiffOutput = `From operation: 8f4a4c2bd2d8 (2026-10-16 09:12:01) snapshot working copy
+  To operation: 0c9e1b7a3f21 (2026-10-16 09:15:44) rebase commit 4b3a0c1d
+
+Changed commits:
++ qpvuntsm 4b3a0c1d fix the parser
+- qpvuntsm/1 2d3e4f5a (hidden) fix the parser
+
+Changed local bookmarks:
+main:
++ qpvuntsm 4b3a0c1d fix the parser
+- (absent)
+
+Changed remote bookmarks:
+main@origin:
++ tracked qpvuntsm 4b3a0c1d fix the parser
+- untracked (absent)
+`
+
+func TestParseOpDiff(t *testing.T) {
+	assert.Equal(t, []OpDiffEntry{
+		{Section: "Changed commits", Added: true, ChangeId: "qpvuntsm", CommitId: "4b3a0c1d", Description: "fix the parser"},
+		{Section: "Changed commits", Added: false, ChangeId: "qpvuntsm/1", CommitId: "2d3e4f5a", Description: "(hidden) fix the parser"},
+		{Section: "Changed local bookmarks", Ref: "main", Added: true, ChangeId: "qpvuntsm", CommitId: "4b3a0c1d", Description: "fix the parser"},
+		{Section: "Changed local bookmarks", Ref: "main", Added: false, Description: "(absent)"},
+		{Section: "Changed remote bookmarks", Ref: "main@origin", Added: true, ChangeId: "qpvuntsm", CommitId: "4b3a0c1d", Description: "fix the parser"},
+		{Section: "Changed remote bookmarks", Ref: "main@origin", Added: false, Description: "(absent)"},
+	}, ParseOpDiff(opDiffOutput))
+}
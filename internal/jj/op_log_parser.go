@@ -0,0 +1,124 @@
+package jj
+
+import (
+	"strings"
+	"time"
+)
+
+const opLogTimeLayout = "2006-01-02T15:04:05-0700"
+
+// OpLogEntry is an operation as listed by OpLogEntries
+type OpLogEntry struct {
+	Id          string
+	User        string
+	Host        string
+	Time        time.Time
+	Description string
+	// Tags are the "key: value" pairs attached to the operation, like the
+	// args of the command that created it
+	Tags map[string]string
+	// Graph is the part of the op log graph drawn on the line of the
+	// operation, and GraphLines are the graph only lines drawn below it
+	Graph      string
+	GraphLines []string
+	// Current tells if the repo is at this operation
+	Current bool
+}
+
+// IsSnapshot tells if the operation only snapshotted the working copy
+func (e OpLogEntry) IsSnapshot() bool {
+	return strings.HasPrefix(e.Description, "snapshot working copy")
+}
+
+// Kind is the command type of the operation, like "snapshot", "rebase" or
+// "git fetch". It is read from the args tag, falling back to the first word of
+// the description for operations without one.
+func (e OpLogEntry) Kind() string {
+	if e.IsSnapshot() {
+		return "snapshot"
+	}
+	if kind := commandFromArgs(e.Tags["args"]); kind != "" {
+		return kind
+	}
+	if word, _, _ := strings.Cut(e.Description, " "); word != "" {
+		return word
+	}
+	return "unknown"
+}
+
+// ParseOpLog parses the output of OpLogEntries, skipping malformed lines
+func ParseOpLog(output string) []OpLogEntry {
+	var entries []OpLogEntry
+	for line := range strings.SplitSeq(output, "\n") {
+		graph, fields, ok := strings.Cut(line, "\x00")
+		if !ok {
+			if len(entries) > 0 && strings.TrimSpace(line) != "" {
+				last := &entries[len(entries)-1]
+				last.GraphLines = append(last.GraphLines, line)
+			}
+			continue
+		}
+		parts := strings.Split(fields, "\t")
+		if len(parts) < 4 || parts[0] == "" {
+			continue
+		}
+		entry := OpLogEntry{
+			Id:          parts[0],
+			User:        parts[1],
+			Description: parts[3],
+			Tags:        map[string]string{},
+			Graph:       graph,
+			Current:     strings.Contains(graph, "@"),
+		}
+		if at := strings.LastIndex(parts[1], "@"); at >= 0 {
+			entry.User = parts[1][:at]
+			entry.Host = parts[1][at+1:]
+		}
+		if t, err := time.Parse(opLogTimeLayout, parts[2]); err == nil {
+			entry.Time = t
+		}
+		for _, tag := range parts[4:] {
+			if key, value, ok := strings.Cut(tag, ": "); ok {
+				entry.Tags[key] = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// valueFlags are the global flags that take a separate value
+var valueFlags = map[string]bool{
+	"-R": true, "--repository": true, "--at-op": true, "--at-operation": true,
+	"--config": true, "--config-toml": true, "--config-file": true, "--color": true,
+}
+
+// commandGroups are the commands that only make sense with their subcommand
+var commandGroups = map[string]bool{
+	"git": true, "op": true, "operation": true, "bookmark": true, "b": true, "tag": true,
+	"workspace": true, "file": true, "sparse": true, "config": true, "util": true,
+}
+
+// commandFromArgs picks the (sub)command out of an args tag like
+// "jj --repository . git fetch --all-remotes"
+func commandFromArgs(args string) string {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return ""
+	}
+	var words []string
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		if strings.HasPrefix(field, "-") {
+			if valueFlags[field] {
+				i++
+			}
+			continue
+		}
+		words = append(words, field)
+		if len(words) == 2 || !commandGroups[field] {
+			break
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,61 @@
+package jj
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const opLogOutput = "@  \x000c9e1b7a3f21\tci@build-01\t2026-10-16T03:15:44+0000\tfetch from git remote(s) origin\targs: jj --repository /src git fetch --all-remotes\n" +
+	"○  \x008f4a4c2bd2d8\tjane@laptop.local\t2026-10-16T09:12:01+0200\tsnapshot working copy\targs: jj log\n" +
+	"├─╮\n" +
+	"│ ○  \x001a2b3c4d5e6f\tjane@laptop.local\t2026-10-16T09:10:00+0200\trebase commit 4b3a0c1d and 2 descendants\targs: jj rebase -d main\tkind: manual\n" +
+	"○ │  \x00000000000000\troot()@\t1970-01-01T00:00:00+0000\t\n" +
+	"\x00garbage\n"
+
+func TestParseOpLog(t *testing.T) {
+	entries := ParseOpLog(opLogOutput)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "0c9e1b7a3f21", entries[0].Id)
+	assert.Equal(t, "ci", entries[0].User)
+	assert.Equal(t, "build-01", entries[0].Host)
+	assert.True(t, entries[0].Time.Equal(time.Date(2026, 10, 16, 3, 15, 44, 0, time.UTC)))
+	assert.Equal(t, "fetch from git remote(s) origin", entries[0].Description)
+	assert.Equal(t, "@  ", entries[0].Graph)
+	assert.True(t, entries[0].Current)
+
+	assert.Equal(t, "jane", entries[1].User)
+	assert.Equal(t, "laptop.local", entries[1].Host)
+	assert.True(t, entries[1].IsSnapshot())
+	assert.False(t, entries[1].Current)
+	assert.Equal(t, []string{"├─╮"}, entries[1].GraphLines)
+
+	assert.Equal(t, map[string]string{"args": "jj rebase -d main", "kind": "manual"}, entries[2].Tags)
+
+	assert.Equal(t, "│ ○  ", entries[2].Graph)
+
+	assert.Equal(t, "root()", entries[3].User)
+	assert.Empty(t, entries[3].Host)
+}
+
+func TestOpLogEntry_Kind(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    OpLogEntry
+		expected string
+	}{
+		{"snapshot", OpLogEntry{Description: "snapshot working copy", Tags: map[string]string{"args": "jj log"}}, "snapshot"},
+		{"command", OpLogEntry{Description: "rebase commit 4b3a0c1d", Tags: map[string]string{"args": "jj rebase -d main"}}, "rebase"},
+		{"subcommand", OpLogEntry{Description: "fetch from git remote(s) origin", Tags: map[string]string{"args": "jj --repository /src git fetch"}}, "git fetch"},
+		{"no args", OpLogEntry{Description: "import git refs"}, "import"},
+		{"empty", OpLogEntry{}, "unknown"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.entry.Kind())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package jj
+
+import (
+	"regexp"
+	"strings"
+)
+
+type PushAction string
+
+const (
+	PushAdd          PushAction = "add"
+	PushMoveForward  PushAction = "move forward"
+	PushMoveSideways PushAction = "move sideways"
+	PushMoveBackward PushAction = "move backward"
+	PushDelete       PushAction = "delete"
+)
+
+// PushChange is a bookmark that `git push` would change on the remote, with
+// the targets being empty when the bookmark is added or deleted
+type PushChange struct {
+	Bookmark  string
+	Action    PushAction
+	OldTarget string
+	NewTarget string
+}
+
+// Forced returns whether the push moves the bookmark to a commit that does
+// not descend from where it was, which rewrites the remote history
+func (c PushChange) Forced() bool {
+	return c.Action == PushMoveSideways || c.Action == PushMoveBackward
+}
+
+var pushChangePattern = regexp.MustCompile(`^(Add|Move forward|Move sideways|Move backward|Move|Force|Delete) (?:bookmark|branch) (\S+)(?: from (\S+))?(?: to (\S+))?$`)
+
+// ParsePushDryRunOutput parses what `git push --dry-run` reports it would
+// change on the remote
+func ParsePushDryRunOutput(output string) []PushChange {
+	var changes []PushChange
+	for line := range strings.SplitSeq(output, "\n") {
+		match := pushChangePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		action := PushAction(strings.ToLower(match[1]))
+		switch action {
+		case "move":
+			action = PushMoveForward
+		case "force":
+			action = PushMoveSideways
+		}
+		changes = append(changes, PushChange{
+			Bookmark:  match[2],
+			Action:    action,
+			OldTarget: match[3],
+			NewTarget: match[4],
+		})
+	}
+	return changes
+}
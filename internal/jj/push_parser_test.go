@@ -0,0 +1,38 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePushDryRunOutput(t *testing.T) {
+	output := `Creating bookmark push-kxqpnrzs for revision kxqpnrzs
+Changes to push to origin:
+  Move forward bookmark main from 1a2b3c4d to 5e6f7a8b
+  Move sideways bookmark feature from 9a8b7c6d to 0f1e2d3c
+  Add bookmark push-kxqpnrzs to 4b5c6d7e
+  Delete bookmark old from 8f9a0b1c
+Dry-run requested, not pushing.`
+	changes := ParsePushDryRunOutput(output)
+	assert.Equal(t, []PushChange{
+		{Bookmark: "main", Action: PushMoveForward, OldTarget: "1a2b3c4d", NewTarget: "5e6f7a8b"},
+		{Bookmark: "feature", Action: PushMoveSideways, OldTarget: "9a8b7c6d", NewTarget: "0f1e2d3c"},
+		{Bookmark: "push-kxqpnrzs", Action: PushAdd, NewTarget: "4b5c6d7e"},
+		{Bookmark: "old", Action: PushDelete, OldTarget: "8f9a0b1c"},
+	}, changes)
+	assert.False(t, changes[0].Forced())
+	assert.True(t, changes[1].Forced())
+}
+
+func TestParsePushDryRunOutput_OlderBranchWording(t *testing.T) {
+	output := "Branch changes to push to origin:\n  Force branch main from 1a2b3c4d to 5e6f7a8b\n  Move branch dev from 0f1e2d3c to 4b5c6d7e\n"
+	assert.Equal(t, []PushChange{
+		{Bookmark: "main", Action: PushMoveSideways, OldTarget: "1a2b3c4d", NewTarget: "5e6f7a8b"},
+		{Bookmark: "dev", Action: PushMoveForward, OldTarget: "0f1e2d3c", NewTarget: "4b5c6d7e"},
+	}, ParsePushDryRunOutput(output))
+}
+
+func TestParsePushDryRunOutput_NothingChanged(t *testing.T) {
+	assert.Empty(t, ParsePushDryRunOutput("Nothing changed."))
+}
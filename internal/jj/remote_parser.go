@@ -21,3 +21,26 @@ func ParseRemoteListOutput(output string) []string {
 	}
 	return remotes
 }
+
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// ParseRemotes parses the output of GitRemoteList into the remotes along
+// with their URLs, in the order jj lists them
+func ParseRemotes(output string) []Remote {
+	var remotes []Remote
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		remote := Remote{Name: fields[0]}
+		if len(fields) > 1 {
+			remote.URL = fields[1]
+		}
+		remotes = append(remotes, remote)
+	}
+	return remotes
+}
@@ -46,3 +46,13 @@ func TestParseRemoteListOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRemotes(t *testing.T) {
+	output := "origin https://github.com/user/repo.git\n  upstream   git@github.com:upstream/repo.git  \nlocal\n"
+	assert.Equal(t, []Remote{
+		{Name: "origin", URL: "https://github.com/user/repo.git"},
+		{Name: "upstream", URL: "git@github.com:upstream/repo.git"},
+		{Name: "local"},
+	}, ParseRemotes(output))
+	assert.Empty(t, ParseRemotes(""))
+}
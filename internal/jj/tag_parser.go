@@ -0,0 +1,30 @@
+package jj
+
+import "strings"
+
+type Tag struct {
+	Name        string
+	Conflict    bool
+	ChangeId    string
+	CommitId    string
+	Description string
+}
+
+// ParseTagListOutput parses the output of TagListAll
+func ParseTagListOutput(output string) []Tag {
+	var tags []Tag
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) < 5 || fields[0] == "" {
+			continue
+		}
+		tags = append(tags, Tag{
+			Name:        fields[0],
+			Conflict:    fields[1] == "conflict",
+			ChangeId:    fields[2],
+			CommitId:    fields[3],
+			Description: fields[4],
+		})
+	}
+	return tags
+}
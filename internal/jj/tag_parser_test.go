@@ -0,0 +1,22 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagListOutput(t *testing.T) {
+	output := "v1.0.0\t\tkxqpnrzs\t1a2b3c4d\trelease 1.0\n" +
+		"v1.1.0\tconflict\t\t\t\n" +
+		"nightly\t\tyqosqzyt\t5e6f7a8b\t\n"
+	assert.Equal(t, []Tag{
+		{Name: "v1.0.0", ChangeId: "kxqpnrzs", CommitId: "1a2b3c4d", Description: "release 1.0"},
+		{Name: "v1.1.0", Conflict: true},
+		{Name: "nightly", ChangeId: "yqosqzyt", CommitId: "5e6f7a8b"},
+	}, ParseTagListOutput(output))
+}
+
+func TestParseTagListOutput_Empty(t *testing.T) {
+	assert.Empty(t, ParseTagListOutput(""))
+}
@@ -0,0 +1,30 @@
+package jj
+
+import "strings"
+
+type Workspace struct {
+	Name        string
+	Current     bool
+	ChangeId    string
+	CommitId    string
+	Description string
+}
+
+// ParseWorkspaceListOutput parses the output of WorkspaceList
+func ParseWorkspaceListOutput(output string) []Workspace {
+	var workspaces []Workspace
+	for line := range strings.SplitSeq(output, "\n") {
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		workspaces = append(workspaces, Workspace{
+			Name:        fields[0],
+			Current:     fields[1] == "@",
+			ChangeId:    fields[2],
+			CommitId:    fields[3],
+			Description: fields[4],
+		})
+	}
+	return workspaces
+}
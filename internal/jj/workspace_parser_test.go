@@ -0,0 +1,20 @@
+package jj
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkspaceListOutput(t *testing.T) {
+	output := "default\t@\tkxqpnrzs\t1a2b3c4d\tadd workspaces view\n" +
+		"docs\t\tyqosqzyt/1\t5e6f7a8b\t\n"
+	assert.Equal(t, []Workspace{
+		{Name: "default", Current: true, ChangeId: "kxqpnrzs", CommitId: "1a2b3c4d", Description: "add workspaces view"},
+		{Name: "docs", ChangeId: "yqosqzyt/1", CommitId: "5e6f7a8b"},
+	}, ParseWorkspaceListOutput(output))
+}
+
+func TestParseWorkspaceListOutput_Empty(t *testing.T) {
+	assert.Empty(t, ParseWorkspaceListOutput(""))
+}
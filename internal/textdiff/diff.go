@@ -0,0 +1,126 @@
+// Package textdiff computes line based differences between two texts.
+package textdiff
+
+import "strings"
+
+type OpKind int
+
+const (
+	Equal OpKind = iota
+	Delete
+	Insert
+)
+
+// Op is a single step of an edit script turning a into b.
+// For Equal and Delete, OldIndex points at the line in a. For Equal and
+// Insert, NewIndex points at the line in b. Insert ops carry the position in a
+// before which the line is inserted in OldIndex, and Delete ops carry the
+// position in b in NewIndex, so that ops stay ordered on both sides.
+type Op struct {
+	Kind     OpKind
+	OldIndex int
+	NewIndex int
+}
+
+// SplitLines splits text into lines keeping the line terminators, so joining
+// the result gives back the original text, including a missing final newline.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Diff returns the shortest edit script turning a into b using Myers' algorithm.
+func Diff(a, b []string) []Op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]Op, 0, max(len(a), len(b)))
+	for i := range prefix {
+		ops = append(ops, Op{Kind: Equal, OldIndex: i, NewIndex: i})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for i := range suffix {
+		ops = append(ops, Op{Kind: Equal, OldIndex: len(a) - suffix + i, NewIndex: len(b) - suffix + i})
+	}
+	return ops
+}
+
+func myers(a, b []string, oldOffset, newOffset int) []Op {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, offset, n, m, oldOffset, newOffset)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, offset, n, m, oldOffset, newOffset int) []Op {
+	var reversed []Op
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Op{Kind: Equal, OldIndex: oldOffset + x - 1, NewIndex: newOffset + y - 1})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Op{Kind: Insert, OldIndex: oldOffset + x, NewIndex: newOffset + y - 1})
+			} else {
+				reversed = append(reversed, Op{Kind: Delete, OldIndex: oldOffset + x - 1, NewIndex: newOffset + y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	ops := make([]Op, len(reversed))
+	for i, op := range reversed {
+		ops[len(reversed)-1-i] = op
+	}
+	return ops
+}
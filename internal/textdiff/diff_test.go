@@ -0,0 +1,88 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apply rebuilds b from a and the edit script to check the script is valid.
+func apply(a, b []string, ops []Op) []string {
+	var result []string
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			result = append(result, a[op.OldIndex])
+		case Insert:
+			result = append(result, b[op.NewIndex])
+		}
+	}
+	return result
+}
+
+func countChanges(ops []Op) int {
+	count := 0
+	for _, op := range ops {
+		if op.Kind != Equal {
+			count++
+		}
+	}
+	return count
+}
+
+func TestSplitLines(t *testing.T) {
+	assert.Nil(t, SplitLines(""))
+	assert.Equal(t, []string{"a\n", "b\n"}, SplitLines("a\nb\n"))
+	assert.Equal(t, []string{"a\n", "b"}, SplitLines("a\nb"))
+	assert.Equal(t, "a\nb", strings.Join(SplitLines("a\nb"), ""))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		changes int
+	}{
+		{name: "identical", a: "a b c", b: "a b c", changes: 0},
+		{name: "insert in middle", a: "a c", b: "a b c", changes: 1},
+		{name: "delete at start", a: "a b c", b: "b c", changes: 1},
+		{name: "replace", a: "a b c", b: "a x c", changes: 2},
+		{name: "from empty", a: "", b: "a b", changes: 2},
+		{name: "to empty", a: "a b", b: "", changes: 2},
+		{name: "interleaved", a: "a b c a b b a", b: "c b a b a c", changes: 5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := strings.Fields(tc.a)
+			b := strings.Fields(tc.b)
+			ops := Diff(a, b)
+			assert.Equal(t, strings.Join(b, " "), strings.Join(apply(a, b, ops), " "))
+			assert.Equal(t, tc.changes, countChanges(ops))
+		})
+	}
+}
+
+func TestHunks_SplitsDistantChanges(t *testing.T) {
+	a := strings.Fields("1 2 3 4 5 6 7 8 9 10 11 12")
+	b := strings.Fields("1 x 3 4 5 6 7 8 9 10 y 12")
+	hunks := Hunks(Diff(a, b), 2)
+	assert.Len(t, hunks, 2)
+	assert.Equal(t, "@@ -1,4 +1,4 @@", hunks[0].Header())
+	assert.Equal(t, "@@ -9,4 +9,4 @@", hunks[1].Header())
+}
+
+func TestHunks_MergesNearbyChanges(t *testing.T) {
+	a := strings.Fields("1 2 3 4 5 6")
+	b := strings.Fields("1 x 3 4 y 6")
+	hunks := Hunks(Diff(a, b), 2)
+	assert.Len(t, hunks, 1)
+	assert.Equal(t, "@@ -1,6 +1,6 @@", hunks[0].Header())
+}
+
+func TestHunks_PureInsertion(t *testing.T) {
+	hunks := Hunks(Diff(nil, []string{"a"}), 3)
+	assert.Len(t, hunks, 1)
+	assert.Equal(t, "@@ -0,0 +1 @@", hunks[0].Header())
+}
@@ -0,0 +1,88 @@
+package textdiff
+
+import "fmt"
+
+// Hunk is a run of ops containing at least one change, surrounded by up to
+// the requested number of context lines.
+type Hunk struct {
+	// Start and End delimit the hunk in the op list as a half-open range.
+	Start int
+	End   int
+
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+}
+
+// Header returns the unified diff header of the hunk, e.g. "@@ -1,3 +1,4 @@".
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+func hunkRange(start, count int) string {
+	// unified diff line numbers are 1-based, an empty range points at the
+	// line before it
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// Hunks groups the changes in ops into hunks with the given amount of context.
+// Changes closer than twice the context end up in the same hunk.
+func Hunks(ops []Op, context int) []Hunk {
+	var hunks []Hunk
+	i := 0
+	for i < len(ops) {
+		if ops[i].Kind == Equal {
+			i++
+			continue
+		}
+
+		start := max(0, i-context)
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].Kind == Equal {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end = min(len(ops), end+context)
+				break
+			}
+			end = next
+		}
+		hunks = append(hunks, newHunk(ops, start, end))
+		i = end
+	}
+	return hunks
+}
+
+func newHunk(ops []Op, start, end int) Hunk {
+	h := Hunk{
+		Start:    start,
+		End:      end,
+		OldStart: ops[start].OldIndex,
+		NewStart: ops[start].NewIndex,
+	}
+	for _, op := range ops[start:end] {
+		switch op.Kind {
+		case Equal:
+			h.OldLines++
+			h.NewLines++
+		case Delete:
+			h.OldLines++
+		case Insert:
+			h.NewLines++
+		}
+	}
+	return h
+}
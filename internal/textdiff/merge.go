@@ -0,0 +1,82 @@
+package textdiff
+
+import "slices"
+
+// MergeChunk is a region of a three-way merge. Chunks without conflict carry
+// the merged lines in Lines, conflicting chunks carry the lines of each side.
+type MergeChunk struct {
+	Conflict bool
+	Lines    []string
+	Base     []string
+	Left     []string
+	Right    []string
+}
+
+// Merge3 merges the changes made to base by left and right. Regions changed
+// by only one side, or changed identically by both, are resolved
+// automatically; the rest are returned as conflicts.
+func Merge3(base, left, right []string) []MergeChunk {
+	matchLeft := matches(base, Diff(base, left))
+	matchRight := matches(base, Diff(base, right))
+
+	var chunks []MergeChunk
+	lastStable := -1
+	appendStable := func(line string) {
+		if lastStable >= 0 && lastStable == len(chunks)-1 {
+			chunks[lastStable].Lines = append(chunks[lastStable].Lines, line)
+			chunks[lastStable].Base = append(chunks[lastStable].Base, line)
+			return
+		}
+		chunks = append(chunks, MergeChunk{Lines: []string{line}, Base: []string{line}})
+		lastStable = len(chunks) - 1
+	}
+
+	i, l, r := 0, 0, 0
+	for {
+		// the next base line kept by both sides ends the unstable region
+		j := i
+		for j < len(base) && (matchLeft[j] < 0 || matchRight[j] < 0) {
+			j++
+		}
+		lj, rj := len(left), len(right)
+		if j < len(base) {
+			lj, rj = matchLeft[j], matchRight[j]
+		}
+
+		if i < j || l < lj || r < rj {
+			chunks = append(chunks, mergeRegion(base[i:j], left[l:lj], right[r:rj]))
+		}
+		if j == len(base) {
+			break
+		}
+		appendStable(base[j])
+		i, l, r = j+1, lj+1, rj+1
+	}
+	return chunks
+}
+
+func matches(base []string, ops []Op) []int {
+	match := make([]int, len(base))
+	for i := range match {
+		match[i] = -1
+	}
+	for _, op := range ops {
+		if op.Kind == Equal {
+			match[op.OldIndex] = op.NewIndex
+		}
+	}
+	return match
+}
+
+func mergeRegion(base, left, right []string) MergeChunk {
+	chunk := MergeChunk{Base: base, Left: left, Right: right}
+	switch {
+	case slices.Equal(left, base):
+		chunk.Lines = right
+	case slices.Equal(right, base), slices.Equal(left, right):
+		chunk.Lines = left
+	default:
+		chunk.Conflict = true
+	}
+	return chunk
+}
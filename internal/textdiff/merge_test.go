@@ -0,0 +1,53 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3_NonOverlappingChangesAreResolved(t *testing.T) {
+	base := strings.Fields("a b c d e")
+	left := strings.Fields("A b c d e")
+	right := strings.Fields("a b c d E")
+
+	chunks := Merge3(base, left, right)
+	var merged []string
+	for _, chunk := range chunks {
+		assert.False(t, chunk.Conflict)
+		merged = append(merged, chunk.Lines...)
+	}
+	assert.Equal(t, "A b c d E", strings.Join(merged, " "))
+}
+
+func TestMerge3_OverlappingChangesConflict(t *testing.T) {
+	base := strings.Fields("a b c")
+	left := strings.Fields("a x c")
+	right := strings.Fields("a y c")
+
+	chunks := Merge3(base, left, right)
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, []string{"a"}, chunks[0].Lines)
+	assert.True(t, chunks[1].Conflict)
+	assert.Equal(t, []string{"b"}, chunks[1].Base)
+	assert.Equal(t, []string{"x"}, chunks[1].Left)
+	assert.Equal(t, []string{"y"}, chunks[1].Right)
+	assert.Equal(t, []string{"c"}, chunks[2].Lines)
+}
+
+func TestMerge3_IdenticalChangesAreResolved(t *testing.T) {
+	base := strings.Fields("a b")
+	left := strings.Fields("a x")
+	right := strings.Fields("a x")
+
+	for _, chunk := range Merge3(base, left, right) {
+		assert.False(t, chunk.Conflict)
+	}
+}
+
+func TestMerge3_ConflictingAdditionsToEmptyBase(t *testing.T) {
+	chunks := Merge3(nil, []string{"left"}, []string{"right"})
+	assert.Len(t, chunks, 1)
+	assert.True(t, chunks[0].Conflict)
+}
@@ -8,6 +8,13 @@ import (
 )
 
 var builtInActionScopes = map[string][]string{
+	"annotate.cancel":                            {"annotate"},
+	"annotate.diff":                              {"annotate"},
+	"annotate.jump_to_revision":                  {"annotate"},
+	"annotate.move_down":                         {"annotate"},
+	"annotate.move_up":                           {"annotate"},
+	"annotate.page_down":                         {"annotate"},
+	"annotate.page_up":                           {"annotate"},
 	"bookmarks.apply":                            {"bookmarks"},
 	"bookmarks.bookmark_delete":                  {"bookmarks"},
 	"bookmarks.bookmark_forget":                  {"bookmarks"},
@@ -17,6 +24,7 @@ var builtInActionScopes = map[string][]string{
 	"bookmarks.cancel":                           {"bookmarks"},
 	"bookmarks.cycle_remotes":                    {"bookmarks"},
 	"bookmarks.cycle_remotes_back":               {"bookmarks"},
+	"bookmarks.cycle_sync_filter":                {"bookmarks"},
 	"bookmarks.filter":                           {"bookmarks"},
 	"bookmarks.move_down":                        {"bookmarks"},
 	"bookmarks.move_up":                          {"bookmarks"},
@@ -31,21 +39,94 @@ var builtInActionScopes = map[string][]string{
 	"command_history.delete_selected":            {"command_history"},
 	"command_history.move_down":                  {"command_history"},
 	"command_history.move_up":                    {"command_history"},
+	"compare_files.cancel":                       {"compare_files"},
+	"compare_files.diff":                         {"compare_files"},
+	"compare_files.move_down":                    {"compare_files"},
+	"compare_files.move_up":                      {"compare_files"},
+	"compare_files.swap":                         {"compare_files"},
+	"conflicts.apply":                            {"conflicts"},
+	"conflicts.cancel":                           {"conflicts"},
+	"conflicts.diff":                             {"conflicts"},
+	"conflicts.move_down":                        {"conflicts"},
+	"conflicts.move_up":                          {"conflicts"},
+	"conflicts.resolve":                          {"conflicts"},
+	"conflicts.take_ours":                        {"conflicts"},
+	"conflicts.take_theirs":                      {"conflicts"},
 	"diff.half_page_down":                        {"diff"},
 	"diff.half_page_up":                          {"diff"},
 	"diff.left":                                  {"diff"},
 	"diff.move_bottom":                           {"diff"},
 	"diff.move_top":                              {"diff"},
+	"diff.next_file":                             {"diff"},
+	"diff.next_hunk":                             {"diff"},
 	"diff.page_down":                             {"diff"},
 	"diff.page_up":                               {"diff"},
+	"diff.prev_file":                             {"diff"},
+	"diff.prev_hunk":                             {"diff"},
+	"diff.quick_search.clear":                    {"diff.quick_search"},
+	"diff.quick_search.next":                     {"diff.quick_search"},
+	"diff.quick_search.prev":                     {"diff.quick_search"},
 	"diff.right":                                 {"diff"},
 	"diff.scroll_down":                           {"diff"},
 	"diff.scroll_up":                             {"diff"},
 	"diff.show":                                  {"diff"},
+	"diff.toggle_collapse":                       {"diff"},
+	"diff.toggle_collapse_all":                   {"diff"},
+	"diff.toggle_file_list":                      {"diff"},
+	"diff.toggle_side_by_side":                   {"diff"},
 	"diff.toggle_wrap":                           {"diff"},
+	"diff_editor.apply":                          {"diff_editor"},
+	"diff_editor.cancel":                         {"diff_editor"},
+	"diff_editor.move_down":                      {"diff_editor"},
+	"diff_editor.move_up":                        {"diff_editor"},
+	"diff_editor.next_file":                      {"diff_editor"},
+	"diff_editor.next_hunk":                      {"diff_editor"},
+	"diff_editor.page_down":                      {"diff_editor"},
+	"diff_editor.page_up":                        {"diff_editor"},
+	"diff_editor.prev_file":                      {"diff_editor"},
+	"diff_editor.prev_hunk":                      {"diff_editor"},
+	"diff_editor.toggle":                         {"diff_editor"},
+	"diff_editor.toggle_all":                     {"diff_editor"},
+	"diff_editor.toggle_collapse":                {"diff_editor"},
+	"divergence.cancel":                          {"divergence"},
+	"divergence.interdiff":                       {"divergence"},
+	"divergence.keep":                            {"divergence"},
+	"divergence.move_down":                       {"divergence"},
+	"divergence.move_up":                         {"divergence"},
+	"divergence.next_version":                    {"divergence"},
+	"divergence.prev_version":                    {"divergence"},
+	"divergence.squash":                          {"divergence"},
+	"explode.apply":                              {"explode"},
+	"explode.by_directory":                       {"explode"},
+	"explode.by_file":                            {"explode"},
+	"explode.cancel":                             {"explode"},
+	"explode.edit_template":                      {"explode"},
+	"explode.group_1":                            {"explode"},
+	"explode.group_2":                            {"explode"},
+	"explode.group_3":                            {"explode"},
+	"explode.group_4":                            {"explode"},
+	"explode.group_5":                            {"explode"},
+	"explode.group_6":                            {"explode"},
+	"explode.group_7":                            {"explode"},
+	"explode.group_8":                            {"explode"},
+	"explode.group_9":                            {"explode"},
+	"explode.move_down":                          {"explode"},
+	"explode.move_up":                            {"explode"},
+	"explode.template.apply":                     {"explode.template"},
+	"explode.template.cancel":                    {"explode.template"},
+	"explode.ungroup":                            {"explode"},
+	"file_history.cancel":                        {"file_history"},
+	"file_history.diff_scroll_down":              {"file_history"},
+	"file_history.diff_scroll_up":                {"file_history"},
+	"file_history.move_down":                     {"file_history"},
+	"file_history.move_up":                       {"file_history"},
+	"file_history.page_down":                     {"file_history"},
+	"file_history.page_up":                       {"file_history"},
+	"file_history.restore":                       {"file_history"},
 	"file_search.apply":                          {"file_search"},
 	"file_search.cancel":                         {"file_search"},
 	"file_search.edit":                           {"file_search"},
+	"file_search.file_history":                   {"file_search"},
 	"file_search.move_down":                      {"file_search"},
 	"file_search.move_up":                        {"file_search"},
 	"file_search.page_down":                      {"file_search"},
@@ -64,7 +145,25 @@ var builtInActionScopes = map[string][]string{
 	"git.page_down":                              {"git"},
 	"git.page_up":                                {"git"},
 	"git.push":                                   {"git"},
+	"git.push_preview.apply":                     {"git.push_preview"},
+	"git.push_preview.cancel":                    {"git.push_preview"},
+	"git.push_preview.next":                      {"git.push_preview"},
+	"git.push_preview.prev":                      {"git.push_preview"},
 	"git.quit":                                   {"git"},
+	"git.remotes":                                {"git"},
+	"git.remotes.add":                            {"git.remotes"},
+	"git.remotes.cancel":                         {"git.remotes"},
+	"git.remotes.confirmation.apply":             {"git.remotes.confirmation"},
+	"git.remotes.confirmation.cancel":            {"git.remotes.confirmation"},
+	"git.remotes.confirmation.next":              {"git.remotes.confirmation"},
+	"git.remotes.confirmation.prev":              {"git.remotes.confirmation"},
+	"git.remotes.input.apply":                    {"git.remotes.input"},
+	"git.remotes.input.cancel":                   {"git.remotes.input"},
+	"git.remotes.move_down":                      {"git.remotes"},
+	"git.remotes.move_up":                        {"git.remotes"},
+	"git.remotes.remove":                         {"git.remotes"},
+	"git.remotes.rename":                         {"git.remotes"},
+	"git.remotes.set_url":                        {"git.remotes"},
 	"help.apply":                                 {"help"},
 	"help.cancel":                                {"help"},
 	"help.close":                                 {"help"},
@@ -77,8 +176,32 @@ var builtInActionScopes = map[string][]string{
 	"help.scroll_up":                             {"help"},
 	"input.apply":                                {"input"},
 	"input.cancel":                               {"input"},
+	"merge_tool.apply":                           {"merge_tool"},
+	"merge_tool.cancel":                          {"merge_tool"},
+	"merge_tool.edit":                            {"merge_tool"},
+	"merge_tool.edit.accept":                     {"merge_tool.edit"},
+	"merge_tool.edit.cancel":                     {"merge_tool.edit"},
+	"merge_tool.next_conflict":                   {"merge_tool"},
+	"merge_tool.prev_conflict":                   {"merge_tool"},
+	"merge_tool.scroll_down":                     {"merge_tool"},
+	"merge_tool.scroll_up":                       {"merge_tool"},
+	"merge_tool.take_base":                       {"merge_tool"},
+	"merge_tool.take_both":                       {"merge_tool"},
+	"merge_tool.take_left":                       {"merge_tool"},
+	"merge_tool.take_right":                      {"merge_tool"},
+	"merge_tool.unresolve":                       {"merge_tool"},
+	"op_diff.apply":                              {"op_diff"},
+	"op_diff.cancel":                             {"op_diff"},
+	"op_diff.diff":                               {"op_diff"},
+	"op_diff.move_down":                          {"op_diff"},
+	"op_diff.move_up":                            {"op_diff"},
 	"oplog.close":                                {"oplog"},
+	"oplog.compare":                              {"oplog"},
 	"oplog.diff":                                 {"oplog"},
+	"oplog.filter":                               {"oplog"},
+	"oplog.filter.apply":                         {"oplog.filter"},
+	"oplog.filter.cancel":                        {"oplog.filter"},
+	"oplog.mark":                                 {"oplog"},
 	"oplog.move_down":                            {"oplog"},
 	"oplog.move_up":                              {"oplog"},
 	"oplog.page_down":                            {"oplog"},
@@ -89,8 +212,22 @@ var builtInActionScopes = map[string][]string{
 	"oplog.quit":                                 {"oplog"},
 	"oplog.restore":                              {"oplog"},
 	"oplog.revert":                               {"oplog"},
+	"oplog.toggle_collapse":                      {"oplog"},
 	"password.apply":                             {"password"},
 	"password.cancel":                            {"password"},
+	"progress.cancel":                            {"progress"},
+	"rebase_plan.apply":                          {"rebase_plan"},
+	"rebase_plan.cancel":                         {"rebase_plan"},
+	"rebase_plan.drop":                           {"rebase_plan"},
+	"rebase_plan.move_down":                      {"rebase_plan"},
+	"rebase_plan.move_entry_down":                {"rebase_plan"},
+	"rebase_plan.move_entry_up":                  {"rebase_plan"},
+	"rebase_plan.move_up":                        {"rebase_plan"},
+	"rebase_plan.pick":                           {"rebase_plan"},
+	"rebase_plan.reword":                         {"rebase_plan"},
+	"rebase_plan.reword.accept":                  {"rebase_plan.reword"},
+	"rebase_plan.reword.cancel":                  {"rebase_plan.reword"},
+	"rebase_plan.squash":                         {"rebase_plan"},
 	"redo.apply":                                 {"redo"},
 	"redo.cancel":                                {"redo"},
 	"redo.next":                                  {"redo"},
@@ -113,8 +250,12 @@ var builtInActionScopes = map[string][]string{
 	"revisions.apply":                            {"revisions"},
 	"revisions.cancel":                           {"revisions"},
 	"revisions.commit":                           {"revisions"},
+	"revisions.compare.apply":                    {"revisions.compare"},
+	"revisions.compare.cancel":                   {"revisions.compare"},
+	"revisions.compare.target_picker":            {"revisions.compare"},
 	"revisions.describe":                         {"revisions"},
 	"revisions.details.absorb":                   {"revisions.details"},
+	"revisions.details.annotate":                 {"revisions.details"},
 	"revisions.details.cancel":                   {"revisions.details"},
 	"revisions.details.confirmation.apply":       {"revisions.details.confirmation"},
 	"revisions.details.confirmation.cancel":      {"revisions.details.confirmation"},
@@ -122,12 +263,15 @@ var builtInActionScopes = map[string][]string{
 	"revisions.details.confirmation.next":        {"revisions.details.confirmation"},
 	"revisions.details.confirmation.prev":        {"revisions.details.confirmation"},
 	"revisions.details.diff":                     {"revisions.details"},
+	"revisions.details.explode":                  {"revisions.details"},
+	"revisions.details.file_history":             {"revisions.details"},
 	"revisions.details.move_down":                {"revisions.details"},
 	"revisions.details.move_up":                  {"revisions.details"},
 	"revisions.details.page_down":                {"revisions.details"},
 	"revisions.details.page_up":                  {"revisions.details"},
 	"revisions.details.quit":                     {"revisions.details"},
 	"revisions.details.refresh":                  {"revisions.details"},
+	"revisions.details.resolve":                  {"revisions.details"},
 	"revisions.details.restore":                  {"revisions.details"},
 	"revisions.details.revisions_changing_file":  {"revisions.details"},
 	"revisions.details.select_file":              {"revisions.details"},
@@ -148,6 +292,8 @@ var builtInActionScopes = map[string][]string{
 	"revisions.evolog.apply":                     {"revisions.evolog"},
 	"revisions.evolog.cancel":                    {"revisions.evolog"},
 	"revisions.evolog.diff":                      {"revisions.evolog"},
+	"revisions.evolog.interdiff":                 {"revisions.evolog"},
+	"revisions.evolog.mark":                      {"revisions.evolog"},
 	"revisions.evolog.move_down":                 {"revisions.evolog"},
 	"revisions.evolog.move_up":                   {"revisions.evolog"},
 	"revisions.evolog.page_down":                 {"revisions.evolog"},
@@ -169,6 +315,7 @@ var builtInActionScopes = map[string][]string{
 	"revisions.new":                              {"revisions"},
 	"revisions.open_abandon":                     {"revisions"},
 	"revisions.open_absorb":                      {"revisions"},
+	"revisions.open_compare":                     {"revisions"},
 	"revisions.open_details":                     {"revisions"},
 	"revisions.open_duplicate":                   {"revisions"},
 	"revisions.open_evolog":                      {"revisions"},
@@ -177,6 +324,7 @@ var builtInActionScopes = map[string][]string{
 	"revisions.open_revert":                      {"revisions"},
 	"revisions.open_set_bookmark":                {"revisions"},
 	"revisions.open_set_parents":                 {"revisions"},
+	"revisions.open_set_tag":                     {"revisions"},
 	"revisions.open_squash":                      {"revisions"},
 	"revisions.page_down":                        {"revisions"},
 	"revisions.page_up":                          {"revisions"},
@@ -209,6 +357,10 @@ var builtInActionScopes = map[string][]string{
 	"revisions.set_parents.cancel":               {"revisions.set_parents"},
 	"revisions.set_parents.jump_to_working_copy": {"revisions.set_parents"},
 	"revisions.set_parents.toggle_select":        {"revisions.set_parents"},
+	"revisions.set_tag.apply":                    {"revisions.set_tag"},
+	"revisions.set_tag.autocomplete":             {"revisions.set_tag"},
+	"revisions.set_tag.autocomplete_back":        {"revisions.set_tag"},
+	"revisions.set_tag.cancel":                   {"revisions.set_tag"},
 	"revisions.split":                            {"revisions"},
 	"revisions.split_parallel":                   {"revisions"},
 	"revisions.squash.ace_jump":                  {"revisions.squash"},
@@ -237,6 +389,18 @@ var builtInActionScopes = map[string][]string{
 	"revset.move_up":                             {"revset"},
 	"revset.reset":                               {"revset"},
 	"revset.set":                                 {"revset"},
+	"sparse.add":                                 {"sparse"},
+	"sparse.apply":                               {"sparse"},
+	"sparse.cancel":                              {"sparse"},
+	"sparse.move_down":                           {"sparse"},
+	"sparse.move_up":                             {"sparse"},
+	"sparse.picker.apply":                        {"sparse.picker"},
+	"sparse.picker.cancel":                       {"sparse.picker"},
+	"sparse.picker.force_apply":                  {"sparse.picker"},
+	"sparse.picker.move_down":                    {"sparse.picker"},
+	"sparse.picker.move_up":                      {"sparse.picker"},
+	"sparse.remove":                              {"sparse"},
+	"sparse.reset":                               {"sparse"},
 	"status.input.apply":                         {"status.input"},
 	"status.input.autocomplete":                  {"status.input"},
 	"status.input.cancel":                        {"status.input"},
@@ -244,6 +408,12 @@ var builtInActionScopes = map[string][]string{
 	"status.input.move_up":                       {"status.input"},
 	"status.input.page_down":                     {"status.input"},
 	"status.input.page_up":                       {"status.input"},
+	"tags.apply":                                 {"tags"},
+	"tags.cancel":                                {"tags"},
+	"tags.delete":                                {"tags"},
+	"tags.move":                                  {"tags"},
+	"tags.move_down":                             {"tags"},
+	"tags.move_up":                               {"tags"},
 	"ui.cancel":                                  {"ui"},
 	"ui.exec_jj":                                 {"ui"},
 	"ui.exec_shell":                              {"ui"},
@@ -251,12 +421,18 @@ var builtInActionScopes = map[string][]string{
 	"ui.file_search_toggle":                      {"ui"},
 	"ui.open_bookmarks":                          {"ui"},
 	"ui.open_command_history":                    {"ui"},
+	"ui.open_conflicts":                          {"ui"},
+	"ui.open_divergence":                         {"ui"},
 	"ui.open_git":                                {"ui"},
 	"ui.open_help":                               {"ui"},
 	"ui.open_oplog":                              {"ui"},
+	"ui.open_rebase_plan":                        {"ui"},
 	"ui.open_redo":                               {"ui"},
 	"ui.open_revset":                             {"ui"},
+	"ui.open_sparse":                             {"ui"},
+	"ui.open_tags":                               {"ui"},
 	"ui.open_undo":                               {"ui"},
+	"ui.open_workspaces":                         {"ui"},
 	"ui.preview.show":                            {"ui.preview"},
 	"ui.preview_expand":                          {"ui"},
 	"ui.preview_half_page_down":                  {"ui"},
@@ -266,13 +442,31 @@ var builtInActionScopes = map[string][]string{
 	"ui.preview_shrink":                          {"ui"},
 	"ui.preview_toggle":                          {"ui"},
 	"ui.preview_toggle_bottom":                   {"ui"},
+	"ui.preview_toggle_side_by_side":             {"ui"},
 	"ui.quick_search":                            {"ui"},
 	"ui.quit":                                    {"ui"},
+	"ui.show_compare":                            {"ui"},
+	"ui.show_op_diff":                            {"ui"},
 	"ui.suspend":                                 {"ui"},
 	"undo.apply":                                 {"undo"},
 	"undo.cancel":                                {"undo"},
 	"undo.next":                                  {"undo"},
 	"undo.prev":                                  {"undo"},
+	"workspaces.add":                             {"workspaces"},
+	"workspaces.apply":                           {"workspaces"},
+	"workspaces.cancel":                          {"workspaces"},
+	"workspaces.check_stale":                     {"workspaces"},
+	"workspaces.confirmation.apply":              {"workspaces.confirmation"},
+	"workspaces.confirmation.cancel":             {"workspaces.confirmation"},
+	"workspaces.confirmation.next":               {"workspaces.confirmation"},
+	"workspaces.confirmation.prev":               {"workspaces.confirmation"},
+	"workspaces.forget":                          {"workspaces"},
+	"workspaces.input.apply":                     {"workspaces.input"},
+	"workspaces.input.cancel":                    {"workspaces.input"},
+	"workspaces.move_down":                       {"workspaces"},
+	"workspaces.move_up":                         {"workspaces"},
+	"workspaces.rename":                          {"workspaces"},
+	"workspaces.update_stale":                    {"workspaces"},
 }
 
 var builtInActionArgSchemas = map[string]map[string]string{
@@ -336,6 +530,14 @@ var builtInActionArgSchemas = map[string]map[string]string{
 	"ui.preview.show": {
 		"content": "string",
 	},
+	"ui.show_compare": {
+		"from": "string",
+		"to":   "string",
+	},
+	"ui.show_op_diff": {
+		"from": "string",
+		"to":   "string",
+	},
 }
 
 var builtInActionRequiredArgs = map[string][]string{
@@ -347,6 +549,8 @@ var builtInActionRequiredArgs = map[string][]string{
 	"revisions.revert.set_target":    {"target"},
 	"revset.set":                     {"value"},
 	"ui.preview.show":                {"content"},
+	"ui.show_compare":                {"from", "to"},
+	"ui.show_op_diff":                {"from", "to"},
 }
 
 func ActionScopes(action string) []string {
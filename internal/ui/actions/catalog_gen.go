@@ -8,44 +8,89 @@ import (
 )
 
 const (
-	ScopeBookmarks           = "bookmarks"
-	ScopeChoose              = "choose"
-	ScopeCommandHistory      = "command_history"
-	ScopeDiff                = "diff"
-	ScopeFileSearch          = "file_search"
-	ScopeGit                 = "git"
-	ScopeHelp                = "help"
-	ScopeInput               = "input"
-	ScopeOplog               = "oplog"
-	ScopeOplogQuickSearch    = "oplog.quick_search"
-	ScopePassword            = "password"
-	ScopeRedo                = "redo"
-	ScopeRevisions           = "revisions"
-	ScopeAbandon             = "revisions.abandon"
-	ScopeAbsorb              = "revisions.absorb"
-	ScopeAceJump             = "revisions.ace_jump"
-	ScopeDetails             = "revisions.details"
-	ScopeDetailsConfirmation = "revisions.details.confirmation"
-	ScopeDuplicate           = "revisions.duplicate"
-	ScopeEvolog              = "revisions.evolog"
-	ScopeInlineDescribe      = "revisions.inline_describe"
-	ScopeQuickSearch         = "revisions.quick_search"
-	ScopeQuickSearchInput    = "revisions.quick_search.input"
-	ScopeRebase              = "revisions.rebase"
-	ScopeRevert              = "revisions.revert"
-	ScopeSetBookmark         = "revisions.set_bookmark"
-	ScopeSetParents          = "revisions.set_parents"
-	ScopeSquash              = "revisions.squash"
-	ScopeTargetPicker        = "revisions.target_picker"
-	ScopeRevset              = "revset"
-	ScopeStatusInput         = "status.input"
-	ScopeUi                  = "ui"
-	ScopeUiPreview           = "ui.preview"
-	ScopeUndo                = "undo"
+	ScopeAnnotate               = "annotate"
+	ScopeBookmarks              = "bookmarks"
+	ScopeChoose                 = "choose"
+	ScopeCommandHistory         = "command_history"
+	ScopeCompareFiles           = "compare_files"
+	ScopeConflicts              = "conflicts"
+	ScopeDiff                   = "diff"
+	ScopeDiffQuickSearch        = "diff.quick_search"
+	ScopeDiffEditor             = "diff_editor"
+	ScopeDivergence             = "divergence"
+	ScopeExplode                = "explode"
+	ScopeExplodeTemplate        = "explode.template"
+	ScopeFileHistory            = "file_history"
+	ScopeFileSearch             = "file_search"
+	ScopeGit                    = "git"
+	ScopeGitPushPreview         = "git.push_preview"
+	ScopeGitRemotes             = "git.remotes"
+	ScopeGitRemotesConfirmation = "git.remotes.confirmation"
+	ScopeGitRemotesInput        = "git.remotes.input"
+	ScopeHelp                   = "help"
+	ScopeInput                  = "input"
+	ScopeMergeTool              = "merge_tool"
+	ScopeMergeToolEdit          = "merge_tool.edit"
+	ScopeOpDiff                 = "op_diff"
+	ScopeOplog                  = "oplog"
+	ScopeOplogFilter            = "oplog.filter"
+	ScopeOplogQuickSearch       = "oplog.quick_search"
+	ScopePassword               = "password"
+	ScopeProgress               = "progress"
+	ScopeRebasePlan             = "rebase_plan"
+	ScopeRebasePlanReword       = "rebase_plan.reword"
+	ScopeRedo                   = "redo"
+	ScopeRevisions              = "revisions"
+	ScopeAbandon                = "revisions.abandon"
+	ScopeAbsorb                 = "revisions.absorb"
+	ScopeAceJump                = "revisions.ace_jump"
+	ScopeCompare                = "revisions.compare"
+	ScopeDetails                = "revisions.details"
+	ScopeDetailsConfirmation    = "revisions.details.confirmation"
+	ScopeDuplicate              = "revisions.duplicate"
+	ScopeEvolog                 = "revisions.evolog"
+	ScopeInlineDescribe         = "revisions.inline_describe"
+	ScopeQuickSearch            = "revisions.quick_search"
+	ScopeQuickSearchInput       = "revisions.quick_search.input"
+	ScopeRebase                 = "revisions.rebase"
+	ScopeRevert                 = "revisions.revert"
+	ScopeSetBookmark            = "revisions.set_bookmark"
+	ScopeSetParents             = "revisions.set_parents"
+	ScopeSetTag                 = "revisions.set_tag"
+	ScopeSquash                 = "revisions.squash"
+	ScopeTargetPicker           = "revisions.target_picker"
+	ScopeRevset                 = "revset"
+	ScopeSparse                 = "sparse"
+	ScopeSparsePicker           = "sparse.picker"
+	ScopeStatusInput            = "status.input"
+	ScopeTags                   = "tags"
+	ScopeUi                     = "ui"
+	ScopeUiPreview              = "ui.preview"
+	ScopeUndo                   = "undo"
+	ScopeWorkspaces             = "workspaces"
+	ScopeWorkspacesConfirmation = "workspaces.confirmation"
+	ScopeWorkspacesInput        = "workspaces.input"
 )
 
 func ResolveIntent(scope string, action keybindings.Action, args map[string]any) (intents.Intent, bool) {
 	switch scope {
+	case ScopeAnnotate:
+		switch action {
+		case keybindings.Action("annotate.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("annotate.diff"):
+			return intents.AnnotateShowDiff{}, true
+		case keybindings.Action("annotate.jump_to_revision"):
+			return intents.AnnotateJump{}, true
+		case keybindings.Action("annotate.move_down"):
+			return intents.AnnotateNavigate{Delta: 1}, true
+		case keybindings.Action("annotate.move_up"):
+			return intents.AnnotateNavigate{Delta: -1}, true
+		case keybindings.Action("annotate.page_down"):
+			return intents.AnnotateNavigate{Delta: 1, IsPage: true}, true
+		case keybindings.Action("annotate.page_up"):
+			return intents.AnnotateNavigate{Delta: -1, IsPage: true}, true
+		}
 	case ScopeBookmarks:
 		switch action {
 		case keybindings.Action("bookmarks.apply"):
@@ -66,6 +111,8 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.BookmarksCycleRemotes{Delta: 1}, true
 		case keybindings.Action("bookmarks.cycle_remotes_back"):
 			return intents.BookmarksCycleRemotes{Delta: -1}, true
+		case keybindings.Action("bookmarks.cycle_sync_filter"):
+			return intents.BookmarksCycleSyncFilter{}, true
 		case keybindings.Action("bookmarks.filter"):
 			return intents.BookmarksOpenFilter{}, true
 		case keybindings.Action("bookmarks.move_down"):
@@ -101,6 +148,38 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("command_history.move_up"):
 			return intents.CommandHistoryNavigate{Delta: -1}, true
 		}
+	case ScopeCompareFiles:
+		switch action {
+		case keybindings.Action("compare_files.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("compare_files.diff"):
+			return intents.CompareDiff{}, true
+		case keybindings.Action("compare_files.move_down"):
+			return intents.CompareNavigate{Delta: 1}, true
+		case keybindings.Action("compare_files.move_up"):
+			return intents.CompareNavigate{Delta: -1}, true
+		case keybindings.Action("compare_files.swap"):
+			return intents.CompareSwap{}, true
+		}
+	case ScopeConflicts:
+		switch action {
+		case keybindings.Action("conflicts.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("conflicts.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("conflicts.diff"):
+			return intents.ConflictsDiff{}, true
+		case keybindings.Action("conflicts.move_down"):
+			return intents.ConflictsNavigate{Delta: 1}, true
+		case keybindings.Action("conflicts.move_up"):
+			return intents.ConflictsNavigate{Delta: -1}, true
+		case keybindings.Action("conflicts.resolve"):
+			return intents.ConflictsResolve{Tool: intents.ConflictsResolveMerge}, true
+		case keybindings.Action("conflicts.take_ours"):
+			return intents.ConflictsResolve{Tool: intents.ConflictsResolveOurs}, true
+		case keybindings.Action("conflicts.take_theirs"):
+			return intents.ConflictsResolve{Tool: intents.ConflictsResolveTheirs}, true
+		}
 	case ScopeDiff:
 		switch action {
 		case keybindings.Action("diff.half_page_down"):
@@ -113,10 +192,18 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.DiffScroll{Kind: intents.DiffMoveBottom}, true
 		case keybindings.Action("diff.move_top"):
 			return intents.DiffScroll{Kind: intents.DiffMoveTop}, true
+		case keybindings.Action("diff.next_file"):
+			return intents.DiffJumpFile{Delta: 1}, true
+		case keybindings.Action("diff.next_hunk"):
+			return intents.DiffJumpHunk{Delta: 1}, true
 		case keybindings.Action("diff.page_down"):
 			return intents.DiffScroll{Kind: intents.DiffPageDown}, true
 		case keybindings.Action("diff.page_up"):
 			return intents.DiffScroll{Kind: intents.DiffPageUp}, true
+		case keybindings.Action("diff.prev_file"):
+			return intents.DiffJumpFile{Delta: -1}, true
+		case keybindings.Action("diff.prev_hunk"):
+			return intents.DiffJumpHunk{Delta: -1}, true
 		case keybindings.Action("diff.right"):
 			return intents.DiffScrollHorizontal{Kind: intents.DiffScrollRight}, true
 		case keybindings.Action("diff.scroll_down"):
@@ -125,9 +212,137 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.DiffScroll{Kind: intents.DiffScrollUp}, true
 		case keybindings.Action("diff.show"):
 			return intents.DiffShow{Content: actionargs.StringArg(args, "content", "")}, true
+		case keybindings.Action("diff.toggle_collapse"):
+			return intents.DiffToggleCollapse{}, true
+		case keybindings.Action("diff.toggle_collapse_all"):
+			return intents.DiffToggleCollapse{All: true}, true
+		case keybindings.Action("diff.toggle_file_list"):
+			return intents.DiffToggleFileList{}, true
+		case keybindings.Action("diff.toggle_side_by_side"):
+			return intents.DiffToggleSideBySide{}, true
 		case keybindings.Action("diff.toggle_wrap"):
 			return intents.DiffToggleWrap{}, true
 		}
+	case ScopeDiffQuickSearch:
+		switch action {
+		case keybindings.Action("diff.quick_search.clear"):
+			return intents.DiffQuickSearchClear{}, true
+		case keybindings.Action("diff.quick_search.next"):
+			return intents.QuickSearchCycle{}, true
+		case keybindings.Action("diff.quick_search.prev"):
+			return intents.QuickSearchCycle{Reverse: true}, true
+		}
+	case ScopeDiffEditor:
+		switch action {
+		case keybindings.Action("diff_editor.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("diff_editor.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("diff_editor.move_down"):
+			return intents.DiffEditorNavigate{Delta: 1}, true
+		case keybindings.Action("diff_editor.move_up"):
+			return intents.DiffEditorNavigate{Delta: -1}, true
+		case keybindings.Action("diff_editor.next_file"):
+			return intents.DiffEditorJumpFile{Delta: 1}, true
+		case keybindings.Action("diff_editor.next_hunk"):
+			return intents.DiffEditorJumpHunk{Delta: 1}, true
+		case keybindings.Action("diff_editor.page_down"):
+			return intents.DiffEditorNavigate{Delta: 1, IsPage: true}, true
+		case keybindings.Action("diff_editor.page_up"):
+			return intents.DiffEditorNavigate{Delta: -1, IsPage: true}, true
+		case keybindings.Action("diff_editor.prev_file"):
+			return intents.DiffEditorJumpFile{Delta: -1}, true
+		case keybindings.Action("diff_editor.prev_hunk"):
+			return intents.DiffEditorJumpHunk{Delta: -1}, true
+		case keybindings.Action("diff_editor.toggle"):
+			return intents.DiffEditorToggle{}, true
+		case keybindings.Action("diff_editor.toggle_all"):
+			return intents.DiffEditorToggleAll{}, true
+		case keybindings.Action("diff_editor.toggle_collapse"):
+			return intents.DiffEditorToggleCollapse{}, true
+		}
+	case ScopeDivergence:
+		switch action {
+		case keybindings.Action("divergence.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("divergence.interdiff"):
+			return intents.DivergenceInterdiff{}, true
+		case keybindings.Action("divergence.keep"):
+			return intents.DivergenceKeep{}, true
+		case keybindings.Action("divergence.move_down"):
+			return intents.DivergenceNavigate{Delta: 1}, true
+		case keybindings.Action("divergence.move_up"):
+			return intents.DivergenceNavigate{Delta: -1}, true
+		case keybindings.Action("divergence.next_version"):
+			return intents.DivergenceCycleVersion{Delta: 1}, true
+		case keybindings.Action("divergence.prev_version"):
+			return intents.DivergenceCycleVersion{Delta: -1}, true
+		case keybindings.Action("divergence.squash"):
+			return intents.DivergenceKeep{Squash: true}, true
+		}
+	case ScopeExplode:
+		switch action {
+		case keybindings.Action("explode.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("explode.by_directory"):
+			return intents.ExplodeSetMode{Mode: intents.ExplodeByDirectory}, true
+		case keybindings.Action("explode.by_file"):
+			return intents.ExplodeSetMode{Mode: intents.ExplodeByFile}, true
+		case keybindings.Action("explode.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("explode.edit_template"):
+			return intents.ExplodeEditTemplate{}, true
+		case keybindings.Action("explode.group_1"):
+			return intents.ExplodeAssignGroup{Group: 1}, true
+		case keybindings.Action("explode.group_2"):
+			return intents.ExplodeAssignGroup{Group: 2}, true
+		case keybindings.Action("explode.group_3"):
+			return intents.ExplodeAssignGroup{Group: 3}, true
+		case keybindings.Action("explode.group_4"):
+			return intents.ExplodeAssignGroup{Group: 4}, true
+		case keybindings.Action("explode.group_5"):
+			return intents.ExplodeAssignGroup{Group: 5}, true
+		case keybindings.Action("explode.group_6"):
+			return intents.ExplodeAssignGroup{Group: 6}, true
+		case keybindings.Action("explode.group_7"):
+			return intents.ExplodeAssignGroup{Group: 7}, true
+		case keybindings.Action("explode.group_8"):
+			return intents.ExplodeAssignGroup{Group: 8}, true
+		case keybindings.Action("explode.group_9"):
+			return intents.ExplodeAssignGroup{Group: 9}, true
+		case keybindings.Action("explode.move_down"):
+			return intents.ExplodeNavigate{Delta: 1}, true
+		case keybindings.Action("explode.move_up"):
+			return intents.ExplodeNavigate{Delta: -1}, true
+		case keybindings.Action("explode.ungroup"):
+			return intents.ExplodeAssignGroup{Group: 0}, true
+		}
+	case ScopeExplodeTemplate:
+		switch action {
+		case keybindings.Action("explode.template.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("explode.template.cancel"):
+			return intents.Cancel{}, true
+		}
+	case ScopeFileHistory:
+		switch action {
+		case keybindings.Action("file_history.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("file_history.diff_scroll_down"):
+			return intents.FileHistoryScrollDiff{Delta: 10}, true
+		case keybindings.Action("file_history.diff_scroll_up"):
+			return intents.FileHistoryScrollDiff{Delta: -10}, true
+		case keybindings.Action("file_history.move_down"):
+			return intents.FileHistoryNavigate{Delta: 1}, true
+		case keybindings.Action("file_history.move_up"):
+			return intents.FileHistoryNavigate{Delta: -1}, true
+		case keybindings.Action("file_history.page_down"):
+			return intents.FileHistoryNavigate{Delta: 1, IsPage: true}, true
+		case keybindings.Action("file_history.page_up"):
+			return intents.FileHistoryNavigate{Delta: -1, IsPage: true}, true
+		case keybindings.Action("file_history.restore"):
+			return intents.FileHistoryRestore{}, true
+		}
 	case ScopeFileSearch:
 		switch action {
 		case keybindings.Action("file_search.apply"):
@@ -136,6 +351,8 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.Cancel{}, true
 		case keybindings.Action("file_search.edit"):
 			return intents.FileSearchEdit{}, true
+		case keybindings.Action("file_search.file_history"):
+			return intents.FileSearchHistory{}, true
 		case keybindings.Action("file_search.move_down"):
 			return intents.FileSearchNavigate{Delta: -1}, true
 		case keybindings.Action("file_search.move_up"):
@@ -177,6 +394,54 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.GitFilter{Kind: intents.GitFilterPush}, true
 		case keybindings.Action("git.quit"):
 			return intents.Quit{}, true
+		case keybindings.Action("git.remotes"):
+			return intents.GitOpenRemotes{}, true
+		}
+	case ScopeGitPushPreview:
+		switch action {
+		case keybindings.Action("git.push_preview.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("git.push_preview.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("git.push_preview.next"):
+			return intents.OptionSelect{Delta: 1}, true
+		case keybindings.Action("git.push_preview.prev"):
+			return intents.OptionSelect{Delta: -1}, true
+		}
+	case ScopeGitRemotes:
+		switch action {
+		case keybindings.Action("git.remotes.add"):
+			return intents.GitRemotesAdd{}, true
+		case keybindings.Action("git.remotes.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("git.remotes.move_down"):
+			return intents.GitRemotesNavigate{Delta: 1}, true
+		case keybindings.Action("git.remotes.move_up"):
+			return intents.GitRemotesNavigate{Delta: -1}, true
+		case keybindings.Action("git.remotes.remove"):
+			return intents.GitRemotesRemove{}, true
+		case keybindings.Action("git.remotes.rename"):
+			return intents.GitRemotesRename{}, true
+		case keybindings.Action("git.remotes.set_url"):
+			return intents.GitRemotesSetUrl{}, true
+		}
+	case ScopeGitRemotesConfirmation:
+		switch action {
+		case keybindings.Action("git.remotes.confirmation.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("git.remotes.confirmation.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("git.remotes.confirmation.next"):
+			return intents.OptionSelect{Delta: 1}, true
+		case keybindings.Action("git.remotes.confirmation.prev"):
+			return intents.OptionSelect{Delta: -1}, true
+		}
+	case ScopeGitRemotesInput:
+		switch action {
+		case keybindings.Action("git.remotes.input.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("git.remotes.input.cancel"):
+			return intents.Cancel{}, true
 		}
 	case ScopeHelp:
 		switch action {
@@ -208,12 +473,65 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("input.cancel"):
 			return intents.Cancel{}, true
 		}
+	case ScopeMergeTool:
+		switch action {
+		case keybindings.Action("merge_tool.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("merge_tool.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("merge_tool.edit"):
+			return intents.MergeToolEdit{}, true
+		case keybindings.Action("merge_tool.next_conflict"):
+			return intents.MergeToolNavigate{Delta: 1}, true
+		case keybindings.Action("merge_tool.prev_conflict"):
+			return intents.MergeToolNavigate{Delta: -1}, true
+		case keybindings.Action("merge_tool.scroll_down"):
+			return intents.MergeToolScroll{Delta: 1}, true
+		case keybindings.Action("merge_tool.scroll_up"):
+			return intents.MergeToolScroll{Delta: -1}, true
+		case keybindings.Action("merge_tool.take_base"):
+			return intents.MergeToolPick{Side: intents.MergeToolSideBase}, true
+		case keybindings.Action("merge_tool.take_both"):
+			return intents.MergeToolPick{Side: intents.MergeToolSideBoth}, true
+		case keybindings.Action("merge_tool.take_left"):
+			return intents.MergeToolPick{Side: intents.MergeToolSideLeft}, true
+		case keybindings.Action("merge_tool.take_right"):
+			return intents.MergeToolPick{Side: intents.MergeToolSideRight}, true
+		case keybindings.Action("merge_tool.unresolve"):
+			return intents.MergeToolPick{Side: intents.MergeToolSideNone}, true
+		}
+	case ScopeMergeToolEdit:
+		switch action {
+		case keybindings.Action("merge_tool.edit.accept"):
+			return intents.MergeToolEditAccept{}, true
+		case keybindings.Action("merge_tool.edit.cancel"):
+			return intents.Cancel{}, true
+		}
+	case ScopeOpDiff:
+		switch action {
+		case keybindings.Action("op_diff.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("op_diff.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("op_diff.diff"):
+			return intents.OpDiffShowDiff{}, true
+		case keybindings.Action("op_diff.move_down"):
+			return intents.OpDiffNavigate{Delta: 1}, true
+		case keybindings.Action("op_diff.move_up"):
+			return intents.OpDiffNavigate{Delta: -1}, true
+		}
 	case ScopeOplog:
 		switch action {
 		case keybindings.Action("oplog.close"):
 			return intents.OpLogClose{}, true
+		case keybindings.Action("oplog.compare"):
+			return intents.OpLogCompare{}, true
 		case keybindings.Action("oplog.diff"):
 			return intents.OpLogShowDiff{}, true
+		case keybindings.Action("oplog.filter"):
+			return intents.OpLogOpenFilter{}, true
+		case keybindings.Action("oplog.mark"):
+			return intents.OpLogMark{}, true
 		case keybindings.Action("oplog.move_down"):
 			return intents.OpLogNavigate{Delta: 1}, true
 		case keybindings.Action("oplog.move_up"):
@@ -228,6 +546,15 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.OpLogRestore{}, true
 		case keybindings.Action("oplog.revert"):
 			return intents.OpLogRevert{}, true
+		case keybindings.Action("oplog.toggle_collapse"):
+			return intents.OpLogToggleCollapse{}, true
+		}
+	case ScopeOplogFilter:
+		switch action {
+		case keybindings.Action("oplog.filter.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("oplog.filter.cancel"):
+			return intents.Cancel{}, true
 		}
 	case ScopeOplogQuickSearch:
 		switch action {
@@ -245,6 +572,41 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("password.cancel"):
 			return intents.Cancel{}, true
 		}
+	case ScopeProgress:
+		switch action {
+		case keybindings.Action("progress.cancel"):
+			return intents.Cancel{}, true
+		}
+	case ScopeRebasePlan:
+		switch action {
+		case keybindings.Action("rebase_plan.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("rebase_plan.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("rebase_plan.drop"):
+			return intents.RebasePlanSetAction{Action: intents.RebasePlanDrop}, true
+		case keybindings.Action("rebase_plan.move_down"):
+			return intents.RebasePlanNavigate{Delta: 1}, true
+		case keybindings.Action("rebase_plan.move_entry_down"):
+			return intents.RebasePlanMove{Delta: 1}, true
+		case keybindings.Action("rebase_plan.move_entry_up"):
+			return intents.RebasePlanMove{Delta: -1}, true
+		case keybindings.Action("rebase_plan.move_up"):
+			return intents.RebasePlanNavigate{Delta: -1}, true
+		case keybindings.Action("rebase_plan.pick"):
+			return intents.RebasePlanSetAction{Action: intents.RebasePlanPick}, true
+		case keybindings.Action("rebase_plan.reword"):
+			return intents.RebasePlanSetAction{Action: intents.RebasePlanReword}, true
+		case keybindings.Action("rebase_plan.squash"):
+			return intents.RebasePlanSetAction{Action: intents.RebasePlanSquash}, true
+		}
+	case ScopeRebasePlanReword:
+		switch action {
+		case keybindings.Action("rebase_plan.reword.accept"):
+			return intents.RebasePlanRewordAccept{}, true
+		case keybindings.Action("rebase_plan.reword.cancel"):
+			return intents.Cancel{}, true
+		}
 	case ScopeRedo:
 		switch action {
 		case keybindings.Action("redo.apply"):
@@ -294,6 +656,8 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.OpenAbandon{}, true
 		case keybindings.Action("revisions.open_absorb"):
 			return intents.OpenAbsorb{}, true
+		case keybindings.Action("revisions.open_compare"):
+			return intents.OpenCompare{}, true
 		case keybindings.Action("revisions.open_details"):
 			return intents.OpenDetails{}, true
 		case keybindings.Action("revisions.open_duplicate"):
@@ -310,6 +674,8 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.OpenSetBookmark{Value: actionargs.StringArg(args, "value", "")}, true
 		case keybindings.Action("revisions.open_set_parents"):
 			return intents.OpenSetParents{}, true
+		case keybindings.Action("revisions.open_set_tag"):
+			return intents.OpenSetTag{}, true
 		case keybindings.Action("revisions.open_squash"):
 			return intents.OpenSquash{}, true
 		case keybindings.Action("revisions.page_down"):
@@ -362,14 +728,29 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("revisions.ace_jump.cancel"):
 			return intents.Cancel{}, true
 		}
+	case ScopeCompare:
+		switch action {
+		case keybindings.Action("revisions.compare.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("revisions.compare.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("revisions.compare.target_picker"):
+			return intents.CompareOpenTargetPicker{}, true
+		}
 	case ScopeDetails:
 		switch action {
 		case keybindings.Action("revisions.details.absorb"):
 			return intents.DetailsAbsorb{}, true
+		case keybindings.Action("revisions.details.annotate"):
+			return intents.DetailsAnnotate{}, true
 		case keybindings.Action("revisions.details.cancel"):
 			return intents.DetailsClose{}, true
 		case keybindings.Action("revisions.details.diff"):
 			return intents.DetailsDiff{}, true
+		case keybindings.Action("revisions.details.explode"):
+			return intents.DetailsExplode{}, true
+		case keybindings.Action("revisions.details.file_history"):
+			return intents.DetailsFileHistory{}, true
 		case keybindings.Action("revisions.details.move_down"):
 			return intents.DetailsNavigate{Delta: 1}, true
 		case keybindings.Action("revisions.details.move_up"):
@@ -382,6 +763,8 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.Quit{}, true
 		case keybindings.Action("revisions.details.refresh"):
 			return intents.Refresh{}, true
+		case keybindings.Action("revisions.details.resolve"):
+			return intents.DetailsResolve{}, true
 		case keybindings.Action("revisions.details.restore"):
 			return intents.DetailsRestore{}, true
 		case keybindings.Action("revisions.details.revisions_changing_file"):
@@ -435,6 +818,10 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.Cancel{}, true
 		case keybindings.Action("revisions.evolog.diff"):
 			return intents.EvologDiff{}, true
+		case keybindings.Action("revisions.evolog.interdiff"):
+			return intents.EvologInterdiff{}, true
+		case keybindings.Action("revisions.evolog.mark"):
+			return intents.EvologMark{}, true
 		case keybindings.Action("revisions.evolog.move_down"):
 			return intents.EvologNavigate{Delta: 1}, true
 		case keybindings.Action("revisions.evolog.move_up"):
@@ -535,6 +922,17 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("revisions.set_parents.toggle_select"):
 			return intents.SetParentsToggleSelect{}, true
 		}
+	case ScopeSetTag:
+		switch action {
+		case keybindings.Action("revisions.set_tag.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("revisions.set_tag.autocomplete"):
+			return intents.AutocompleteCycle{}, true
+		case keybindings.Action("revisions.set_tag.autocomplete_back"):
+			return intents.AutocompleteCycle{Reverse: true}, true
+		case keybindings.Action("revisions.set_tag.cancel"):
+			return intents.Cancel{}, true
+		}
 	case ScopeSquash:
 		switch action {
 		case keybindings.Action("revisions.squash.ace_jump"):
@@ -594,6 +992,36 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("revset.set"):
 			return intents.Set{Value: actionargs.StringArg(args, "value", "")}, true
 		}
+	case ScopeSparse:
+		switch action {
+		case keybindings.Action("sparse.add"):
+			return intents.SparseAdd{}, true
+		case keybindings.Action("sparse.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("sparse.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("sparse.move_down"):
+			return intents.SparseNavigate{Delta: 1}, true
+		case keybindings.Action("sparse.move_up"):
+			return intents.SparseNavigate{Delta: -1}, true
+		case keybindings.Action("sparse.remove"):
+			return intents.SparseRemove{}, true
+		case keybindings.Action("sparse.reset"):
+			return intents.SparseReset{}, true
+		}
+	case ScopeSparsePicker:
+		switch action {
+		case keybindings.Action("sparse.picker.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("sparse.picker.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("sparse.picker.force_apply"):
+			return intents.Apply{Force: true}, true
+		case keybindings.Action("sparse.picker.move_down"):
+			return intents.SparseNavigate{Delta: 1}, true
+		case keybindings.Action("sparse.picker.move_up"):
+			return intents.SparseNavigate{Delta: -1}, true
+		}
 	case ScopeStatusInput:
 		switch action {
 		case keybindings.Action("status.input.apply"):
@@ -611,6 +1039,21 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("status.input.page_up"):
 			return intents.SuggestNavigate{Delta: 1}, true
 		}
+	case ScopeTags:
+		switch action {
+		case keybindings.Action("tags.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("tags.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("tags.delete"):
+			return intents.TagsDelete{}, true
+		case keybindings.Action("tags.move"):
+			return intents.TagsMove{}, true
+		case keybindings.Action("tags.move_down"):
+			return intents.TagsNavigate{Delta: 1}, true
+		case keybindings.Action("tags.move_up"):
+			return intents.TagsNavigate{Delta: -1}, true
+		}
 	case ScopeUi:
 		switch action {
 		case keybindings.Action("ui.cancel"):
@@ -627,18 +1070,30 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.OpenBookmarks{}, true
 		case keybindings.Action("ui.open_command_history"):
 			return intents.CommandHistoryToggle{}, true
+		case keybindings.Action("ui.open_conflicts"):
+			return intents.OpenConflicts{}, true
+		case keybindings.Action("ui.open_divergence"):
+			return intents.OpenDivergence{}, true
 		case keybindings.Action("ui.open_git"):
 			return intents.OpenGit{}, true
 		case keybindings.Action("ui.open_help"):
 			return intents.OpenHelp{}, true
 		case keybindings.Action("ui.open_oplog"):
 			return intents.OpLogOpen{}, true
+		case keybindings.Action("ui.open_rebase_plan"):
+			return intents.OpenRebasePlan{}, true
 		case keybindings.Action("ui.open_redo"):
 			return intents.Redo{}, true
 		case keybindings.Action("ui.open_revset"):
 			return intents.Edit{Clear: true}, true
+		case keybindings.Action("ui.open_sparse"):
+			return intents.OpenSparse{}, true
+		case keybindings.Action("ui.open_tags"):
+			return intents.OpenTags{}, true
 		case keybindings.Action("ui.open_undo"):
 			return intents.Undo{}, true
+		case keybindings.Action("ui.open_workspaces"):
+			return intents.OpenWorkspaces{}, true
 		case keybindings.Action("ui.preview_expand"):
 			return intents.PreviewExpand{}, true
 		case keybindings.Action("ui.preview_half_page_down"):
@@ -655,10 +1110,16 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 			return intents.PreviewToggle{}, true
 		case keybindings.Action("ui.preview_toggle_bottom"):
 			return intents.PreviewToggleBottom{}, true
+		case keybindings.Action("ui.preview_toggle_side_by_side"):
+			return intents.PreviewToggleSideBySide{}, true
 		case keybindings.Action("ui.quick_search"):
 			return intents.QuickSearch{}, true
 		case keybindings.Action("ui.quit"):
 			return intents.Quit{}, true
+		case keybindings.Action("ui.show_compare"):
+			return intents.ShowCompare{From: actionargs.StringArg(args, "from", ""), To: actionargs.StringArg(args, "to", "")}, true
+		case keybindings.Action("ui.show_op_diff"):
+			return intents.ShowOpDiff{From: actionargs.StringArg(args, "from", ""), To: actionargs.StringArg(args, "to", "")}, true
 		case keybindings.Action("ui.suspend"):
 			return intents.Suspend{}, true
 		}
@@ -678,6 +1139,45 @@ func ResolveIntent(scope string, action keybindings.Action, args map[string]any)
 		case keybindings.Action("undo.prev"):
 			return intents.OptionSelect{Delta: -1}, true
 		}
+	case ScopeWorkspaces:
+		switch action {
+		case keybindings.Action("workspaces.add"):
+			return intents.WorkspacesAdd{}, true
+		case keybindings.Action("workspaces.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("workspaces.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("workspaces.check_stale"):
+			return intents.WorkspacesCheckStale{}, true
+		case keybindings.Action("workspaces.forget"):
+			return intents.WorkspacesForget{}, true
+		case keybindings.Action("workspaces.move_down"):
+			return intents.WorkspacesNavigate{Delta: 1}, true
+		case keybindings.Action("workspaces.move_up"):
+			return intents.WorkspacesNavigate{Delta: -1}, true
+		case keybindings.Action("workspaces.rename"):
+			return intents.WorkspacesRename{}, true
+		case keybindings.Action("workspaces.update_stale"):
+			return intents.WorkspacesUpdateStale{}, true
+		}
+	case ScopeWorkspacesConfirmation:
+		switch action {
+		case keybindings.Action("workspaces.confirmation.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("workspaces.confirmation.cancel"):
+			return intents.Cancel{}, true
+		case keybindings.Action("workspaces.confirmation.next"):
+			return intents.OptionSelect{Delta: 1}, true
+		case keybindings.Action("workspaces.confirmation.prev"):
+			return intents.OptionSelect{Delta: -1}, true
+		}
+	case ScopeWorkspacesInput:
+		switch action {
+		case keybindings.Action("workspaces.input.apply"):
+			return intents.Apply{}, true
+		case keybindings.Action("workspaces.input.cancel"):
+			return intents.Cancel{}, true
+		}
 	}
 	return nil, false
 }
@@ -0,0 +1,310 @@
+package annotate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+// line is a line of the annotated file along with the change that last
+// modified it
+type line struct {
+	changeId string
+	commitId string
+	author   string
+	time     time.Time
+	number   int
+	content  string
+}
+
+type loadedMsg struct {
+	lines []line
+	err   error
+}
+
+type lineClickMsg struct {
+	Index int
+}
+
+type lineScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m lineScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type Model struct {
+	context             *context.MainContext
+	changeId            string
+	file                string
+	lines               []line
+	loaded              bool
+	err                 error
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+	now                 func() time.Time
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeAnnotate,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.FileAnnotate(m.changeId, m.file))
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	return loadedMsg{lines: parseLines(string(output))}
+}
+
+// parseLines parses the output of jj.FileAnnotate
+func parseLines(output string) []line {
+	var lines []line
+	for _, row := range strings.SplitAfter(output, "\n") {
+		fields := strings.SplitN(row, "\t", 6)
+		if len(fields) < 6 {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[3], 10, 64)
+		number, _ := strconv.Atoi(fields[4])
+		lines = append(lines, line{
+			changeId: fields[0],
+			commitId: fields[1],
+			author:   fields[2],
+			time:     time.Unix(seconds, 0),
+			number:   number,
+			content:  strings.TrimRight(fields[5], "\r\n"),
+		})
+	}
+	return lines
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.lines = msg.lines
+		m.err = msg.err
+		m.cursor = 0
+		m.ensureCursorVisible = true
+	case lineClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.lines) {
+			m.cursor = msg.Index
+		}
+	case lineScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.AnnotateNavigate:
+		m.navigate(intent.Delta, intent.IsPage)
+		return nil, true
+	case intents.AnnotateJump:
+		current, ok := m.current()
+		if !ok {
+			return nil, true
+		}
+		return intents.Invoke(intents.JumpToRevision{ChangeID: current.changeId, FallbackID: current.commitId}), true
+	case intents.AnnotateShowDiff:
+		current, ok := m.current()
+		if !ok {
+			return nil, true
+		}
+		return func() tea.Msg {
+			output, _ := m.context.RunCommandImmediate(jj.Diff(current.commitId, ""))
+			return intents.DiffShow{Content: string(output)}
+		}, true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) current() (line, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.lines) {
+		return line{}, false
+	}
+	return m.lines[m.cursor], true
+}
+
+func (m *Model) navigate(delta int, page bool) {
+	if len(m.lines) == 0 {
+		return
+	}
+	if page {
+		span := max(m.listRenderer.GetLastRowIndex()-m.listRenderer.GetFirstRowIndex()-1, 1)
+		delta *= span
+	}
+	m.cursor = max(min(m.cursor+delta, len(m.lines)-1), 0)
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	frame := box.Inset(1)
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("annotate text")
+	borderStyle := common.DefaultPalette.GetBorder("annotate border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, listBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled(fmt.Sprintf("annotate %s @ %s", m.file, m.changeId), common.DefaultPalette.Get("annotate title")).
+		Done()
+	_, listBox = listBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(listBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+	case !m.loaded:
+		dl.AddDraw(listBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+	default:
+		m.renderLines(dl, listBox)
+	}
+}
+
+func (m *Model) renderLines(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 || len(m.lines) == 0 {
+		return
+	}
+	now := m.now()
+	numberWidth := len(strconv.Itoa(m.lines[len(m.lines)-1].number))
+	authorWidth := 0
+	for _, l := range m.lines {
+		authorWidth = max(authorWidth, ansi.StringWidth(l.author))
+	}
+	authorWidth = min(authorWidth, 16)
+
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.lines))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.lines),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			l := m.lines[index]
+			// only the first line of a run of lines from the same change
+			// shows the change details
+			gutter := strings.Repeat(" ", 8+1+authorWidth+1+4)
+			if index == 0 || m.lines[index-1].commitId != l.commitId {
+				author := ansi.Truncate(l.author, authorWidth, "…")
+				gutter = fmt.Sprintf("%-8s %-*s %4s", l.changeId, authorWidth, author, age(now.Sub(l.time)))
+			}
+			// the whole line is tinted by its age, not just the gutter
+			gutterStyle := common.DefaultPalette.Get(ageStyle(now.Sub(l.time)))
+			textStyle := gutterStyle.Inherit(common.DefaultPalette.Get("annotate text"))
+			dimmedStyle := common.DefaultPalette.Get("annotate dimmed")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("annotate selected")
+				textStyle = textStyle.Inherit(selected)
+				gutterStyle = gutterStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(gutter, gutterStyle).
+				Styled(fmt.Sprintf(" %*d ", numberWidth, l.number), dimmedStyle).
+				Styled(ansi.Truncate(render.ExpandTabs(l.content), max(rect.Dx()-ansi.StringWidth(gutter)-numberWidth-2, 0), ""), textStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return lineClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// age formats a duration compactly, e.g. 5m, 3d, 2mo
+func age(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(int(d/time.Minute), 0))
+	case d < day:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	case d < 7*day:
+		return fmt.Sprintf("%dd", int(d/day))
+	case d < 30*day:
+		return fmt.Sprintf("%dw", int(d/(7*day)))
+	case d < 365*day:
+		return fmt.Sprintf("%dmo", int(d/(30*day)))
+	default:
+		return fmt.Sprintf("%dy", int(d/(365*day)))
+	}
+}
+
+// ageStyle returns the palette key lines of the given age are coloured with
+func ageStyle(d time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case d < day:
+		return "annotate age newest"
+	case d < 7*day:
+		return "annotate age new"
+	case d < 30*day:
+		return "annotate age recent"
+	case d < 365*day:
+		return "annotate age old"
+	default:
+		return "annotate age oldest"
+	}
+}
+
+func NewModel(c *context.MainContext, changeId string, file string) *Model {
+	m := &Model{
+		context:      c,
+		changeId:     changeId,
+		file:         file,
+		listRenderer: render.NewListRenderer(lineScrollMsg{}),
+		now:          time.Now,
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
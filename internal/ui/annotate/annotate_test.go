@@ -0,0 +1,100 @@
+package annotate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const annotateOutput = "kkmpptxz\t1a2b3c4d\tAlice\t1700000000\t1\tpackage main\n" +
+	"kkmpptxz\t1a2b3c4d\tAlice\t1700000000\t2\t\n" +
+	"zsuskuln\t5e6f7a8b\tBob\t1700086400\t3\tfunc main() {}\n"
+
+func newTestModel(t *testing.T, commandRunner *test.CommandRunner) *Model {
+	t.Helper()
+	model := NewModel(test.NewTestContext(commandRunner), "abc", "main.go")
+	model.now = func() time.Time { return time.Unix(1700000000, 0).Add(48 * time.Hour) }
+	test.SimulateModel(model, model.Init())
+	return model
+}
+
+func TestParseLines(t *testing.T) {
+	lines := parseLines(annotateOutput)
+	assert.Len(t, lines, 3)
+	assert.Equal(t, line{
+		changeId: "zsuskuln",
+		commitId: "5e6f7a8b",
+		author:   "Bob",
+		time:     time.Unix(1700086400, 0),
+		number:   3,
+		content:  "func main() {}",
+	}, lines[2])
+	assert.Equal(t, "", lines[1].content)
+
+	lines = parseLines("zzzzzzzz\t00000000\t\t0\t4\troot line\n")
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "", lines[0].author)
+	assert.Equal(t, 4, lines[0].number)
+	assert.Equal(t, "root line", lines[0].content)
+}
+
+func TestView_ShowsChangeOnFirstLineOfEachRun(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileAnnotate("abc", "main.go")).SetOutput([]byte(annotateOutput))
+	defer commandRunner.Verify()
+
+	model := newTestModel(t, commandRunner)
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 10))
+	assert.Contains(t, rendered, "annotate main.go @ abc")
+	assert.Contains(t, rendered, "kkmpptxz Alice   2d 1 package main")
+	assert.Contains(t, rendered, "zsuskuln Bob     1d 3 func main() {}")
+	assert.Equal(t, 1, strings.Count(rendered, "kkmpptxz"))
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "5m", age(5*time.Minute))
+	assert.Equal(t, "3h", age(3*time.Hour))
+	assert.Equal(t, "2w", age(15*24*time.Hour))
+	assert.Equal(t, "4mo", age(125*24*time.Hour))
+	assert.Equal(t, "2y", age(800*24*time.Hour))
+}
+
+func TestJump_NavigatesToChangeOfCurrentLine(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileAnnotate("abc", "main.go")).SetOutput([]byte(annotateOutput))
+	defer commandRunner.Verify()
+
+	model := newTestModel(t, commandRunner)
+	model.Update(intents.AnnotateNavigate{Delta: 5})
+	assert.Equal(t, 2, model.cursor)
+
+	var jumped intents.JumpToRevision
+	test.SimulateModel(model, func() tea.Msg { return intents.AnnotateJump{} }, func(msg tea.Msg) {
+		if jump, ok := msg.(intents.JumpToRevision); ok {
+			jumped = jump
+		}
+	})
+	assert.Equal(t, intents.JumpToRevision{ChangeID: "zsuskuln", FallbackID: "5e6f7a8b"}, jumped)
+}
+
+func TestShowDiff_OpensDiffOfCurrentLine(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.FileAnnotate("abc", "main.go")).SetOutput([]byte(annotateOutput))
+	commandRunner.Expect(jj.Diff("1a2b3c4d", "")).SetOutput([]byte("diff content"))
+	defer commandRunner.Verify()
+
+	model := newTestModel(t, commandRunner)
+	var shown string
+	test.SimulateModel(model, func() tea.Msg { return intents.AnnotateShowDiff{} }, func(msg tea.Msg) {
+		if show, ok := msg.(intents.DiffShow); ok {
+			shown = show.Content
+		}
+	})
+	assert.Equal(t, "diff content", shown)
+}
@@ -0,0 +1,171 @@
+package autofetch
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// fetchEnv keeps git and ssh from prompting for credentials or passphrases on
+// the terminal, which jjui owns while the fetch runs in the background
+func fetchEnv() []string {
+	ssh := os.Getenv("GIT_SSH_COMMAND")
+	if ssh == "" {
+		ssh = "ssh"
+	}
+	return []string{"GIT_TERMINAL_PROMPT=0", "GIT_SSH_COMMAND=" + ssh + " -o BatchMode=yes"}
+}
+
+type tickMsg struct{}
+
+type fetchedMsg struct {
+	moved []jj.TrackedBookmark
+	err   error
+}
+
+// MovedMsg carries the names of the tracked bookmarks that moved in the last
+// fetch, by the commit id they moved to
+type MovedMsg struct {
+	Bookmarks map[string][]string
+}
+
+// Model periodically runs `jj git fetch` quietly and reports the tracked
+// bookmarks that moved upstream. Fetches are skipped while busy reports that
+// the user is in the middle of something, so they never interrupt it.
+type Model struct {
+	context  *context.MainContext
+	busy     func() bool
+	fetching bool
+}
+
+func New(c *context.MainContext, busy func() bool) *Model {
+	return &Model{
+		context: c,
+		busy:    busy,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.schedule()
+}
+
+func (m *Model) schedule() tea.Cmd {
+	interval := config.Current.Git.BackgroundFetch.Interval
+	if interval > 0 {
+		return tea.Tick(time.Duration(interval)*time.Second, func(time.Time) tea.Msg {
+			return tickMsg{}
+		})
+	}
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		if m.fetching || m.busy() {
+			return m.schedule()
+		}
+		m.fetching = true
+		return m.fetch
+	case fetchedMsg:
+		m.fetching = false
+		if msg.err != nil {
+			log.Println("background fetch failed:", msg.err)
+			return m.schedule()
+		}
+		if len(msg.moved) == 0 {
+			return m.schedule()
+		}
+		cmds := []tea.Cmd{
+			m.schedule(),
+			intents.Invoke(intents.AddMessage{Text: movedMessage(msg.moved), Sticky: true}),
+		}
+		// the user may have started something while the fetch was running,
+		// the next refresh picks up the moved bookmarks then
+		if !m.busy() {
+			cmds = append(cmds, func() tea.Msg { return common.AutoRefreshMsg{} })
+		}
+		if config.Current.Git.BackgroundFetch.Badge {
+			bookmarks := map[string][]string{}
+			for _, b := range msg.moved {
+				bookmarks[b.CommitId] = append(bookmarks[b.CommitId], b.Name+"@"+b.Remote)
+			}
+			cmds = append(cmds, func() tea.Msg { return MovedMsg{Bookmarks: bookmarks} })
+		}
+		return tea.Batch(cmds...)
+	}
+	return nil
+}
+
+func (m *Model) fetch() tea.Msg {
+	remotes := config.Current.Git.BackgroundFetch.Remotes
+	before, err := m.trackedBookmarks(remotes)
+	if err != nil {
+		return fetchedMsg{err: err}
+	}
+	var flags []string
+	for _, remote := range remotes {
+		flags = append(flags, "--remote", remote)
+	}
+	flags = append(flags, "--quiet")
+	if _, err := m.context.RunCommandImmediateWithEnv(jj.GitFetch(flags...), fetchEnv()); err != nil {
+		return fetchedMsg{err: err}
+	}
+	after, err := m.trackedBookmarks(remotes)
+	if err != nil {
+		return fetchedMsg{err: err}
+	}
+	return fetchedMsg{moved: movedBookmarks(before, after)}
+}
+
+// trackedBookmarks lists the tracked bookmarks of remotes, or of all remotes
+// when none are given
+func (m *Model) trackedBookmarks(remotes []string) ([]jj.TrackedBookmark, error) {
+	output, err := m.context.RunCommandImmediate(jj.BookmarkListTracked())
+	if err != nil {
+		return nil, err
+	}
+	bookmarks := jj.ParseTrackedBookmarks(string(output))
+	if len(remotes) > 0 {
+		bookmarks = slices.DeleteFunc(bookmarks, func(b jj.TrackedBookmark) bool {
+			return !slices.Contains(remotes, b.Remote)
+		})
+	}
+	return bookmarks, nil
+}
+
+// movedBookmarks returns the bookmarks of after that point at a different
+// commit than they did before. Newly tracked, deleted and conflicted
+// bookmarks are left out.
+func movedBookmarks(before []jj.TrackedBookmark, after []jj.TrackedBookmark) []jj.TrackedBookmark {
+	previous := map[string]string{}
+	for _, b := range before {
+		previous[b.Name+"@"+b.Remote] = b.CommitId
+	}
+	var moved []jj.TrackedBookmark
+	for _, b := range after {
+		commitId, ok := previous[b.Name+"@"+b.Remote]
+		if ok && commitId != "" && b.CommitId != "" && commitId != b.CommitId {
+			moved = append(moved, b)
+		}
+	}
+	return moved
+}
+
+func movedMessage(moved []jj.TrackedBookmark) string {
+	names := make([]string, len(moved))
+	for i, b := range moved {
+		names[i] = b.Name + "@" + b.Remote
+	}
+	return fmt.Sprintf("Moved upstream: %s", strings.Join(names, ", "))
+}
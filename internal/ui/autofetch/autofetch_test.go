@@ -0,0 +1,95 @@
+package autofetch
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func fetchRemotes(t *testing.T, remotes ...string) {
+	t.Helper()
+	previous := config.Current.Git.BackgroundFetch
+	config.Current.Git.BackgroundFetch = config.BackgroundFetchConfig{Remotes: remotes, Badge: true}
+	t.Cleanup(func() { config.Current.Git.BackgroundFetch = previous })
+}
+
+func TestAutoFetch_ReportsMovedBookmarks(t *testing.T) {
+	fetchRemotes(t, "origin")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\taaaa\ndev\torigin\tbbbb\nmain\tupstream\tcccc"))
+	commandRunner.Expect(jj.GitFetch("--remote", "origin", "--quiet"))
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\tdddd\ndev\torigin\tbbbb\nmain\tupstream\teeee\nnew\torigin\tffff"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), func() bool { return false })
+	var message intents.AddMessage
+	var moved MovedMsg
+	test.SimulateModel(model, func() tea.Msg { return tickMsg{} }, func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case intents.AddMessage:
+			message = msg
+		case MovedMsg:
+			moved = msg
+		}
+	})
+	assert.Equal(t, "Moved upstream: main@origin", message.Text)
+	assert.True(t, message.Sticky)
+	assert.Equal(t, map[string][]string{"dddd": {"main@origin"}}, moved.Bookmarks)
+}
+
+func TestAutoFetch_StaysQuietWhenNothingMoved(t *testing.T) {
+	fetchRemotes(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\taaaa"))
+	commandRunner.Expect(jj.GitFetch("--quiet"))
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\taaaa"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), func() bool { return false })
+	test.SimulateModel(model, func() tea.Msg { return tickMsg{} }, func(msg tea.Msg) {
+		_, isMessage := msg.(intents.AddMessage)
+		assert.False(t, isMessage)
+	})
+}
+
+func TestAutoFetch_SkipsWhileBusy(t *testing.T) {
+	fetchRemotes(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner), func() bool { return true })
+	test.SimulateModel(model, func() tea.Msg { return tickMsg{} })
+	assert.False(t, model.fetching)
+}
+
+func TestAutoFetch_DoesNotRefreshWhenBusyAfterFetch(t *testing.T) {
+	fetchRemotes(t)
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\taaaa"))
+	commandRunner.Expect(jj.GitFetch("--quiet"))
+	commandRunner.Expect(jj.BookmarkListTracked()).SetOutput([]byte("main\torigin\tbbbb"))
+	defer commandRunner.Verify()
+
+	busy := false
+	model := New(test.NewTestContext(commandRunner), func() bool { return busy })
+	msg := model.Update(tickMsg{})()
+	busy = true
+	var refreshed bool
+	test.SimulateModel(model, func() tea.Msg { return msg }, func(msg tea.Msg) {
+		if _, ok := msg.(common.AutoRefreshMsg); ok {
+			refreshed = true
+		}
+	})
+	assert.False(t, refreshed)
+}
+
+func TestFetchEnv_DisablesSshPrompts(t *testing.T) {
+	t.Setenv("GIT_SSH_COMMAND", "ssh -i key")
+	assert.Equal(t, []string{"GIT_TERMINAL_PROMPT=0", "GIT_SSH_COMMAND=ssh -i key -o BatchMode=yes"}, fetchEnv())
+}
@@ -21,6 +21,7 @@ import (
 
 type updateItemsMsg struct {
 	items []item
+	syncs []jj.BookmarkSync
 }
 
 // SelectRemoteMsg is sent when a remote is clicked
@@ -51,6 +52,41 @@ const (
 	filterApplied
 )
 
+// syncFilter narrows the list down to the bookmarks that drifted from their
+// tracked remotes in a given way
+type syncFilter int
+
+const (
+	syncFilterOff syncFilter = iota
+	syncFilterUnpushed
+	syncFilterUnpulled
+	syncFilterDiverged
+)
+
+func (f syncFilter) String() string {
+	switch f {
+	case syncFilterUnpushed:
+		return "unpushed"
+	case syncFilterUnpulled:
+		return "unpulled"
+	case syncFilterDiverged:
+		return "diverged"
+	}
+	return ""
+}
+
+func (f syncFilter) match(s jj.BookmarkSync) bool {
+	switch f {
+	case syncFilterUnpushed:
+		return s.Unpushed()
+	case syncFilterUnpulled:
+		return s.Unpulled()
+	case syncFilterDiverged:
+		return s.Diverged()
+	}
+	return true
+}
+
 var _ common.ImmediateModel = (*Model)(nil)
 var _ common.Focusable = (*Model)(nil)
 var _ common.Editable = (*Model)(nil)
@@ -69,6 +105,8 @@ type Model struct {
 	filterState         filterState
 	filterText          string
 	categoryFilter      string
+	syncFilter          syncFilter
+	syncs               []jj.BookmarkSync
 	ensureCursorVisible bool
 	title               string
 }
@@ -98,6 +136,9 @@ type item struct {
 	dist     int
 	args     []string
 	key      string
+	bookmark string
+	// remote is set for the items acting on a single remote bookmark
+	remote string
 }
 
 func (i item) FilterValue() string {
@@ -239,6 +280,7 @@ func (m *Model) loadMovables() tea.Msg {
 			priority: moveCommand,
 			args:     jj.BookmarkMove(m.current.GetChangeId(), b.Name, extraFlags...),
 			dist:     m.distance(b.CommitId),
+			bookmark: b.Name,
 		}
 		if b.Name == "main" || b.Name == "master" {
 			elem.key = "m"
@@ -263,6 +305,7 @@ func (m *Model) loadAll() tea.Msg {
 					priority: deleteCommand,
 					dist:     distance,
 					args:     jj.BookmarkDelete(b.Name),
+					bookmark: b.Name,
 				})
 			}
 
@@ -271,6 +314,7 @@ func (m *Model) loadAll() tea.Msg {
 				priority: forgetCommand,
 				dist:     distance,
 				args:     jj.BookmarkForget(b.Name),
+				bookmark: b.Name,
 			})
 
 			// Track local bookmarks as they have no remotes
@@ -280,6 +324,7 @@ func (m *Model) loadAll() tea.Msg {
 					priority: trackCommand,
 					dist:     distance,
 					args:     jj.BookmarkTrack(b.Name, m.defaultTrackRemote()),
+					bookmark: b.Name,
 				})
 			}
 
@@ -291,6 +336,8 @@ func (m *Model) loadAll() tea.Msg {
 						priority: untrackCommand,
 						dist:     distance,
 						args:     jj.BookmarkUntrack(b.Name, remote.Remote),
+						bookmark: b.Name,
+						remote:   remote.Remote,
 					})
 				} else {
 					items = append(items, item{
@@ -298,12 +345,26 @@ func (m *Model) loadAll() tea.Msg {
 						priority: trackCommand,
 						dist:     distance,
 						args:     jj.BookmarkTrack(b.Name, remote.Remote),
+						bookmark: b.Name,
+						remote:   remote.Remote,
 					})
 				}
 			}
 		}
-		return updateItemsMsg{items: items}
+		return updateItemsMsg{items: items, syncs: context.LoadBookmarkSyncs(m.context, bookmarks)}
+	}
+}
+
+// syncsOf returns the counts of the bookmark the item acts on, against the
+// item's remote if it has one or all tracked remotes otherwise
+func (m *Model) syncsOf(listItem item) []jj.BookmarkSync {
+	var syncs []jj.BookmarkSync
+	for _, s := range m.syncs {
+		if s.Name == listItem.bookmark && (listItem.remote == "" || s.Remote == listItem.remote) {
+			syncs = append(syncs, s)
+		}
 	}
+	return syncs
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
@@ -351,6 +412,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		}
 	case updateItemsMsg:
 		m.allItems = append(m.allItems, msg.items...)
+		m.syncs = append(m.syncs, msg.syncs...)
 		slices.SortFunc(m.allItems, itemSorter)
 		return m.updateMenuForRemote()
 	}
@@ -389,6 +451,10 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return m.filtered(filter), true
 	case intents.BookmarksCycleRemotes:
 		return m.cycleRemotes(msg.Delta), true
+	case intents.BookmarksCycleSyncFilter:
+		m.syncFilter = (m.syncFilter + 1) % (syncFilterDiverged + 1)
+		m.applyFilters(true)
+		return nil, true
 	case intents.BookmarksOpenFilter:
 		m.filterState = filterEditing
 		m.filterInput.Focus()
@@ -629,7 +695,7 @@ func (m *Model) moveCursor(delta int) {
 }
 
 func (m *Model) hasActiveFilter() bool {
-	return m.categoryFilter != "" || m.currentFilterText() != ""
+	return m.categoryFilter != "" || m.syncFilter != syncFilterOff || m.currentFilterText() != ""
 }
 
 func (m *Model) currentFilterText() string {
@@ -649,6 +715,7 @@ func (m *Model) resetTextFilter() {
 
 func (m *Model) resetAllFilters() {
 	m.categoryFilter = ""
+	m.syncFilter = syncFilterOff
 	m.resetTextFilter()
 }
 
@@ -665,6 +732,12 @@ func (m *Model) applyFilters(resetCursor bool) {
 		items = filtered
 	}
 
+	if m.syncFilter != syncFilterOff {
+		items = slices.DeleteFunc(items, func(listItem item) bool {
+			return !slices.ContainsFunc(m.syncsOf(listItem), m.syncFilter.match)
+		})
+	}
+
 	filterText := m.currentFilterText()
 	if filterText != "" {
 		filtered := make([]item, 0, len(items))
@@ -753,6 +826,13 @@ func (m *Model) renderFilterView(dl *render.DisplayContext, box layout.Box) {
 		labelStyle.Render("remote"),
 	}
 
+	if m.syncFilter != syncFilterOff {
+		parts = append(parts,
+			labelStyle.Render("only"),
+			valueStyle.Render(m.syncFilter.String()),
+		)
+	}
+
 	filterText := m.currentFilterText()
 	if filterText != "" {
 		parts = append(parts,
@@ -789,7 +869,7 @@ func (m *Model) renderList(dl *render.DisplayContext, listBox layout.Box) {
 			if index < 0 || index >= itemCount {
 				return
 			}
-			renderItem(dl, rect, listWidth, m.categoryFilter != "", m.cursor, index, items[index])
+			renderItem(dl, rect, listWidth, m.categoryFilter != "", m.cursor, index, items[index], jj.SyncLabel(m.syncsOf(items[index])))
 		},
 		func(index int, _ tea.Mouse) tea.Msg { return itemClickMsg{Index: index} },
 	)
@@ -797,7 +877,7 @@ func (m *Model) renderList(dl *render.DisplayContext, listBox layout.Box) {
 	m.ensureCursorVisible = false
 }
 
-func renderItem(dl *render.DisplayContext, rect layout.Rectangle, width int, showShortcuts bool, cursor int, index int, item item) {
+func renderItem(dl *render.DisplayContext, rect layout.Rectangle, width int, showShortcuts bool, cursor int, index int, item item, syncLabel string) {
 	var (
 		title string
 		desc  string
@@ -812,8 +892,11 @@ func renderItem(dl *render.DisplayContext, rect layout.Rectangle, width int, sho
 		return
 	}
 
-	if len(title) > width {
-		title = title[:width-1] + "…"
+	if syncLabel != "" {
+		syncLabel = " " + syncLabel
+	}
+	if titleWidth := width - lipgloss.Width(syncLabel); len(title) > titleWidth {
+		title = title[:max(titleWidth-1, 0)] + "…"
 	}
 
 	if len(desc) > width {
@@ -823,11 +906,13 @@ func renderItem(dl *render.DisplayContext, rect layout.Rectangle, width int, sho
 	textStyle := common.DefaultPalette.Get("bookmarks menu text")
 	descStyle := common.DefaultPalette.Get("bookmarks menu dimmed")
 	shortcutStyle := common.DefaultPalette.Get("bookmarks menu shortcut")
+	syncStyle := common.DefaultPalette.Get("bookmarks menu sync")
 
 	if index == cursor {
 		textStyle = common.DefaultPalette.Get("bookmarks menu selected text")
 		descStyle = common.DefaultPalette.Get("bookmarks menu selected dimmed")
 		shortcutStyle = common.DefaultPalette.Get("bookmarks menu selected shortcut")
+		syncStyle = common.DefaultPalette.Get("bookmarks menu selected sync")
 	}
 
 	titleLine := ""
@@ -836,6 +921,9 @@ func renderItem(dl *render.DisplayContext, rect layout.Rectangle, width int, sho
 	} else {
 		titleLine = textStyle.PaddingLeft(1).Render(title)
 	}
+	if syncLabel != "" {
+		titleLine = lipgloss.JoinHorizontal(0, titleLine, syncStyle.Render(syncLabel))
+	}
 	titleLine = lipgloss.PlaceHorizontal(width+2, 0, titleLine, lipgloss.WithWhitespaceStyle(textStyle))
 
 	descStyle = descStyle.PaddingLeft(1).PaddingRight(1).Width(width + 2)
@@ -111,3 +111,34 @@ func Test_FilterEditing_AcceptsPasteMsg(t *testing.T) {
 
 	assert.Equal(t, "track feature", op.filterInput.Value())
 }
+
+func Test_SyncCounts_ShownAndFiltered(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin"))
+	commandRunner.Expect(jj.BookmarkListAll()).SetOutput([]byte(`main;.;false;false;false;a1
+main;origin;true;false;false;b2
+docs;.;false;false;false;d4
+docs;origin;true;false;false;d4`))
+	commandRunner.Expect(jj.BookmarksAheadBehind([]jj.BookmarkSync{{Name: "main", Remote: "origin", CommitId: "a1"}})).SetOutput([]byte("0+ \n0+ \n0- \n"))
+	commandRunner.Expect(jj.BookmarkListMovable("abc123")).SetOutput([]byte(""))
+	defer commandRunner.Verify()
+
+	commit := &jj.Commit{ChangeId: "abc123", CommitId: "commit123"}
+	op := NewModel(test.NewTestContext(commandRunner), commit, []string{"commit123"})
+	test.SimulateModel(op, op.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(op, 100, 40))
+	assert.Contains(t, rendered, "delete 'main' ↑2↓1")
+	assert.Contains(t, rendered, "delete 'docs'")
+
+	// unpushed, then unpulled, then diverged all keep main only
+	for range 3 {
+		test.SimulateModel(op, func() tea.Msg { return intents.BookmarksCycleSyncFilter{} })
+		for _, listItem := range op.visibleItems() {
+			assert.Equal(t, "main", listItem.bookmark)
+		}
+		assert.NotEmpty(t, op.visibleItems())
+	}
+	test.SimulateModel(op, func() tea.Msg { return intents.BookmarksCycleSyncFilter{} })
+	assert.Equal(t, syncFilterOff, op.syncFilter)
+}
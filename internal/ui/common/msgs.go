@@ -38,6 +38,7 @@ type (
 		Item SelectedItem
 	}
 	QuickSearchMsg  string
+	DiffSearchMsg   string
 	UpdateRevSetMsg string
 	ExecMsg         struct {
 		Line string
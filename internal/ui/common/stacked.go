@@ -9,3 +9,10 @@ type StackedModel interface {
 	ImmediateModel
 	dispatch.ScopeProvider
 }
+
+// Disposable is implemented by stacked models that hold on to something, like
+// a running command, that has to be released once the model is closed or
+// replaced by another one.
+type Disposable interface {
+	Dispose()
+}
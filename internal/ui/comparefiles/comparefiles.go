@@ -0,0 +1,235 @@
+package comparefiles
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+type loadedMsg struct {
+	files []jj.ChangedFile
+	err   error
+}
+
+type rowClickMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// Model lists the files changed between two revisions. The first row stands
+// for the whole diff, the others for a single file each.
+type Model struct {
+	context             *context.MainContext
+	from                string
+	to                  string
+	files               []jj.ChangedFile
+	loaded              bool
+	err                 error
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeCompareFiles,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.DiffRangeSummary(m.from, m.to))
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	return loadedMsg{files: jj.ParseDiffSummary(string(output))}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.err = msg.err
+		m.files = msg.files
+		m.loaded = true
+		m.cursor = min(m.cursor, len(m.files))
+	case rowClickMsg:
+		if msg.Index >= 0 && msg.Index <= len(m.files) {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.CompareNavigate:
+		m.cursor = max(min(m.cursor+intent.Delta, len(m.files)), 0)
+		m.ensureCursorVisible = true
+		return nil, true
+	case intents.CompareDiff:
+		if !m.loaded || m.err != nil {
+			return nil, true
+		}
+		var file string
+		if m.cursor > 0 {
+			file = m.files[m.cursor-1].Path
+		}
+		args := jj.DiffRange(m.from, m.to, file)
+		return func() tea.Msg {
+			output, _ := m.context.RunCommandImmediate(args)
+			return intents.DiffShow{Content: string(output)}
+		}, true
+	case intents.CompareSwap:
+		m.from, m.to = m.to, m.from
+		m.loaded = false
+		return m.load, true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("compare text")
+	dimmedStyle := common.DefaultPalette.Get("compare dimmed")
+	changeIdStyle := common.DefaultPalette.Get("compare change_id")
+	borderStyle := common.DefaultPalette.GetBorder("compare border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("compare ", common.DefaultPalette.Get("compare title")).
+		Styled("from ", dimmedStyle).
+		Styled(m.from, changeIdStyle).
+		Styled(" to ", dimmedStyle).
+		Styled(m.to, changeIdStyle).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.files) == 0:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("no changes"), render.ZMenuContent)
+		return
+	}
+	m.renderRows(dl, contentBox)
+}
+
+func (m *Model) renderRows(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	count := len(m.files) + 1
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), count)
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		count,
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			textStyle := common.DefaultPalette.Get("compare text")
+			dimmedStyle := common.DefaultPalette.Get("compare dimmed")
+			var statusStyle lipgloss.Style
+			if index > 0 {
+				statusStyle = statusStyleOf(m.files[index-1].Status)
+			}
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("compare selected")
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				statusStyle = statusStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent)
+			if index == 0 {
+				tb.Styled(fmt.Sprintf("all files (%d changed)", len(m.files)), dimmedStyle).Done()
+				return
+			}
+			file := m.files[index-1]
+			tb.Styled(string(file.Status)+" ", statusStyle)
+			tb.Styled(ansi.Truncate(file.Name, max(rect.Dx()-2, 0), "…"), textStyle).Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return rowClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+func statusStyleOf(status byte) lipgloss.Style {
+	switch status {
+	case 'A':
+		return common.DefaultPalette.Get("compare added")
+	case 'D':
+		return common.DefaultPalette.Get("compare deleted")
+	case 'R', 'C':
+		return common.DefaultPalette.Get("compare renamed")
+	default:
+		return common.DefaultPalette.Get("compare modified")
+	}
+}
+
+func NewModel(c *context.MainContext, from string, to string) *Model {
+	m := &Model{
+		context:      c,
+		from:         from,
+		to:           to,
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
@@ -0,0 +1,63 @@
+package comparefiles
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const summaryOutput = "M src/main.go\nR src/{parser.go => parse/parser.go}\n"
+
+func TestLoad_ListsChangedFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DiffRangeSummary("base", "tip")).SetOutput([]byte(summaryOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "base", "tip")
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "compare from base to tip")
+	assert.Contains(t, rendered, "all files (2 changed)")
+	assert.Contains(t, rendered, "M src/main.go")
+	assert.Contains(t, rendered, "R src/{parser.go => parse/parser.go}")
+}
+
+func TestDiff_ShowsWholeDiffOrSelectedFile(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DiffRangeSummary("base", "tip")).SetOutput([]byte(summaryOutput))
+	commandRunner.Expect(jj.DiffRange("base", "tip", "")).SetOutput([]byte("whole diff"))
+	commandRunner.Expect(jj.DiffRange("base", "tip", "src/parse/parser.go")).SetOutput([]byte("parser diff"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "base", "tip")
+	test.SimulateModel(model, model.Init())
+
+	cmd := model.Update(intents.CompareDiff{})
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.DiffShow{Content: "whole diff"}, cmd())
+
+	model.Update(intents.CompareNavigate{Delta: 2})
+	cmd = model.Update(intents.CompareDiff{})
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.DiffShow{Content: "parser diff"}, cmd())
+}
+
+func TestSwap_ReloadsWithSidesSwapped(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DiffRangeSummary("base", "tip")).SetOutput([]byte(summaryOutput))
+	commandRunner.Expect(jj.DiffRangeSummary("tip", "base")).SetOutput([]byte("M src/main.go\n"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "base", "tip")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, model.Update(intents.CompareSwap{}))
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "compare from tip to base")
+	assert.Contains(t, rendered, "all files (1 changed)")
+}
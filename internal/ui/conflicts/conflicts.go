@@ -0,0 +1,325 @@
+package conflicts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+type conflictedRevision struct {
+	jj.ConflictedRevision
+	files []jj.ConflictedFile
+}
+
+type loadedMsg struct {
+	revisions []conflictedRevision
+	err       error
+}
+
+type rowClickMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// row is a line of the dashboard, either a revision or, when file is not
+// negative, one of its conflicted files
+type row struct {
+	revision int
+	file     int
+}
+
+// Model lists every conflicted revision along with its conflicted files. It
+// reloads after each resolution, keeping count of how many of the conflicted
+// files it first found are resolved.
+type Model struct {
+	context             *context.MainContext
+	revisions           []conflictedRevision
+	rows                []row
+	initialFiles        int
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeConflicts,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.ConflictedRevisions())
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	var revisions []conflictedRevision
+	for _, revision := range jj.ParseConflictedRevisions(string(output)) {
+		// a revision whose files cannot be listed is still worth showing
+		output, _ := m.context.RunCommandImmediate(jj.ResolveList(revision.CommitId))
+		revisions = append(revisions, conflictedRevision{
+			ConflictedRevision: revision,
+			files:              jj.ParseResolveListOutput(string(output)),
+		})
+	}
+	return loadedMsg{revisions: revisions}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.err = msg.err
+		m.revisions = msg.revisions
+		m.rows = nil
+		for i, revision := range m.revisions {
+			m.rows = append(m.rows, row{revision: i, file: -1})
+			for j := range revision.files {
+				m.rows = append(m.rows, row{revision: i, file: j})
+			}
+		}
+		if !m.loaded {
+			m.initialFiles = m.remainingFiles()
+		}
+		m.loaded = true
+		m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+	case rowClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.rows) {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.ConflictsNavigate:
+		if len(m.rows) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.rows)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.ConflictsDiff:
+		revision, file := m.current()
+		if revision == nil {
+			return nil, true
+		}
+		var fileName string
+		if file != nil {
+			fileName = file.Path
+		}
+		args := jj.Diff(revision.CommitId, fileName)
+		return func() tea.Msg {
+			output, _ := m.context.RunCommandImmediate(args)
+			return intents.DiffShow{Content: string(output)}
+		}, true
+	case intents.ConflictsResolve:
+		revision, file := m.current()
+		if file == nil {
+			m.message = "select a conflicted file to resolve"
+			return nil, true
+		}
+		m.message = ""
+		if intent.Tool != intents.ConflictsResolveMerge {
+			args := jj.Resolve(revision.CommitId, file.Path, string(intent.Tool))
+			return m.context.RunCommand(args, m.load, common.Refresh), true
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err}), true
+		}
+		args := jj.Resolve(revision.CommitId, file.Path, jj.BuiltinMergeTool, jj.BuiltinMergeToolConfig(exe)...)
+		return m.context.RunInteractiveCommand(args, tea.Batch(m.load, common.Refresh)), true
+	case intents.Apply:
+		revision, _ := m.current()
+		if revision == nil {
+			return nil, true
+		}
+		return tea.Sequence(common.Close, intents.Invoke(intents.Navigate{ChangeID: revision.ChangeId})), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+// current returns the revision under the cursor, and the file too if the
+// cursor is on one of its files
+func (m *Model) current() (*conflictedRevision, *jj.ConflictedFile) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil, nil
+	}
+	r := m.rows[m.cursor]
+	revision := &m.revisions[r.revision]
+	if r.file < 0 {
+		return revision, nil
+	}
+	return revision, &revision.files[r.file]
+}
+
+func (m *Model) remainingFiles() int {
+	count := 0
+	for _, revision := range m.revisions {
+		count += len(revision.files)
+	}
+	return count
+}
+
+// progress summarises what is left, and how much got resolved since the
+// dashboard was opened
+func (m *Model) progress() string {
+	remaining := m.remainingFiles()
+	summary := fmt.Sprintf("%d conflicted revisions, %d files", len(m.revisions), remaining)
+	if resolved := m.initialFiles - remaining; resolved > 0 {
+		summary += fmt.Sprintf(" (%d of %d resolved)", resolved, m.initialFiles)
+	}
+	return summary
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("conflicts text")
+	dimmedStyle := common.DefaultPalette.Get("conflicts dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("conflicts border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	tb := dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("conflicts", common.DefaultPalette.Get("conflicts title"))
+	if m.loaded && m.err == nil {
+		tb.Styled(" "+m.progress(), dimmedStyle)
+	}
+	tb.Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.rows) == 0 && m.initialFiles > 0:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("all conflicts resolved"), render.ZMenuContent)
+		return
+	case len(m.rows) == 0:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("no conflicts"), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	m.renderRows(dl, contentBox)
+}
+
+func (m *Model) renderRows(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.rows))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.rows),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			r := m.rows[index]
+			revision := m.revisions[r.revision]
+			textStyle := common.DefaultPalette.Get("conflicts text")
+			dimmedStyle := common.DefaultPalette.Get("conflicts dimmed")
+			changeIdStyle := common.DefaultPalette.Get("conflicts change_id")
+			conflictStyle := common.DefaultPalette.Get("conflicts conflict")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("conflicts selected")
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				conflictStyle = conflictStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent)
+			if r.file >= 0 {
+				file := revision.files[r.file]
+				path := "  " + file.Path + " "
+				tb.Styled(path, textStyle)
+				remaining := max(rect.Dx()-ansi.StringWidth(path), 0)
+				tb.Styled(ansi.Truncate(file.Description, remaining, "…"), conflictStyle).Done()
+				return
+			}
+			description := revision.Description
+			if description == "" {
+				description = "(no description set)"
+			}
+			tb.Styled(revision.ChangeId+" ", changeIdStyle)
+			remaining := max(rect.Dx()-ansi.StringWidth(revision.ChangeId)-1, 0)
+			tb.Styled(ansi.Truncate(description, remaining, "…"), textStyle).Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return rowClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+func NewModel(c *context.MainContext) *Model {
+	m := &Model{
+		context:      c,
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
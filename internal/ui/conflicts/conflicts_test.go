@@ -0,0 +1,105 @@
+package conflicts
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const revisionsOutput = "kxqpnrzs\t1a2b\tfix the parser\nyqosqzyt\t5e6f\t\n"
+
+func TestLoad_ListsRevisionsWithTheirFiles(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte(revisionsOutput))
+	commandRunner.Expect(jj.ResolveList("1a2b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.ResolveList("5e6f")).SetOutput([]byte("parser.go    2-sided conflict\nlexer.go     3-sided conflict"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "2 conflicted revisions, 3 files")
+	assert.Contains(t, rendered, "kxqpnrzs fix the parser")
+	assert.Contains(t, rendered, "yqosqzyt (no description set)")
+	assert.Contains(t, rendered, "lexer.go 3-sided conflict")
+}
+
+func TestResolve_ReloadsAndCountsProgress(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte(revisionsOutput))
+	commandRunner.Expect(jj.ResolveList("1a2b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.ResolveList("5e6f")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.Resolve("1a2b", "parser.go", ":theirs"))
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte("yqosqzyt\t7a8b\t\n"))
+	commandRunner.Expect(jj.ResolveList("7a8b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.ConflictsNavigate{Delta: 1})
+	test.SimulateModel(model, func() tea.Msg { return intents.ConflictsResolve{Tool: intents.ConflictsResolveTheirs} })
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "1 conflicted revisions, 1 files (1 of 2 resolved)")
+}
+
+func TestResolve_OnRevisionAsksForFile(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte(revisionsOutput))
+	commandRunner.Expect(jj.ResolveList("1a2b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.ResolveList("5e6f")).SetOutput([]byte(""))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.ConflictsResolve{Tool: intents.ConflictsResolveOurs})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "select a conflicted file to resolve")
+}
+
+func TestDiff_ShowsConflictDiffOfFile(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte(revisionsOutput))
+	commandRunner.Expect(jj.ResolveList("1a2b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.ResolveList("5e6f")).SetOutput([]byte(""))
+	commandRunner.Expect(jj.Diff("1a2b", "parser.go")).SetOutput([]byte("diff --git a/parser.go b/parser.go"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.ConflictsNavigate{Delta: 1})
+
+	var content string
+	test.SimulateModel(model, func() tea.Msg { return intents.ConflictsDiff{} }, func(msg tea.Msg) {
+		if show, ok := msg.(intents.DiffShow); ok {
+			content = show.Content
+		}
+	})
+	assert.Equal(t, "diff --git a/parser.go b/parser.go", content)
+}
+
+func TestApply_NavigatesToRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.ConflictedRevisions()).SetOutput([]byte(revisionsOutput))
+	commandRunner.Expect(jj.ResolveList("1a2b")).SetOutput([]byte("parser.go    2-sided conflict"))
+	commandRunner.Expect(jj.ResolveList("5e6f")).SetOutput([]byte(""))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.ConflictsNavigate{Delta: 2})
+
+	var navigated string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if navigate, ok := msg.(intents.Navigate); ok {
+			navigated = navigate.ChangeID
+		}
+	})
+	assert.Equal(t, "yqosqzyt", navigated)
+}
@@ -0,0 +1,17 @@
+package context
+
+import "github.com/idursun/jjui/internal/jj"
+
+// LoadBookmarkSyncs counts the commits each local bookmark is ahead and behind
+// of the tracked remote bookmarks it does not point to the same commit as. The
+// counts of all bookmarks come from a single jj invocation.
+func LoadBookmarkSyncs(runner CommandRunner, bookmarks []jj.Bookmark) []jj.BookmarkSync {
+	syncs := jj.OutOfSync(bookmarks)
+	if len(syncs) == 0 {
+		return syncs
+	}
+	if output, err := runner.RunCommandImmediate(jj.BookmarksAheadBehind(syncs)); err == nil {
+		jj.ParseAheadBehind(string(output), syncs)
+	}
+	return syncs
+}
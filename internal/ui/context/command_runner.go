@@ -23,6 +23,7 @@ type CommandRunner interface {
 	RunCommandImmediate(args []string) ([]byte, error)
 	RunCommandImmediateWithEnv(args []string, env []string) ([]byte, error)
 	RunCommandStreaming(ctx context.Context, args []string) (*StreamingCommand, error)
+	RunCommandWithProgress(args []string) (*ProgressCommand, error)
 	RunCommand(args []string, continuations ...tea.Cmd) tea.Cmd
 	RunCommandWithInput(args []string, input string, continuations ...tea.Cmd) tea.Cmd
 	RunInteractiveCommand(args []string, continuation tea.Cmd) tea.Cmd
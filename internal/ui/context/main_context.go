@@ -33,6 +33,17 @@ type MainContext struct {
 	TerminalThemeDetected     bool
 	Histories                 *config.Histories
 	ScriptVM                  *lua.LState
+	// UndoPoint lets undo revert an action that ran several jj commands in
+	// one step, as long as nothing else has happened to the repo since
+	UndoPoint *UndoPoint
+}
+
+// UndoPoint is the operation the repo was at before an action made of several
+// jj commands, and the operation the action left it at.
+type UndoPoint struct {
+	Description string
+	Before      string
+	After       string
 }
 
 func NewAppContext(location string, aps *askpass.Server) *MainContext {
@@ -0,0 +1,160 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrCommandCancelled is the error of a ProgressCommand killed by Cancel
+var ErrCommandCancelled = errors.New("cancelled")
+
+// ProgressCommand is a command whose output is read while it runs, so long
+// running commands like fetch and push can show what they are doing. jj only
+// draws its progress bars when writing to a terminal, so the output mostly
+// comes in whole lines.
+type ProgressCommand struct {
+	ID      int
+	Command string
+	chunks  chan string
+	done    chan struct{}
+	// stopped is closed by Cancel, after which the output is no longer handed
+	// to Next and the command never waits for a reader
+	stopped chan struct{}
+	stop    sync.Once
+	output  strings.Builder
+	err     error
+	cancel  func()
+}
+
+// Next blocks until the command writes more output, returning it along with
+// its line terminator, or false once the command has exited. Progress lines
+// end with "\r" as the next line replaces them.
+func (p *ProgressCommand) Next() (string, bool) {
+	chunk, ok := <-p.chunks
+	return chunk, ok
+}
+
+// Wait waits for the command to exit and returns all of its output. The
+// output not read by Next yet is skipped, so callers that only want the whole
+// output can call Wait right away.
+func (p *ProgressCommand) Wait() (string, error) {
+	for range p.chunks {
+	}
+	<-p.done
+	return p.output.String(), p.err
+}
+
+// Cancel kills the command. It is safe to call more than once and after the
+// command has exited.
+func (p *ProgressCommand) Cancel() {
+	p.stop.Do(func() { close(p.stopped) })
+	p.cancel()
+}
+
+// NewCompletedProgressCommand returns a command that has already exited with
+// output and err, for runners that cannot stream
+func NewCompletedProgressCommand(id int, command string, output string, err error) *ProgressCommand {
+	p := &ProgressCommand{
+		ID:      id,
+		Command: command,
+		chunks:  make(chan string, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		err:     err,
+		cancel:  func() {},
+	}
+	p.output.WriteString(output)
+	if output != "" {
+		p.chunks <- output
+	}
+	close(p.chunks)
+	close(p.done)
+	return p
+}
+
+func (a *MainCommandRunner) RunCommandWithProgress(args []string) (*ProgressCommand, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started, cancelAskpass, env := a.Askpass.NewSubprocess(strings.Join(args, " "))
+	p := &ProgressCommand{
+		ID:      a.nextID(),
+		Command: "jj " + strings.Join(args, " "),
+		chunks:  make(chan string),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		cancel:  cancel,
+	}
+	if !slices.Contains(args, "--color") {
+		args = append([]string{"--color", "always"}, args...)
+	}
+	c := exec.CommandContext(ctx, "jj", args...)
+	c.Dir = a.Location
+	c.Env = append(os.Environ(), env...)
+	reader, writer := io.Pipe()
+	// jj reports progress on stderr, both streams are read together to keep
+	// the order they were written in
+	c.Stdout = writer
+	c.Stderr = writer
+	if err := c.Start(); err != nil {
+		cancel()
+		cancelAskpass()
+		return nil, err
+	}
+	started(c.Process.Pid)
+
+	go func() {
+		err := c.Wait()
+		if ctx.Err() != nil {
+			err = ErrCommandCancelled
+		}
+		// set before closing the pipe, which lets the reader below finish
+		p.err = err
+		cancelAskpass()
+		writer.Close()
+	}()
+	go func() {
+		defer close(p.done)
+		defer close(p.chunks)
+		readChunks(reader, func(chunk string) {
+			p.output.WriteString(chunk)
+			select {
+			case p.chunks <- chunk:
+			case <-p.stopped:
+			}
+		})
+		if p.err != nil && !errors.Is(p.err, ErrCommandCancelled) {
+			p.err = errors.New(p.output.String())
+		}
+	}()
+	return p, nil
+}
+
+// readChunks reads r until it is closed, calling emit with every line as
+// soon as its "\n" or "\r" terminator is read
+func readChunks(r io.Reader, emit func(string)) {
+	buf := make([]byte, 4096)
+	var pending []byte
+	for {
+		n, err := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+		for {
+			i := slices.IndexFunc(pending, func(b byte) bool { return b == '\n' || b == '\r' })
+			if i < 0 {
+				break
+			}
+			emit(string(pending[:i+1]))
+			pending = pending[i+1:]
+		}
+		if err != nil {
+			if len(pending) > 0 {
+				emit(string(pending))
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/idursun/jjui/internal/askpass"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadChunks_SplitsOnLineAndProgressTerminators(t *testing.T) {
+	var chunks []string
+	readChunks(strings.NewReader("Fetching\nReceiving 10%\rReceiving 100%\r\ndone"), func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	assert.Equal(t, []string{"Fetching\n", "Receiving 10%\r", "Receiving 100%\r", "\n", "done"}, chunks)
+}
+
+func TestRunCommandWithProgress_CancelWithoutReadingOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for jj")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho Fetching from origin >&2\necho Receiving >&2\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(dir, "jj"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	runner := &MainCommandRunner{Location: dir, Askpass: askpass.NewUnstartedServer("JJUI_TEST")}
+	command, err := runner.RunCommandWithProgress([]string{"git", "fetch"})
+	require.NoError(t, err)
+	// nothing reads the output, like a progress panel closed while the
+	// command runs
+	time.Sleep(100 * time.Millisecond)
+	command.Cancel()
+
+	select {
+	case <-command.done:
+		assert.ErrorIs(t, command.err, ErrCommandCancelled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cancelled command never finished")
+	}
+}
@@ -1,10 +1,14 @@
 package diff
 
 import (
+	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/idursun/jjui/internal/ui/actions"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/dispatch"
@@ -15,7 +19,16 @@ import (
 
 type viewMode interface {
 	totalLines(width int) int
+	// rowOf returns the first row the given line is rendered at
+	rowOf(line int, width int) int
+	// lineAt returns the line rendered at the given row
+	lineAt(row int, width int) int
 	scrollHorizontal(delta int, viewportWidth int)
+	// matchRects returns where a search match is rendered, in columns of
+	// the viewport and rows of the whole content
+	matchRects(m match, width int) []layout.Rectangle
+	// revealColumns scrolls horizontally so the given columns are visible
+	revealColumns(start, end int, viewportWidth int)
 	ViewRect(dl *render.DisplayContext, box layout.Box, scrollY int)
 }
 
@@ -36,6 +49,14 @@ func (v *defaultView) totalLines(_ int) int {
 	return len(v.lines)
 }
 
+func (v *defaultView) rowOf(line int, _ int) int {
+	return line
+}
+
+func (v *defaultView) lineAt(row int, _ int) int {
+	return row
+}
+
 func (v *defaultView) scrollHorizontal(delta int, viewportWidth int) {
 	maxScroll := max(0, v.maxLineWidth-viewportWidth)
 	v.scrollX = max(0, min(v.scrollX+delta, maxScroll))
@@ -116,6 +137,22 @@ func (v *wrappedView) firstLine(scrollY int, width int) (line int, skip int) {
 	return idx, scrollY - v.visualRowStart[idx]
 }
 
+func (v *wrappedView) rowOf(line int, width int) int {
+	v.ensureIndex(width)
+	if line < len(v.visualRowStart) {
+		return v.visualRowStart[line]
+	}
+	if width <= 0 {
+		return line
+	}
+	return v.totalVisualRows
+}
+
+func (v *wrappedView) lineAt(row int, width int) int {
+	line, _ := v.firstLine(row, width)
+	return line
+}
+
 func (v *wrappedView) scrollHorizontal(_ int, _ int) {}
 
 func (v *wrappedView) ViewRect(dl *render.DisplayContext, box layout.Box, scrollY int) {
@@ -147,9 +184,25 @@ func (v *wrappedView) ViewRect(dl *render.DisplayContext, box layout.Box, scroll
 var _ common.ImmediateModel = (*Model)(nil)
 
 type Model struct {
+	// source holds every line of the diff, lines only the ones shown: the
+	// body of a collapsed file is left out. lineIndex maps each shown line to
+	// its index in source.
+	source       []string
+	files        []diffFile
 	lines        []string
+	lineIndex    []int
 	maxLineWidth int
 
+	showFileList bool
+	fileList     *render.ListRenderer
+
+	wrap       bool
+	sideBySide bool
+
+	search       string
+	matches      []match
+	currentMatch int
+
 	scrollY        int
 	viewportWidth  int
 	viewportHeight int
@@ -158,13 +211,19 @@ type Model struct {
 }
 
 func (m *Model) Scopes() []dispatch.Scope {
-	return []dispatch.Scope{
-		{
-			Name:    actions.ScopeDiff,
-			Leak:    dispatch.LeakGlobal,
+	var ret []dispatch.Scope
+	if m.search != "" {
+		ret = append(ret, dispatch.Scope{
+			Name:    actions.ScopeDiffQuickSearch,
+			Leak:    dispatch.LeakAll,
 			Handler: m,
-		},
+		})
 	}
+	return append(ret, dispatch.Scope{
+		Name:    actions.ScopeDiff,
+		Leak:    dispatch.LeakGlobal,
+		Handler: m,
+	})
 }
 
 func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
@@ -193,18 +252,63 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return nil, true
 
 	case intents.DiffToggleWrap:
-		switch m.mode.(type) {
-		case *wrappedView:
-			m.mode = newDefaultView(m.lines, m.maxLineWidth)
-		default:
-			m.mode = newWrappedView(m.lines)
+		m.wrap = !m.wrap
+		m.rebuild()
+		return nil, true
+
+	case intents.DiffToggleSideBySide:
+		if !m.sideBySide && m.noGitHunks() {
+			return intents.Invoke(intents.AddMessage{Text: "side by side needs a git style diff: " + gitDiffHint}), true
 		}
+		m.sideBySide = !m.sideBySide
+		m.rebuild()
 		return nil, true
 
 	case intents.DiffShow:
 		m.SetContent(msg.Content)
 		return nil, true
 
+	case intents.DiffJumpFile:
+		starts := make([]int, len(m.files))
+		for i, f := range m.files {
+			starts[i] = f.start
+		}
+		m.jump(starts, msg.Delta)
+		return nil, true
+
+	case intents.DiffJumpHunk:
+		if m.noGitHunks() {
+			return intents.Invoke(intents.AddMessage{Text: "no hunks to jump to: " + gitDiffHint}), true
+		}
+		var hunks []int
+		for _, f := range m.files {
+			if !f.collapsed {
+				hunks = append(hunks, f.hunks...)
+			}
+		}
+		m.jump(hunks, msg.Delta)
+		return nil, true
+
+	case intents.DiffToggleFileList:
+		m.showFileList = !m.showFileList
+		return nil, true
+
+	case intents.DiffToggleCollapse:
+		m.toggleCollapse(msg.All)
+		return nil, true
+
+	case intents.QuickSearchCycle:
+		delta := 1
+		if msg.Reverse {
+			delta = -1
+		}
+		m.cycleMatch(delta)
+		return nil, true
+
+	case intents.DiffQuickSearchClear:
+		m.setSearch("")
+		return nil, true
+
 	case intents.DiffScrollHorizontal:
 		switch msg.Kind {
 		case intents.DiffScrollLeft:
@@ -232,50 +336,179 @@ func (s ScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
 	return s
 }
 
+type fileClickedMsg struct {
+	index int
+}
+
 func (m *Model) clampScroll(width, height int) {
 	total := m.mode.totalLines(width)
 	m.scrollY = max(0, min(m.scrollY, max(0, total-height)))
 }
 
 func (m *Model) SetContent(content string) {
-	wrapped := false
-	if m.mode != nil {
-		_, wrapped = m.mode.(*wrappedView)
-	}
-
 	content = strings.ReplaceAll(content, "\r", "")
 	if content == "" {
 		content = "(empty)"
 	}
 
 	rawLines := strings.Split(content, "\n")
-	lines := make([]string, len(rawLines))
-	maxWidth := 0
+	source := make([]string, len(rawLines))
 	for i, line := range rawLines {
-		line = render.ExpandTabs(line)
-		lines[i] = line
+		source[i] = render.ExpandTabs(line)
+	}
+
+	m.source = source
+	m.files = parseFiles(source)
+	m.scrollY = 0
+	m.mode = nil
+	m.rebuild()
+}
+
+// rebuild recomputes the shown lines and the view mode after the content, the
+// layout or the collapsed state of a file changes. The horizontal scroll is
+// kept where the new mode supports it.
+func (m *Model) rebuild() {
+	scrollX := 0
+	switch mode := m.mode.(type) {
+	case *defaultView:
+		scrollX = mode.scrollX
+	case *splitView:
+		scrollX = mode.scrollX
+	}
+
+	collapsedStyle := common.DefaultPalette.Get("diff dimmed")
+	lines := make([]string, 0, len(m.source))
+	lineIndex := make([]int, 0, len(m.source))
+	maxWidth := 0
+	for i := 0; i < len(m.source); i++ {
+		line := m.source[i]
+		if f := m.fileOfLine(i); f >= 0 && m.files[f].collapsed {
+			file := m.files[f]
+			line += collapsedStyle.Render(fmt.Sprintf(" (%d lines hidden)", file.end-file.start-1))
+			i = file.end - 1
+			lines = append(lines, line)
+			lineIndex = append(lineIndex, file.start)
+		} else {
+			lines = append(lines, line)
+			lineIndex = append(lineIndex, i)
+		}
 		if w := render.StringWidth(line); w > maxWidth {
 			maxWidth = w
 		}
 	}
 
 	m.lines = lines
+	m.lineIndex = lineIndex
 	m.maxLineWidth = maxWidth
-	m.scrollY = 0
 
-	if wrapped {
+	switch {
+	case m.sideBySide:
+		view := newSplitView(lines, maxWidth)
+		view.scrollX = scrollX
+		m.mode = view
+	case m.wrap:
 		m.mode = newWrappedView(lines)
-		return
+	default:
+		view := newDefaultView(lines, maxWidth)
+		view.scrollX = scrollX
+		m.mode = view
+	}
+	m.refreshMatches()
+}
+
+// gitDiffHint explains how to get the `@@` hunk headers that hunk jumps and
+// the side by side layout rely on.
+const gitDiffHint = "run jj diff with --git or set ui.diff-formatter = \":git\""
+
+// noGitHunks reports whether the diff has content but none of it is in git
+// hunks, as with jj's default color-words format.
+func (m *Model) noGitHunks() bool {
+	return len(m.files) > 0 && !hasHunks(m.source)
+}
+
+// fileOfLine returns the index of the file containing the given source line,
+// or -1 if the line comes before the first file.
+func (m *Model) fileOfLine(line int) int {
+	i := sort.Search(len(m.files), func(i int) bool { return m.files[i].start > line }) - 1
+	if i < 0 || line >= m.files[i].end {
+		return -1
+	}
+	return i
+}
+
+// topLine returns the source line shown at the top of the viewport
+func (m *Model) topLine() int {
+	if len(m.lineIndex) == 0 {
+		return 0
+	}
+	line := m.mode.lineAt(m.scrollY, m.viewportWidth)
+	return m.lineIndex[max(0, min(line, len(m.lineIndex)-1))]
+}
+
+func (m *Model) currentFile() int {
+	return m.fileOfLine(m.topLine())
+}
+
+func (m *Model) rowOfSource(line int) int {
+	return m.mode.rowOf(sort.SearchInts(m.lineIndex, line), m.viewportWidth)
+}
+
+// jump scrolls to the next (delta > 0) or previous (delta < 0) of the given
+// source lines relative to the top of the viewport. targets must be sorted.
+func (m *Model) jump(targets []int, delta int) {
+	for ; delta > 0; delta-- {
+		i := slices.IndexFunc(targets, func(t int) bool { return m.rowOfSource(t) > m.scrollY })
+		if i < 0 {
+			return
+		}
+		m.scrollY = m.rowOfSource(targets[i])
+	}
+	for ; delta < 0; delta++ {
+		i := len(targets) - 1
+		for i >= 0 && m.rowOfSource(targets[i]) >= m.scrollY {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		m.scrollY = m.rowOfSource(targets[i])
+	}
+}
+
+func (m *Model) toggleCollapse(all bool) {
+	current := m.currentFile()
+	if all {
+		collapse := slices.ContainsFunc(m.files, func(f diffFile) bool { return !f.collapsed })
+		for i := range m.files {
+			m.files[i].collapsed = collapse
+		}
+	} else if current >= 0 {
+		m.files[current].collapsed = !m.files[current].collapsed
+	}
+	m.rebuild()
+	if current >= 0 {
+		m.scrollY = m.rowOfSource(m.files[current].start)
 	}
-	m.mode = newDefaultView(lines, maxWidth)
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
-	case intents.DiffScroll, intents.DiffToggleWrap, intents.DiffShow, intents.DiffScrollHorizontal:
+	case intents.DiffScroll, intents.DiffToggleWrap, intents.DiffShow, intents.DiffScrollHorizontal,
+		intents.DiffToggleSideBySide, intents.DiffJumpFile, intents.DiffJumpHunk, intents.DiffToggleFileList, intents.DiffToggleCollapse,
+		intents.QuickSearchCycle, intents.DiffQuickSearchClear:
 		cmd, _ := m.HandleIntent(msg.(intents.Intent))
 		return cmd
 
+	case common.DiffSearchMsg:
+		m.setSearch(string(msg))
+		return nil
+
+	case fileClickedMsg:
+		if msg.index >= 0 && msg.index < len(m.files) {
+			m.scrollY = m.rowOfSource(m.files[msg.index].start)
+		}
+		return nil
+
 	case ScrollMsg:
 		if !msg.Horizontal {
 			m.scrollY += msg.Delta
@@ -288,6 +521,12 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 }
 
 func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	if m.showFileList && len(m.files) > 0 {
+		var listBox layout.Box
+		listBox, box = box.CutLeft(m.fileListWidth(box.R.Dx()))
+		m.renderFileList(dl, listBox)
+	}
+
 	width := box.R.Dx()
 	height := box.R.Dy()
 	m.viewportWidth = width
@@ -295,11 +534,68 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.clampScroll(width, height)
 
 	m.mode.ViewRect(dl, box, m.scrollY)
+	m.renderMatches(dl, box)
 	dl.AddInteraction(box.R, ScrollMsg{}, render.InteractionScroll, 0)
 }
 
+// ScrollY returns the row shown at the top of the viewport
+func (m *Model) ScrollY() int {
+	return m.scrollY
+}
+
+// SetScrollY scrolls the viewport to show row at its top, for views like the
+// diff editor that move through the diff with a cursor of their own
+func (m *Model) SetScrollY(row int) {
+	m.scrollY = max(row, 0)
+}
+
+func (m *Model) fileListWidth(width int) int {
+	longest := 0
+	for _, f := range m.files {
+		longest = max(longest, render.StringWidth(f.name))
+	}
+	// room for the collapse marker and a separating column
+	return min(longest+3, width/3)
+}
+
+func (m *Model) renderFileList(dl *render.DisplayContext, box layout.Box) {
+	textStyle := common.DefaultPalette.Get("diff file_list text")
+	selectedStyle := common.DefaultPalette.Get("diff file_list selected")
+	dl.AddFill(box.R, ' ', textStyle, 0)
+
+	current := m.currentFile()
+	width := max(box.R.Dx()-1, 0)
+	m.fileList.Render(
+		dl,
+		box,
+		len(m.files),
+		current,
+		true,
+		func(int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			file := m.files[index]
+			style := textStyle
+			if index == current {
+				style = selectedStyle
+			}
+			marker := "  "
+			if file.collapsed {
+				marker = "+ "
+			}
+			rect.Max.X = rect.Min.X + width
+			dl.AddFill(rect, ' ', style, 0)
+			dl.Text(rect.Min.X, rect.Min.Y, 0).
+				Styled(ansi.Truncate(marker+file.name, width, "…"), style).
+				Done()
+		},
+		func(index int, _ tea.Mouse) render.ClickMessage {
+			return fileClickedMsg{index: index}
+		},
+	)
+}
+
 func New(output string) *Model {
-	model := &Model{}
+	model := &Model{fileList: render.NewListRenderer(nil)}
 	model.SetContent(output)
 	return model
 }
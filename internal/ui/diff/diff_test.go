@@ -7,6 +7,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew_TrimsCarriageReturnsAndHandlesEmpty(t *testing.T) {
@@ -165,3 +166,84 @@ func TestTabs_WrapUsingExpandedWidth(t *testing.T) {
 	rendered := test.RenderImmediate(model, 5, 2)
 	assert.Equal(t, "    1\n23456", rendered)
 }
+
+func TestJumpFile_ScrollsToFileHeaders(t *testing.T) {
+	model := New(gitDiff)
+	test.RenderImmediate(model, 40, 5)
+
+	model.Update(intents.DiffJumpFile{Delta: 1})
+	rendered := test.Stripped(test.RenderImmediate(model, 40, 5))
+	assert.True(t, strings.HasPrefix(rendered, "diff --git a/dir/b.txt"))
+
+	model.Update(intents.DiffJumpFile{Delta: -1})
+	rendered = test.Stripped(test.RenderImmediate(model, 40, 5))
+	assert.True(t, strings.HasPrefix(rendered, "diff --git a/a.txt"))
+}
+
+func TestJumpHunk_StepsThroughHunksAcrossFiles(t *testing.T) {
+	model := New(gitDiff)
+	test.RenderImmediate(model, 40, 2)
+
+	var tops []string
+	for range 3 {
+		model.Update(intents.DiffJumpHunk{Delta: 1})
+		rendered := test.Stripped(test.RenderImmediate(model, 40, 2))
+		tops = append(tops, strings.Split(rendered, "\n")[0])
+	}
+	assert.Equal(t, []string{"@@ -1,2 +1,2 @@", "@@ -10,1 +10,1 @@", "@@ -0,0 +1,1 @@"}, tops)
+}
+
+func TestJumpHunk_ReportsColorWordsDiff(t *testing.T) {
+	model := New("Modified regular file a.txt:\n   1    1: first\n   2    2: second")
+	test.RenderImmediate(model, 40, 2)
+
+	cmd := model.Update(intents.DiffJumpHunk{Delta: 1})
+	require.NotNil(t, cmd)
+	added, ok := cmd().(intents.AddMessage)
+	require.True(t, ok)
+	assert.Contains(t, added.Text, "--git")
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 40, 2)), "Modified regular file a.txt:")
+}
+
+func TestToggleSideBySide_ReportsColorWordsDiff(t *testing.T) {
+	model := New("Modified regular file a.txt:\n   1    1: first")
+	test.RenderImmediate(model, 40, 2)
+
+	cmd := model.Update(intents.DiffToggleSideBySide{})
+	require.NotNil(t, cmd)
+	added, ok := cmd().(intents.AddMessage)
+	require.True(t, ok)
+	assert.Contains(t, added.Text, "--git")
+	assert.False(t, model.sideBySide)
+}
+
+func TestToggleCollapse_HidesFileBody(t *testing.T) {
+	model := New(gitDiff)
+	test.RenderImmediate(model, 60, 20)
+
+	model.Update(intents.DiffToggleCollapse{})
+	rendered := test.Stripped(test.RenderImmediate(model, 60, 20))
+	assert.Contains(t, rendered, "diff --git a/a.txt b/a.txt (10 lines hidden)")
+	assert.NotContains(t, rendered, "+ONE")
+	assert.Contains(t, rendered, "+new")
+
+	model.Update(intents.DiffToggleCollapse{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 60, 20)), "+ONE")
+}
+
+func TestToggleCollapseAll_CollapsesEveryFile(t *testing.T) {
+	model := New(gitDiff)
+	model.Update(intents.DiffToggleCollapse{All: true})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 60, 20))
+	assert.Equal(t, 2, strings.Count(rendered, "lines hidden"))
+}
+
+func TestToggleFileList_ShowsFileNames(t *testing.T) {
+	model := New(gitDiff)
+	model.Update(intents.DiffToggleFileList{})
+
+	lines := strings.Split(test.Stripped(test.RenderImmediate(model, 80, 20)), "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "a.txt"))
+	assert.True(t, strings.HasPrefix(lines[1], "dir/b.txt"))
+}
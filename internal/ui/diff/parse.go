@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// diffFile is a file section of the diff. start and end are line indices into
+// the full diff, end being exclusive. hunks holds the line indices of the
+// hunk headers within the file.
+type diffFile struct {
+	name      string
+	start     int
+	end       int
+	hunks     []int
+	collapsed bool
+}
+
+var (
+	gitHeaderRe = regexp.MustCompile(`^diff --git a/(.*) b/(.*)$`)
+	// jj's own diff formats start each file with a line like
+	// "Modified regular file src/main.go:"
+	jjHeaderRe = regexp.MustCompile(`^(?:Added|Modified|Removed|Copied|Renamed) (?:regular|executable|symlink|conflicted|git submodule|binary)? ?file (.*):$`)
+)
+
+// parseFiles splits the lines of a `jj diff --git` style output into files
+// and hunks. Lines are matched with colours stripped, so the coloured output
+// of jj can be used as is.
+func parseFiles(lines []string) []diffFile {
+	var files []diffFile
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		if name, ok := fileHeader(plain); ok {
+			if n := len(files); n > 0 {
+				files[n-1].end = i
+			}
+			files = append(files, diffFile{name: name, start: i})
+			continue
+		}
+		if len(files) > 0 && strings.HasPrefix(plain, "@@ ") {
+			current := &files[len(files)-1]
+			current.hunks = append(current.hunks, i)
+		}
+	}
+	if n := len(files); n > 0 {
+		files[n-1].end = len(lines)
+	}
+	return files
+}
+
+func fileHeader(line string) (string, bool) {
+	if m := gitHeaderRe.FindStringSubmatch(line); m != nil {
+		return m[2], true
+	}
+	if m := jjHeaderRe.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitDiff = `diff --git a/a.txt b/a.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+-one
++ONE
+ two
+@@ -10,1 +10,1 @@
+-ten
++TEN
+diff --git a/dir/b.txt b/dir/b.txt
+new file mode 100644
+--- /dev/null
++++ b/dir/b.txt
+@@ -0,0 +1,1 @@
++new`
+
+func TestParseFiles_GitFormat(t *testing.T) {
+	files := parseFiles(strings.Split(gitDiff, "\n"))
+	require.Len(t, files, 2)
+
+	assert.Equal(t, "a.txt", files[0].name)
+	assert.Equal(t, 0, files[0].start)
+	assert.Equal(t, 11, files[0].end)
+	assert.Equal(t, []int{4, 8}, files[0].hunks)
+
+	assert.Equal(t, "dir/b.txt", files[1].name)
+	assert.Equal(t, 11, files[1].start)
+	assert.Equal(t, 17, files[1].end)
+	assert.Equal(t, []int{15}, files[1].hunks)
+}
+
+func TestParseFiles_StripsColours(t *testing.T) {
+	lines := []string{"\x1b[1mdiff --git a/x.go b/x.go\x1b[0m", "\x1b[36m@@ -1 +1 @@\x1b[0m"}
+	files := parseFiles(lines)
+	require.Len(t, files, 1)
+	assert.Equal(t, "x.go", files[0].name)
+	assert.Equal(t, []int{1}, files[0].hunks)
+}
+
+func TestParseFiles_JjFormatHeaders(t *testing.T) {
+	lines := []string{"Modified regular file src/main.go:", "   1    1: package main", "Added regular file README.md:", "        1: hello"}
+	files := parseFiles(lines)
+	require.Len(t, files, 2)
+	assert.Equal(t, "src/main.go", files[0].name)
+	assert.Equal(t, "README.md", files[1].name)
+}
+
+func TestParseFiles_PlainTextHasNoFiles(t *testing.T) {
+	assert.Empty(t, parseFiles([]string{"just", "some", "text"}))
+}
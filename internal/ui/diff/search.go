@@ -0,0 +1,178 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// match is an occurrence of the search query. line is an index into the shown
+// lines, start and end are display columns within the line.
+type match struct {
+	line  int
+	start int
+	end   int
+}
+
+func findMatches(lines []string, query string) []match {
+	if query == "" {
+		return nil
+	}
+	var matches []match
+	for i, line := range lines {
+		plain := strings.ToLower(ansi.Strip(line))
+		offset := 0
+		for {
+			index := strings.Index(plain[offset:], query)
+			if index < 0 {
+				break
+			}
+			start := offset + index
+			end := start + len(query)
+			matches = append(matches, match{
+				line:  i,
+				start: ansi.StringWidth(plain[:start]),
+				end:   ansi.StringWidth(plain[:end]),
+			})
+			offset = end
+		}
+	}
+	return matches
+}
+
+func (v *defaultView) matchRects(m match, _ int) []layout.Rectangle {
+	return []layout.Rectangle{layout.Rect(m.start-v.scrollX, m.line, m.end-m.start, 1)}
+}
+
+func (v *defaultView) revealColumns(start, end int, viewportWidth int) {
+	if start < v.scrollX || end > v.scrollX+viewportWidth {
+		v.scrollHorizontal(start-viewportWidth/3-v.scrollX, viewportWidth)
+	}
+}
+
+func (v *wrappedView) matchRects(m match, width int) []layout.Rectangle {
+	v.ensureIndex(width)
+	if width <= 0 || m.line >= len(v.visualRowStart) {
+		return nil
+	}
+	var rects []layout.Rectangle
+	for col := m.start; col < m.end; {
+		rowEnd := (col/width + 1) * width
+		segmentEnd := min(m.end, rowEnd)
+		rects = append(rects, layout.Rect(col%width, v.visualRowStart[m.line]+col/width, segmentEnd-col, 1))
+		col = segmentEnd
+	}
+	return rects
+}
+
+func (v *wrappedView) revealColumns(int, int, int) {}
+
+func (v *splitView) matchRects(m match, width int) []layout.Rectangle {
+	if m.line >= len(v.lineRow) {
+		return nil
+	}
+	rowIndex := v.lineRow[m.line]
+	row := v.rows[rowIndex]
+	if row.full {
+		return []layout.Rectangle{layout.Rect(m.start-v.scrollX, rowIndex, m.end-m.start, 1)}
+	}
+
+	leftWidth := (width - 1) / 2
+	rightWidth := width - leftWidth - 1
+	var rects []layout.Rectangle
+	clip := func(x0, w int) {
+		start := max(m.start-v.scrollX, 0)
+		end := min(m.end-v.scrollX, w)
+		if start < end {
+			rects = append(rects, layout.Rect(x0+start, rowIndex, end-start, 1))
+		}
+	}
+	if row.left == m.line {
+		clip(0, leftWidth)
+	}
+	if row.right == m.line {
+		clip(leftWidth+1, rightWidth)
+	}
+	return rects
+}
+
+func (v *splitView) revealColumns(start, end int, viewportWidth int) {
+	half := (viewportWidth - 1) / 2
+	if start < v.scrollX || end > v.scrollX+half {
+		v.scrollHorizontal(start-half/3-v.scrollX, viewportWidth)
+	}
+}
+
+// setSearch starts a new search and moves to the first match at or below the
+// top of the viewport.
+func (m *Model) setSearch(query string) {
+	m.search = strings.ToLower(query)
+	m.matches = findMatches(m.lines, m.search)
+	m.currentMatch = -1
+	if len(m.matches) == 0 {
+		return
+	}
+	top := m.mode.lineAt(m.scrollY, m.viewportWidth)
+	first := slices.IndexFunc(m.matches, func(mt match) bool { return mt.line >= top })
+	m.selectMatch(max(first, 0))
+}
+
+// refreshMatches finds the matches again after the shown lines change
+func (m *Model) refreshMatches() {
+	m.matches = findMatches(m.lines, m.search)
+	m.currentMatch = min(m.currentMatch, len(m.matches)-1)
+}
+
+func (m *Model) cycleMatch(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	n := len(m.matches)
+	m.selectMatch(((m.currentMatch+delta)%n + n) % n)
+}
+
+// selectMatch makes the given match current and scrolls it into view, a third
+// of the way down the viewport.
+func (m *Model) selectMatch(index int) {
+	m.currentMatch = index
+	mt := m.matches[index]
+	m.mode.revealColumns(mt.start, mt.end, m.viewportWidth)
+	if rects := m.mode.matchRects(mt, m.viewportWidth); len(rects) > 0 {
+		m.scrollY = rects[0].Min.Y - m.viewportHeight/3
+	}
+}
+
+func (m *Model) renderMatches(dl *render.DisplayContext, box layout.Box) {
+	if m.search == "" {
+		return
+	}
+	matchedStyle := common.DefaultPalette.Get("diff matched")
+	currentStyle := common.DefaultPalette.Get("diff matched selected")
+	for i, mt := range m.matches {
+		style := matchedStyle
+		if i == m.currentMatch {
+			style = currentStyle
+		}
+		for _, rect := range m.mode.matchRects(mt, m.viewportWidth) {
+			rect = rect.Add(box.R.Min).Sub(layout.Pos(0, m.scrollY))
+			if rect = rect.Intersect(box.R); !rect.Empty() {
+				dl.AddStyle(rect, style, 1)
+			}
+		}
+	}
+
+	counter := "no matches"
+	if len(m.matches) > 0 {
+		counter = fmt.Sprintf("%d/%d", m.currentMatch+1, len(m.matches))
+	}
+	counter = fmt.Sprintf(" /%s %s ", m.search, counter)
+	counterStyle := common.DefaultPalette.Get("diff search")
+	width := min(render.StringWidth(counter), box.R.Dx())
+	rect := layout.Rect(box.R.Max.X-width, box.R.Min.Y, width, 1)
+	dl.AddDraw(rect, counterStyle.Render(ansi.Truncate(counter, width, "")), 2)
+}
@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMatches_CaseInsensitiveWithColumns(t *testing.T) {
+	lines := []string{"\x1b[31m-Foo foo\x1b[0m", "bar"}
+	assert.Equal(t, []match{{line: 0, start: 1, end: 4}, {line: 0, start: 5, end: 8}}, findMatches(lines, "foo"))
+	assert.Empty(t, findMatches(lines, ""))
+}
+
+func TestSearch_ScrollsToFirstMatchAndCounts(t *testing.T) {
+	model := New("1\n2\n3\n4\nneedle\n6\n7\nneedle")
+	test.RenderImmediate(model, 30, 3)
+
+	model.Update(common.DiffSearchMsg("needle"))
+	rendered := test.Stripped(test.RenderImmediate(model, 30, 3))
+	assert.Contains(t, rendered, "/needle 1/2")
+	assert.Contains(t, rendered, "needle")
+	assert.NotContains(t, rendered, "1\n")
+
+	model.Update(intents.QuickSearchCycle{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 30, 3)), "/needle 2/2")
+
+	model.Update(intents.QuickSearchCycle{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 30, 3)), "/needle 1/2")
+
+	model.Update(intents.QuickSearchCycle{Reverse: true})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 30, 3)), "/needle 2/2")
+}
+
+func TestSearch_ReportsNoMatches(t *testing.T) {
+	model := New("a\nb")
+	model.Update(common.DiffSearchMsg("zzz"))
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 30, 3)), "/zzz no matches")
+}
+
+func TestSearch_ClearRemovesScopeAndCounter(t *testing.T) {
+	model := New("a\nb")
+	model.Update(common.DiffSearchMsg("a"))
+	assert.Len(t, model.Scopes(), 2)
+
+	model.Update(intents.DiffQuickSearchClear{})
+	assert.Len(t, model.Scopes(), 1)
+	assert.Equal(t, "a\nb", test.Stripped(test.RenderImmediate(model, 30, 3)))
+}
+
+func TestSearch_RevealsMatchBeyondHorizontalScroll(t *testing.T) {
+	model := New(strings.Repeat(".", 50) + "needle")
+	test.RenderImmediate(model, 20, 2)
+
+	model.Update(common.DiffSearchMsg("needle"))
+	rendered := test.Stripped(test.RenderImmediate(model, 20, 2))
+	assert.Contains(t, rendered, "needle")
+}
+
+func TestMatchRects_WrappedMatchSpansRows(t *testing.T) {
+	view := newWrappedView([]string{"0123456789"})
+	rects := view.matchRects(match{line: 0, start: 3, end: 7}, 5)
+	assert.Equal(t, []layout.Rectangle{layout.Rect(3, 0, 2, 1), layout.Rect(0, 1, 2, 1)}, rects)
+}
+
+func TestMatchRects_SplitViewHighlightsContextOnBothSides(t *testing.T) {
+	view := newSplitView([]string{"@@ -1 +1 @@", " ctx"}, 4)
+	rects := view.matchRects(match{line: 1, start: 1, end: 4}, 21)
+	assert.Equal(t, []layout.Rectangle{layout.Rect(1, 1, 3, 1), layout.Rect(12, 1, 3, 1)}, rects)
+}
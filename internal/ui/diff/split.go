@@ -0,0 +1,190 @@
+package diff
+
+import (
+	"strings"
+
+	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// splitRow is a row of the side-by-side layout. left and right are line
+// indices, -1 leaving that side empty. Rows outside of hunks (file headers,
+// hunk headers) span the full width.
+type splitRow struct {
+	left  int
+	right int
+	full  bool
+}
+
+// splitRows lays out the lines of a git style diff side by side. Within a
+// hunk, runs of removed lines are paired with the added lines that follow
+// them, and context lines are shown on both sides.
+func splitRows(lines []string) []splitRow {
+	var rows []splitRow
+	var removed, added []int
+	flush := func() {
+		for i := range max(len(removed), len(added)) {
+			row := splitRow{left: -1, right: -1}
+			if i < len(removed) {
+				row.left = removed[i]
+			}
+			if i < len(added) {
+				row.right = added[i]
+			}
+			rows = append(rows, row)
+		}
+		removed, added = removed[:0], added[:0]
+	}
+
+	inHunk := false
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		if _, ok := fileHeader(plain); ok {
+			inHunk = false
+		} else if strings.HasPrefix(plain, "@@ ") {
+			inHunk = true
+		} else if inHunk {
+			switch {
+			case strings.HasPrefix(plain, "-"):
+				if len(added) > 0 {
+					flush()
+				}
+				removed = append(removed, i)
+				continue
+			case strings.HasPrefix(plain, "+"):
+				added = append(added, i)
+				continue
+			case plain == "", strings.HasPrefix(plain, " "):
+				flush()
+				rows = append(rows, splitRow{left: i, right: i})
+				continue
+			}
+		}
+		flush()
+		rows = append(rows, splitRow{left: i, right: i, full: true})
+	}
+	flush()
+	return rows
+}
+
+func hasHunks(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(ansi.Strip(line), "@@ ") {
+			return true
+		}
+	}
+	return false
+}
+
+// composeRow renders a row of the side-by-side layout as a single line of the
+// given width, with both sides scrolled horizontally by scrollX.
+func composeRow(lines []string, row splitRow, width int, scrollX int) string {
+	if row.full {
+		return ansi.Cut(lines[row.left], scrollX, scrollX+width)
+	}
+	leftWidth := (width - 1) / 2
+	rightWidth := width - leftWidth - 1
+	separator := common.DefaultPalette.Get("diff dimmed").Render("│")
+
+	cell := func(index int, w int) string {
+		if index < 0 {
+			return strings.Repeat(" ", w)
+		}
+		content := ansi.Cut(lines[index], scrollX, scrollX+w)
+		// reset the style so it does not bleed into the separator
+		return content + "\x1b[m" + strings.Repeat(" ", max(0, w-ansi.StringWidth(content)))
+	}
+	return cell(row.left, leftWidth) + separator + cell(row.right, rightWidth)
+}
+
+type splitView struct {
+	lines        []string
+	rows         []splitRow
+	lineRow      []int
+	maxLineWidth int
+	scrollX      int
+}
+
+func newSplitView(lines []string, maxLineWidth int) *splitView {
+	rows := splitRows(lines)
+	lineRow := make([]int, len(lines))
+	for i := len(rows) - 1; i >= 0; i-- {
+		if rows[i].left >= 0 {
+			lineRow[rows[i].left] = i
+		}
+		if rows[i].right >= 0 {
+			lineRow[rows[i].right] = i
+		}
+	}
+	return &splitView{
+		lines:        lines,
+		rows:         rows,
+		lineRow:      lineRow,
+		maxLineWidth: maxLineWidth,
+	}
+}
+
+func (v *splitView) totalLines(_ int) int {
+	return len(v.rows)
+}
+
+func (v *splitView) rowOf(line int, _ int) int {
+	if line < len(v.lineRow) {
+		return v.lineRow[line]
+	}
+	return len(v.rows)
+}
+
+func (v *splitView) lineAt(row int, _ int) int {
+	if row < 0 || row >= len(v.rows) {
+		return row
+	}
+	if v.rows[row].left >= 0 {
+		return v.rows[row].left
+	}
+	return v.rows[row].right
+}
+
+func (v *splitView) scrollHorizontal(delta int, viewportWidth int) {
+	maxScroll := max(0, v.maxLineWidth-(viewportWidth-1)/2)
+	v.scrollX = max(0, min(v.scrollX+delta, maxScroll))
+}
+
+func (v *splitView) ViewRect(dl *render.DisplayContext, box layout.Box, scrollY int) {
+	width := box.R.Dx()
+	height := box.R.Dy()
+	buf := render.NewScreenBuffer(width, height)
+	firstRow := max(0, scrollY)
+	for i := range height {
+		row := firstRow + i
+		if row >= len(v.rows) {
+			break
+		}
+		ss := uv.NewStyledString(composeRow(v.lines, v.rows[row], width, v.scrollX))
+		ss.Wrap = false
+		ss.Draw(buf, uv.Rect(0, i, width, 1))
+	}
+	dl.AddDraw(box.R, buf.Render(), 0)
+}
+
+// RenderSideBySide lays out a git style diff side by side at the given width.
+// It reports false if the content does not contain any hunks, in which case it
+// is better shown as is.
+func RenderSideBySide(content string, width int) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r", ""), "\n")
+	if !hasHunks(lines) {
+		return "", false
+	}
+	for i, line := range lines {
+		lines[i] = render.ExpandTabs(line)
+	}
+	rows := splitRows(lines)
+	rendered := make([]string, len(rows))
+	for i, row := range rows {
+		rendered[i] = composeRow(lines, row, width, 0)
+	}
+	return strings.Join(rendered, "\n"), true
+}
@@ -0,0 +1,57 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitRows_PairsRemovedWithAddedLines(t *testing.T) {
+	lines := strings.Split("@@ -1,3 +1,3 @@\n ctx\n-a\n-b\n+A\n ctx", "\n")
+	assert.Equal(t, []splitRow{
+		{left: 0, right: 0, full: true},
+		{left: 1, right: 1},
+		{left: 2, right: 4},
+		{left: 3, right: -1},
+		{left: 5, right: 5},
+	}, splitRows(lines))
+}
+
+func TestSplitRows_AddedBeforeRemovedStartsNewRun(t *testing.T) {
+	lines := strings.Split("@@ -1 +1 @@\n+x\n-y", "\n")
+	assert.Equal(t, []splitRow{
+		{left: 0, right: 0, full: true},
+		{left: -1, right: 1},
+		{left: 2, right: -1},
+	}, splitRows(lines))
+}
+
+func TestToggleSideBySide_ShowsOldAndNewInColumns(t *testing.T) {
+	model := New("diff --git a/f b/f\n@@ -1,2 +1,2 @@\n-one\n+ONE\n two")
+	model.Update(intents.DiffToggleSideBySide{})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 21, 5))
+	assert.Equal(t, strings.Join([]string{
+		"diff --git a/f b/f",
+		"@@ -1,2 +1,2 @@",
+		"-one      │+ONE",
+		"two      │ two",
+	}, "\n"), rendered)
+
+	model.Update(intents.DiffToggleSideBySide{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 21, 5)), "-one\n+ONE")
+}
+
+func TestSideBySide_JumpHunkUsesRows(t *testing.T) {
+	model := New(gitDiff)
+	model.Update(intents.DiffToggleSideBySide{})
+	test.RenderImmediate(model, 60, 2)
+
+	model.Update(intents.DiffJumpHunk{Delta: 1})
+	model.Update(intents.DiffJumpHunk{Delta: 1})
+	rendered := test.Stripped(test.RenderImmediate(model, 60, 2))
+	assert.True(t, strings.HasPrefix(rendered, "@@ -10,1 +10,1 @@"))
+}
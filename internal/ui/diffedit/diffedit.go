@@ -0,0 +1,395 @@
+package diffedit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/idursun/jjui/internal/textdiff"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/diff"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+type rowKind int
+
+const (
+	fileRow rowKind = iota
+	hunkRow
+	lineRow
+)
+
+type row struct {
+	kind rowKind
+	file int
+	hunk int
+	op   int
+}
+
+type itemClickedMsg struct {
+	Index int
+}
+
+// gutterWidth is the width of the column left of the diff holding the
+// collapse markers and checkboxes
+const gutterWidth = 8
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ dispatch.ScopeProvider = (*Model)(nil)
+
+// Model lets the user pick which changes between two directories are kept.
+// It implements the diff editor protocol of jj: the right directory is
+// rewritten to contain only the selected changes. The diff itself is drawn by
+// the diff view, one line per row, with the selection in a gutter next to it.
+type Model struct {
+	left                string
+	right               string
+	files               []*file
+	rows                []row
+	cursor              int
+	diff                *diff.Model
+	height              int
+	ensureCursorVisible bool
+	applied             bool
+	err                 error
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeDiffEditor,
+			Leak:    dispatch.LeakNone,
+			Handler: m,
+		},
+	}
+}
+
+// Applied reports whether the selection was written to the right directory.
+func (m *Model) Applied() bool {
+	return m.applied
+}
+
+// Err returns the error encountered while writing the selection, if any.
+func (m *Model) Err() error {
+	return m.err
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case itemClickedMsg:
+		if msg.Index >= 0 && msg.Index < len(m.rows) {
+			m.cursor = msg.Index
+			m.ensureCursorVisible = true
+		}
+	case diff.ScrollMsg:
+		m.ensureCursorVisible = false
+		return m.diff.Update(msg)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.DiffEditorNavigate:
+		delta := intent.Delta
+		if intent.IsPage {
+			delta *= max(m.height-1, 1)
+		}
+		m.moveCursor(m.cursor + delta)
+		return nil, true
+	case intents.DiffEditorJumpFile:
+		m.jumpTo(intent.Delta, fileRow)
+		return nil, true
+	case intents.DiffEditorJumpHunk:
+		m.jumpTo(intent.Delta, hunkRow)
+		return nil, true
+	case intents.DiffEditorToggle:
+		m.toggle()
+		return nil, true
+	case intents.DiffEditorToggleAll:
+		value := !m.allSelected()
+		for _, f := range m.files {
+			f.setAll(value)
+		}
+		return nil, true
+	case intents.DiffEditorToggleCollapse:
+		if len(m.rows) == 0 {
+			return nil, true
+		}
+		fileIndex := m.rows[m.cursor].file
+		m.files[fileIndex].collapsed = !m.files[fileIndex].collapsed
+		m.buildRows()
+		m.moveCursor(m.fileRowIndex(fileIndex))
+		return nil, true
+	case intents.Apply:
+		if err := writeFiles(m.left, m.right, m.files); err != nil {
+			m.err = err
+			return tea.Quit, true
+		}
+		m.applied = true
+		return tea.Quit, true
+	case intents.Cancel:
+		return tea.Quit, true
+	}
+	return nil, false
+}
+
+func (m *Model) moveCursor(index int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	m.cursor = max(0, min(index, len(m.rows)-1))
+	m.ensureCursorVisible = true
+}
+
+func (m *Model) jumpTo(delta int, kind rowKind) {
+	for i := m.cursor + delta; i >= 0 && i < len(m.rows); i += delta {
+		if m.rows[i].kind == kind {
+			m.moveCursor(i)
+			return
+		}
+	}
+}
+
+func (m *Model) fileRowIndex(fileIndex int) int {
+	for i, r := range m.rows {
+		if r.kind == fileRow && r.file == fileIndex {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *Model) toggle() {
+	if len(m.rows) == 0 {
+		return
+	}
+	r := m.rows[m.cursor]
+	f := m.files[r.file]
+	switch r.kind {
+	case fileRow:
+		selected, total := f.selection()
+		f.setAll(selected != total)
+	case hunkRow:
+		h := f.hunks[r.hunk]
+		selected, total := f.hunkSelection(h)
+		f.setRange(h.Start, h.End, selected != total)
+	case lineRow:
+		if f.isChange(r.op) {
+			f.selected[r.op] = !f.selected[r.op]
+		}
+	}
+}
+
+func (m *Model) allSelected() bool {
+	for _, f := range m.files {
+		if selected, total := f.selection(); selected != total {
+			return false
+		}
+	}
+	return true
+}
+
+// buildRows lays out the rows of the files and hands their lines to the diff
+// view, keeping it scrolled where it was
+func (m *Model) buildRows() {
+	m.rows = m.rows[:0]
+	for fi, f := range m.files {
+		m.rows = append(m.rows, row{kind: fileRow, file: fi})
+		if f.collapsed {
+			continue
+		}
+		for hi, h := range f.hunks {
+			m.rows = append(m.rows, row{kind: hunkRow, file: fi, hunk: hi})
+			for op := h.Start; op < h.End; op++ {
+				m.rows = append(m.rows, row{kind: lineRow, file: fi, hunk: hi, op: op})
+			}
+		}
+	}
+
+	lines := make([]string, len(m.rows))
+	for i, r := range m.rows {
+		lines[i] = m.rowLine(r)
+	}
+	scrollY := m.diff.ScrollY()
+	m.diff.SetContent(strings.Join(lines, "\n"))
+	m.diff.SetScrollY(scrollY)
+}
+
+func checkbox(selected, total int) string {
+	switch {
+	case selected == 0:
+		return "[ ]"
+	case selected == total:
+		return "[x]"
+	default:
+		return "[~]"
+	}
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	titleBox, listBox := box.CutTop(1)
+	m.renderTitle(dl, titleBox)
+
+	if len(m.rows) == 0 {
+		dl.Text(listBox.R.Min.X, listBox.R.Min.Y, 0).
+			Styled("no changes", common.DefaultPalette.Get("diff_editor dimmed")).
+			Done()
+		return
+	}
+
+	m.height = listBox.R.Dy()
+	if m.ensureCursorVisible {
+		top := m.diff.ScrollY()
+		top = min(top, m.cursor)
+		top = max(top, m.cursor-m.height+1)
+		m.diff.SetScrollY(top)
+		m.ensureCursorVisible = false
+	}
+	gutterBox, diffBox := listBox.CutLeft(gutterWidth)
+	m.diff.ViewRect(dl, diffBox)
+	dl.AddInteraction(gutterBox.R, diff.ScrollMsg{}, render.InteractionScroll, 0)
+
+	selectedStyle := common.DefaultPalette.Get("diff_editor selected")
+	top := m.diff.ScrollY()
+	for y := 0; y < m.height && top+y < len(m.rows); y++ {
+		index := top + y
+		m.renderGutter(dl, m.rows[index], layout.Rect(gutterBox.R.Min.X, gutterBox.R.Min.Y+y, gutterWidth, 1))
+		rect := layout.Rect(listBox.R.Min.X, listBox.R.Min.Y+y, listBox.R.Dx(), 1)
+		dl.AddInteraction(rect, itemClickedMsg{Index: index}, render.InteractionClick, 0)
+		if index == m.cursor {
+			dl.AddHighlight(rect, selectedStyle, 1)
+		}
+	}
+}
+
+func (m *Model) renderTitle(dl *render.DisplayContext, box layout.Box) {
+	titleStyle := common.DefaultPalette.Get("diff_editor title")
+	dimmedStyle := common.DefaultPalette.Get("diff_editor dimmed")
+	selected, total := 0, 0
+	for _, f := range m.files {
+		s, t := f.selection()
+		selected += s
+		total += t
+	}
+	dl.Text(box.R.Min.X, box.R.Min.Y, 0).
+		Styled("diff editor", titleStyle).
+		Space(1).
+		Styled(fmt.Sprintf("%d of %d changes selected in %d files", selected, total, len(m.files)), dimmedStyle).
+		Done()
+}
+
+// renderGutter draws the collapse marker and checkbox of the row
+func (m *Model) renderGutter(dl *render.DisplayContext, r row, rect layout.Rectangle) {
+	f := m.files[r.file]
+	checkboxStyle := common.DefaultPalette.Get("diff_editor checkbox")
+	tb := dl.Text(rect.Min.X, rect.Min.Y, 0)
+	switch r.kind {
+	case fileRow:
+		marker := "▾"
+		if f.collapsed || len(f.hunks) == 0 {
+			marker = "▸"
+		}
+		tb.Styled(marker+" ", common.DefaultPalette.Get("diff_editor dimmed")).
+			Styled(checkbox(f.selection()), checkboxStyle)
+	case hunkRow:
+		tb.Space(2).Styled(checkbox(f.hunkSelection(f.hunks[r.hunk])), checkboxStyle)
+	case lineRow:
+		if f.isChange(r.op) {
+			tb.Space(4).Styled(checkbox(boolToInt(f.selected[r.op]), 1), checkboxStyle)
+		}
+	}
+	tb.Done()
+}
+
+// rowLine is the line of the diff shown for the row
+func (m *Model) rowLine(r row) string {
+	f := m.files[r.file]
+	switch r.kind {
+	case fileRow:
+		return fileStatusStyle(f.status).Render(fileStatusLabel(f)) + " " + common.DefaultPalette.Get("diff_editor file").Render(f.path)
+	case hunkRow:
+		return common.DefaultPalette.Get("diff_editor hunk").Render(f.hunks[r.hunk].Header())
+	}
+	op := f.ops[r.op]
+	switch op.Kind {
+	case textdiff.Delete:
+		return common.DefaultPalette.Get("diff_editor removed").Render("-" + lineText(f.oldLines[op.OldIndex]))
+	case textdiff.Insert:
+		return common.DefaultPalette.Get("diff_editor added").Render("+" + lineText(f.newLines[op.NewIndex]))
+	default:
+		return common.DefaultPalette.Get("diff_editor context").Render(" " + lineText(f.oldLines[op.OldIndex]))
+	}
+}
+
+func fileStatusLabel(f *file) string {
+	label := "M"
+	switch f.status {
+	case fileAdded:
+		label = "A"
+	case fileDeleted:
+		label = "D"
+	}
+	if f.binary {
+		label += " (binary)"
+	}
+	if f.symlink {
+		label += " (symlink)"
+	}
+	if f.modeChanged() {
+		label += fmt.Sprintf(" (mode %o → %o)", f.oldMode, f.mode)
+	}
+	return label
+}
+
+func fileStatusStyle(status fileStatus) lipgloss.Style {
+	switch status {
+	case fileAdded:
+		return common.DefaultPalette.Get("diff_editor added")
+	case fileDeleted:
+		return common.DefaultPalette.Get("diff_editor removed")
+	default:
+		return common.DefaultPalette.Get("diff_editor modified")
+	}
+}
+
+func lineText(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return render.ExpandTabs(line)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// New loads the differences between the left and right directories.
+func New(left, right string) (*Model, error) {
+	files, err := loadFiles(left, right)
+	if err != nil {
+		return nil, err
+	}
+	m := &Model{
+		left:  left,
+		right: right,
+		files: files,
+		diff:  diff.New(""),
+	}
+	m.buildRows()
+	return m, nil
+}
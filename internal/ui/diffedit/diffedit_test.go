@@ -0,0 +1,189 @@
+package diffedit
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+	return root
+}
+
+func readFile(t *testing.T, root, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(root, path))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestNew_ListsChangedFilesOnly(t *testing.T) {
+	left := writeTree(t, map[string]string{"same.txt": "a\n", "changed.txt": "a\n", "deleted.txt": "x\n"})
+	right := writeTree(t, map[string]string{"same.txt": "a\n", "changed.txt": "b\n", "added.txt": "y\n", instructionsFile: "help"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 60, 20))
+	assert.Contains(t, rendered, "added.txt")
+	assert.Contains(t, rendered, "changed.txt")
+	assert.Contains(t, rendered, "deleted.txt")
+	assert.NotContains(t, rendered, "same.txt")
+	assert.NotContains(t, rendered, instructionsFile)
+}
+
+func TestApply_KeepsEverythingByDefault(t *testing.T) {
+	left := writeTree(t, map[string]string{"file.txt": "1\n2\n3\n"})
+	right := writeTree(t, map[string]string{"file.txt": "1\ntwo\n3\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	model.Update(intents.Apply{})
+
+	assert.True(t, model.Applied())
+	assert.Equal(t, "1\ntwo\n3\n", readFile(t, right, "file.txt"))
+}
+
+func TestApply_DeselectedLineIsReverted(t *testing.T) {
+	left := writeTree(t, map[string]string{"file.txt": "1\n2\n3\n"})
+	right := writeTree(t, map[string]string{"file.txt": "1\n2\n3\n4\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+
+	// rows: file, hunk, 1, 2, 3, +4
+	model.Update(intents.DiffEditorJumpHunk{Delta: 1})
+	model.Update(intents.DiffEditorNavigate{Delta: 4})
+	model.Update(intents.DiffEditorToggle{})
+	model.Update(intents.Apply{})
+
+	assert.Equal(t, "1\n2\n3\n", readFile(t, right, "file.txt"))
+}
+
+func TestApply_PartialHunkSelection(t *testing.T) {
+	left := writeTree(t, map[string]string{"file.txt": "a\nb\n"})
+	right := writeTree(t, map[string]string{"file.txt": "a\nB\nc\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+
+	// rows: file, hunk, a, -b, +B, +c; drop the addition of c only
+	model.Update(intents.DiffEditorNavigate{Delta: 5})
+	model.Update(intents.DiffEditorToggle{})
+	model.Update(intents.Apply{})
+
+	assert.Equal(t, "a\nB\n", readFile(t, right, "file.txt"))
+}
+
+func TestApply_DeselectedFilesAreRestored(t *testing.T) {
+	left := writeTree(t, map[string]string{"deleted.txt": "x\n"})
+	right := writeTree(t, map[string]string{"added.txt": "y\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	model.Update(intents.DiffEditorToggleAll{})
+	model.Update(intents.Apply{})
+
+	_, err = os.Stat(filepath.Join(right, "added.txt"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, "x\n", readFile(t, right, "deleted.txt"))
+}
+
+func TestCancel_LeavesRightUntouched(t *testing.T) {
+	left := writeTree(t, map[string]string{"file.txt": "a\n"})
+	right := writeTree(t, map[string]string{"file.txt": "b\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	model.Update(intents.DiffEditorToggleAll{})
+	model.Update(intents.Cancel{})
+
+	assert.False(t, model.Applied())
+	assert.Equal(t, "b\n", readFile(t, right, "file.txt"))
+}
+
+func TestToggleCollapse_HidesHunks(t *testing.T) {
+	left := writeTree(t, map[string]string{"file.txt": "a\n"})
+	right := writeTree(t, map[string]string{"file.txt": "b\n"})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 60, 10)), "@@ -1 +1 @@")
+
+	model.Update(intents.DiffEditorToggleCollapse{})
+	assert.NotContains(t, test.Stripped(test.RenderImmediate(model, 60, 10)), "@@ -1 +1 @@")
+}
+
+func TestApply_EmptyAddedFileCanBeDeselected(t *testing.T) {
+	left := writeTree(t, map[string]string{})
+	right := writeTree(t, map[string]string{"empty.txt": ""})
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	model.Update(intents.DiffEditorToggle{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 60, 10)), "0 of 1 changes selected")
+	model.Update(intents.Apply{})
+
+	_, err = os.Stat(filepath.Join(right, "empty.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestApply_ModeOnlyChangeIsListedAndCanBeReverted(t *testing.T) {
+	left := writeTree(t, map[string]string{"run.sh": "echo\n"})
+	right := writeTree(t, map[string]string{"run.sh": "echo\n"})
+	require.NoError(t, os.Chmod(filepath.Join(right, "run.sh"), 0o755))
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 60, 10)), "M (mode 644 → 755) run.sh")
+
+	model.Update(intents.DiffEditorToggle{})
+	model.Update(intents.Apply{})
+
+	info, err := os.Stat(filepath.Join(right, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+}
+
+func TestApply_SymlinksAreTakenAsAWhole(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need extra privileges on windows")
+	}
+	left := writeTree(t, map[string]string{"a.txt": "a\n", "b.txt": "b\n", "file": "regular\n"})
+	right := writeTree(t, map[string]string{"a.txt": "a\n", "b.txt": "b\n"})
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(left, "link")))
+	require.NoError(t, os.Symlink("b.txt", filepath.Join(right, "link")))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(right, "added")))
+	require.NoError(t, os.Symlink("b.txt", filepath.Join(right, "file")))
+
+	model, err := New(left, right)
+	require.NoError(t, err)
+	rendered := test.Stripped(test.RenderImmediate(model, 60, 20))
+	assert.Contains(t, rendered, "A (symlink) added")
+	assert.Contains(t, rendered, "M (symlink) file")
+	assert.Contains(t, rendered, "M (symlink) link")
+
+	model.Update(intents.DiffEditorToggleAll{})
+	model.Update(intents.Apply{})
+
+	_, err = os.Lstat(filepath.Join(right, "added"))
+	assert.True(t, os.IsNotExist(err))
+	target, err := os.Readlink(filepath.Join(right, "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "a.txt", target)
+	assert.Equal(t, "regular\n", readFile(t, right, "file"))
+	assert.Equal(t, "b\n", readFile(t, right, "b.txt"))
+}
@@ -0,0 +1,316 @@
+package diffedit
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/idursun/jjui/internal/textdiff"
+)
+
+// jj drops its instructions into the right directory, they are not part of the change
+const instructionsFile = "JJ-INSTRUCTIONS"
+
+const contextLines = 3
+
+type fileStatus int
+
+const (
+	fileModified fileStatus = iota
+	fileAdded
+	fileDeleted
+)
+
+type file struct {
+	path   string
+	status fileStatus
+	binary bool
+	// symlink is set when either side is a symbolic link, which is compared
+	// and taken by its target as a whole
+	symlink  bool
+	oldMode  fs.FileMode
+	mode     fs.FileMode
+	oldLines []string
+	newLines []string
+	ops      []textdiff.Op
+	hunks    []textdiff.Hunk
+	// selected tracks whether the change made by ops[i] is kept. Equal ops are ignored.
+	selected []bool
+	// wholeSelected is used for files which can only be taken as a whole, see
+	// whole
+	wholeSelected bool
+	collapsed     bool
+}
+
+func (f *file) isChange(op int) bool {
+	return f.ops[op].Kind != textdiff.Equal
+}
+
+// whole tells if the file has no lines to pick from, like binary files, empty
+// files being added or removed, symbolic links and files whose mode alone
+// changed
+func (f *file) whole() bool {
+	return f.binary || f.symlink || !slices.ContainsFunc(f.ops, func(op textdiff.Op) bool { return op.Kind != textdiff.Equal })
+}
+
+// modeChanged tells if the file is kept with a different mode, like when it is
+// made executable
+func (f *file) modeChanged() bool {
+	return f.status == fileModified && !f.symlink && f.oldMode != f.mode
+}
+
+// selection reports how many of the changes in the file are kept out of the total.
+func (f *file) selection() (selected int, total int) {
+	if f.whole() {
+		if f.wholeSelected {
+			return 1, 1
+		}
+		return 0, 1
+	}
+	for i := range f.ops {
+		if !f.isChange(i) {
+			continue
+		}
+		total++
+		if f.selected[i] {
+			selected++
+		}
+	}
+	return selected, total
+}
+
+func (f *file) hunkSelection(h textdiff.Hunk) (selected int, total int) {
+	for i := h.Start; i < h.End; i++ {
+		if !f.isChange(i) {
+			continue
+		}
+		total++
+		if f.selected[i] {
+			selected++
+		}
+	}
+	return selected, total
+}
+
+func (f *file) setRange(start, end int, value bool) {
+	for i := start; i < end; i++ {
+		f.selected[i] = value
+	}
+}
+
+func (f *file) setAll(value bool) {
+	f.wholeSelected = value
+	if !f.binary {
+		f.setRange(0, len(f.ops), value)
+	}
+}
+
+// content rebuilds the file from the left side and the selected changes.
+func (f *file) content() string {
+	var b strings.Builder
+	for i, op := range f.ops {
+		switch op.Kind {
+		case textdiff.Equal:
+			b.WriteString(f.oldLines[op.OldIndex])
+		case textdiff.Delete:
+			if !f.selected[i] {
+				b.WriteString(f.oldLines[op.OldIndex])
+			}
+		case textdiff.Insert:
+			if f.selected[i] {
+				b.WriteString(f.newLines[op.NewIndex])
+			}
+		}
+	}
+	return b.String()
+}
+
+// loadFiles compares the regular files and symbolic links in the left and
+// right directories.
+func loadFiles(left, right string) ([]*file, error) {
+	leftFiles, err := listFiles(left)
+	if err != nil {
+		return nil, err
+	}
+	rightFiles, err := listFiles(right)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(leftFiles)+len(rightFiles))
+	for path := range leftFiles {
+		paths = append(paths, path)
+	}
+	for path := range rightFiles {
+		if _, ok := leftFiles[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	var files []*file
+	for _, path := range paths {
+		leftMode, inLeft := leftFiles[path]
+		rightMode, inRight := rightFiles[path]
+
+		var oldContent, newContent []byte
+		if inLeft {
+			if oldContent, err = readEntry(filepath.Join(left, path), leftMode); err != nil {
+				return nil, err
+			}
+		}
+		if inRight {
+			if newContent, err = readEntry(filepath.Join(right, path), rightMode); err != nil {
+				return nil, err
+			}
+		}
+		if inLeft && inRight && leftMode == rightMode && bytes.Equal(oldContent, newContent) {
+			continue
+		}
+
+		f := &file{path: path, status: fileModified, oldMode: leftMode, mode: rightMode, wholeSelected: true}
+		switch {
+		case !inLeft:
+			f.status = fileAdded
+			f.oldMode = rightMode
+		case !inRight:
+			f.status = fileDeleted
+			f.mode = leftMode
+		}
+		if isSymlink(leftMode) || isSymlink(rightMode) {
+			f.symlink = true
+			files = append(files, f)
+			continue
+		}
+		if isBinary(oldContent) || isBinary(newContent) {
+			f.binary = true
+			files = append(files, f)
+			continue
+		}
+
+		f.oldLines = textdiff.SplitLines(string(oldContent))
+		f.newLines = textdiff.SplitLines(string(newContent))
+		f.ops = textdiff.Diff(f.oldLines, f.newLines)
+		f.hunks = textdiff.Hunks(f.ops, contextLines)
+		f.selected = make([]bool, len(f.ops))
+		f.setAll(true)
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func listFiles(root string) (map[string]fs.FileMode, error) {
+	files := make(map[string]fs.FileMode)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() && !isSymlink(d.Type()) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == instructionsFile {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode().Perm()
+		if isSymlink(d.Type()) {
+			mode = fs.ModeSymlink
+		}
+		files[filepath.ToSlash(rel)] = mode
+		return nil
+	})
+	return files, err
+}
+
+func isSymlink(mode fs.FileMode) bool {
+	return mode&fs.ModeSymlink != 0
+}
+
+// readEntry reads the content of a regular file, or the target of a symbolic
+// link
+func readEntry(path string, mode fs.FileMode) ([]byte, error) {
+	if isSymlink(mode) {
+		target, err := os.Readlink(path)
+		return []byte(target), err
+	}
+	return os.ReadFile(path)
+}
+
+func isBinary(content []byte) bool {
+	const sniffLen = 8000
+	return bytes.IndexByte(content[:min(len(content), sniffLen)], 0) >= 0
+}
+
+// writeFiles makes the right directory reflect the selected changes.
+// The right directory starts with every change applied, so only files with
+// deselected changes need to be touched.
+func writeFiles(left, right string, files []*file) error {
+	for _, f := range files {
+		selected, total := f.selection()
+		if selected == total {
+			continue
+		}
+		target := filepath.Join(right, filepath.FromSlash(f.path))
+		if selected == 0 {
+			if err := revertFile(left, target, f); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeFile(target, []byte(f.content()), f.mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func revertFile(left, target string, f *file) error {
+	if f.status == fileAdded {
+		return os.Remove(target)
+	}
+	source := filepath.Join(left, filepath.FromSlash(f.path))
+	if f.symlink {
+		// writing through a link would change the file it points to, so
+		// whatever is on the right goes first
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if isSymlink(f.oldMode) {
+		link, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.Symlink(link, target)
+	}
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return writeFile(target, content, f.oldMode)
+}
+
+// writeFile writes content to target with mode, which os.WriteFile leaves
+// alone for files that already exist
+func writeFile(target string, content []byte, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target, content, mode); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode)
+}
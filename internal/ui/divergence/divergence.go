@@ -0,0 +1,428 @@
+package divergence
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+// predecessorLimit is how many previous versions of each commit are shown
+const predecessorLimit = 3
+
+type loadedMsg struct {
+	changes []jj.DivergentChange
+	err     error
+}
+
+// version is what is loaded on demand about a commit of the selected change
+type version struct {
+	description  string
+	predecessors []jj.EvologEntry
+}
+
+type versionsMsg struct {
+	versions map[string]version
+}
+
+type interdiffMsg struct {
+	from    string
+	to      string
+	content string
+}
+
+type changeClickMsg struct {
+	Index int
+}
+
+type changeScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m changeScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// Model lists the divergent changes and shows the commits of the selected one
+// side by side, with an interdiff between the selected commit and the next,
+// so that one of them can be kept and the others abandoned or squashed in.
+type Model struct {
+	context             *context.MainContext
+	changes             []jj.DivergentChange
+	versions            map[string]version
+	interdiff           interdiffMsg
+	loaded              bool
+	err                 error
+	cursor              int
+	selected            int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeDivergence,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.DivergentCommits())
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	return loadedMsg{changes: jj.ParseDivergentCommits(string(output))}
+}
+
+// loadVersions loads the descriptions and predecessors of the commits of the
+// change under the cursor
+func (m *Model) loadVersions() tea.Cmd {
+	change := m.currentChange()
+	if change == nil {
+		return nil
+	}
+	commits := change.Commits
+	return func() tea.Msg {
+		versions := map[string]version{}
+		for _, c := range commits {
+			description, _ := m.context.RunCommandImmediate(jj.GetDescription(c.CommitId))
+			output, _ := m.context.RunCommandImmediate(jj.EvologEntries(c.CommitId, predecessorLimit+1))
+			predecessors := jj.ParseEvologEntries(string(output))
+			// the first entry is the commit itself
+			if len(predecessors) > 0 {
+				predecessors = predecessors[1:]
+			}
+			versions[c.CommitId] = version{description: string(description), predecessors: predecessors}
+		}
+		return versionsMsg{versions: versions}
+	}
+}
+
+// loadInterdiff loads the interdiff from the next commit of the change to the
+// selected one, which is what keeping the selected one changes
+func (m *Model) loadInterdiff() tea.Cmd {
+	selected, other := m.selectedCommit(), m.otherCommit()
+	if selected == nil || other == nil {
+		return nil
+	}
+	from, to := other.CommitId, selected.CommitId
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.Interdiff(from, to))
+		if err != nil {
+			output = []byte(err.Error())
+		}
+		return interdiffMsg{from: from, to: to, content: string(output)}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.changes = msg.changes
+		m.cursor = min(m.cursor, max(len(m.changes)-1, 0))
+		return m.selectChange()
+	case versionsMsg:
+		m.versions = msg.versions
+	case interdiffMsg:
+		m.interdiff = msg
+	case changeClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.changes) && msg.Index != m.cursor {
+			m.cursor = msg.Index
+			return m.selectChange()
+		}
+	case changeScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.DivergenceNavigate:
+		if len(m.changes) == 0 {
+			return nil, true
+		}
+		cursor := max(min(m.cursor+intent.Delta, len(m.changes)-1), 0)
+		if cursor == m.cursor {
+			return nil, true
+		}
+		m.cursor = cursor
+		m.ensureCursorVisible = true
+		return m.selectChange(), true
+	case intents.DivergenceCycleVersion:
+		change := m.currentChange()
+		if change == nil {
+			return nil, true
+		}
+		n := len(change.Commits)
+		m.selected = ((m.selected+intent.Delta)%n + n) % n
+		return m.loadInterdiff(), true
+	case intents.DivergenceKeep:
+		change, selected := m.currentChange(), m.selectedCommit()
+		if selected == nil {
+			return nil, true
+		}
+		var others []*jj.Commit
+		for _, c := range change.Commits {
+			if c.CommitId != selected.CommitId {
+				others = append(others, &jj.Commit{ChangeId: c.Revision(), CommitId: c.CommitId})
+			}
+		}
+		revisions := jj.NewSelectedRevisions(others...)
+		args := jj.Abandon(revisions, false)
+		if intent.Squash {
+			args = jj.Squash(revisions, selected.Revision(), nil, false, true, false, false)
+		}
+		// the bookmarks of the other versions go to the kept one, not to the
+		// parents the abandoned commits would leave them on
+		moveBookmarks := jj.BookmarkMoveFrom(revisions, selected.CommitId)
+		return m.context.RunCommand(moveBookmarks, m.context.RunCommand(args, m.load, common.Refresh)), true
+	case intents.DivergenceInterdiff:
+		if m.interdiff.content == "" {
+			return nil, true
+		}
+		content := m.interdiff.content
+		return func() tea.Msg { return intents.DiffShow{Content: content} }, true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+// selectChange resets the selected commit for the change under the cursor
+// and loads what is shown about it
+func (m *Model) selectChange() tea.Cmd {
+	m.selected = 0
+	m.versions = nil
+	m.interdiff = interdiffMsg{}
+	return tea.Batch(m.loadVersions(), m.loadInterdiff())
+}
+
+func (m *Model) currentChange() *jj.DivergentChange {
+	if m.cursor < 0 || m.cursor >= len(m.changes) {
+		return nil
+	}
+	return &m.changes[m.cursor]
+}
+
+func (m *Model) selectedCommit() *jj.DivergentCommit {
+	change := m.currentChange()
+	if change == nil || m.selected >= len(change.Commits) {
+		return nil
+	}
+	return &change.Commits[m.selected]
+}
+
+// otherCommit is the commit the selected one is compared with
+func (m *Model) otherCommit() *jj.DivergentCommit {
+	change := m.currentChange()
+	if change == nil || len(change.Commits) < 2 {
+		return nil
+	}
+	return &change.Commits[(m.selected+1)%len(change.Commits)]
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 140), min(ph, 40))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("divergence text")
+	dimmedStyle := common.DefaultPalette.Get("divergence dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("divergence border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("divergent changes", common.DefaultPalette.Get("divergence title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.changes) == 0:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("no divergent changes"), render.ZMenuContent)
+		return
+	}
+
+	listBox, contentBox := contentBox.CutTop(min(len(m.changes), 5))
+	m.renderChanges(dl, listBox)
+	_, contentBox = contentBox.CutTop(1)
+	versionsBox, contentBox := contentBox.CutTop(min(12, contentBox.R.Dy()/2))
+	m.renderVersions(dl, versionsBox)
+	_, contentBox = contentBox.CutTop(1)
+	m.renderInterdiff(dl, contentBox)
+}
+
+func (m *Model) renderChanges(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.changes))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.changes),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			change := m.changes[index]
+			textStyle := common.DefaultPalette.Get("divergence text")
+			dimmedStyle := common.DefaultPalette.Get("divergence dimmed")
+			changeIdStyle := common.DefaultPalette.Get("divergence change_id")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("divergence selected")
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			count := fmt.Sprintf(" %d versions ", len(change.Commits))
+			description := change.Commits[0].Description
+			if description == "" {
+				description = "(no description set)"
+			}
+			remaining := max(rect.Dx()-ansi.StringWidth(change.ChangeId)-ansi.StringWidth(count), 0)
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(change.ChangeId, changeIdStyle).
+				Styled(count, dimmedStyle).
+				Styled(ansi.Truncate(description, remaining, "…"), textStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return changeClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// renderVersions renders the commits of the selected change in columns
+func (m *Model) renderVersions(dl *render.DisplayContext, box layout.Box) {
+	change := m.currentChange()
+	if change == nil || box.R.Dx() <= 0 || box.R.Dy() <= 0 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("divergence text")
+	dimmedStyle := common.DefaultPalette.Get("divergence dimmed")
+	changeIdStyle := common.DefaultPalette.Get("divergence change_id")
+	selectedStyle := common.DefaultPalette.Get("divergence selected")
+
+	specs := make([]layout.Spec, len(change.Commits))
+	for i := range specs {
+		specs[i] = layout.Fill(1)
+	}
+	columns := box.H(specs...)
+	for i, c := range change.Commits {
+		// leave a gap between the columns
+		column, _ := columns[i].CutRight(1)
+		columnWidth := column.R.Dx()
+
+		header := changeIdStyle.Render(c.Revision()) + " " + dimmedStyle.Render(c.CommitId)
+		if i == m.selected {
+			header = changeIdStyle.Inherit(selectedStyle).Render(c.Revision()) + selectedStyle.Render(" "+c.CommitId+" keep")
+		}
+		lines := []string{
+			header,
+			dimmedStyle.Render(c.Author + ", " + c.Age),
+			"",
+		}
+		v, ok := m.versions[c.CommitId]
+		description := strings.TrimSpace(v.description)
+		switch {
+		case !ok:
+			lines = append(lines, dimmedStyle.Render("loading..."))
+		case description == "":
+			lines = append(lines, dimmedStyle.Render("(no description set)"))
+		default:
+			for line := range strings.SplitSeq(description, "\n") {
+				lines = append(lines, textStyle.Render(line))
+			}
+		}
+		if len(v.predecessors) > 0 {
+			lines = append(lines, "", dimmedStyle.Render("evolved from"))
+			for _, p := range v.predecessors {
+				lines = append(lines, changeIdStyle.Render(p.CommitId)+" "+dimmedStyle.Render(p.Age)+" "+textStyle.Render(p.Description))
+			}
+		}
+		for j := range lines {
+			lines[j] = ansi.Truncate(lines[j], columnWidth, "…")
+		}
+		lines = lines[:min(len(lines), column.R.Dy())]
+		dl.AddDraw(column.R, strings.Join(lines, "\n"), render.ZMenuContent)
+	}
+}
+
+func (m *Model) renderInterdiff(dl *render.DisplayContext, box layout.Box) {
+	selected, other := m.selectedCommit(), m.otherCommit()
+	if selected == nil || other == nil || box.R.Dx() <= 0 || box.R.Dy() <= 0 {
+		return
+	}
+	dimmedStyle := common.DefaultPalette.Get("divergence dimmed")
+	headerBox, box := box.CutTop(1)
+	dl.Text(headerBox.R.Min.X, headerBox.R.Min.Y, render.ZMenuContent).
+		Styled(fmt.Sprintf("interdiff %s → %s", other.Revision(), selected.Revision()), common.DefaultPalette.Get("divergence title")).
+		Done()
+	content := strings.TrimRight(m.interdiff.content, "\n")
+	if m.interdiff.to != selected.CommitId {
+		content = dimmedStyle.Render("loading...")
+	} else if content == "" {
+		content = dimmedStyle.Render("the versions have the same content")
+	}
+	lines := strings.Split(content, "\n")
+	lines = lines[:min(len(lines), box.R.Dy())]
+	for i := range lines {
+		lines[i] = ansi.Truncate(lines[i], box.R.Dx(), "…")
+	}
+	dl.AddDraw(box.R, strings.Join(lines, "\n"), render.ZMenuContent)
+}
+
+func NewModel(c *context.MainContext) *Model {
+	m := &Model{
+		context:      c,
+		listRenderer: render.NewListRenderer(changeScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
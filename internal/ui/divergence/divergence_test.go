@@ -0,0 +1,76 @@
+package divergence
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const divergentOutput = "kx\t0\t1a2b\tAda\t2 hours ago\tfix the parser\n" +
+	"kx\t1\t5e6f\tBob\t3 hours ago\tfix the parser properly\n"
+
+func expectVersions(commandRunner *test.CommandRunner) {
+	commandRunner.Expect(jj.GetDescription("1a2b")).SetOutput([]byte("fix the parser\n\nfirst take"))
+	commandRunner.Expect(jj.EvologEntries("1a2b", predecessorLimit+1)).SetOutput([]byte("1a2b\t2 hours ago\tfix the parser\n9f8e\t5 hours ago\twip"))
+	commandRunner.Expect(jj.GetDescription("5e6f")).SetOutput([]byte("fix the parser properly"))
+	commandRunner.Expect(jj.EvologEntries("5e6f", predecessorLimit+1)).SetOutput([]byte("5e6f\t3 hours ago\tfix the parser properly"))
+}
+
+func TestLoad_ShowsVersionsSideBySideWithInterdiff(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DivergentCommits()).SetOutput([]byte(divergentOutput))
+	expectVersions(commandRunner)
+	commandRunner.Expect(jj.Interdiff("5e6f", "1a2b")).SetOutput([]byte("-return nil\n+return err"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 140, 40))
+	assert.Contains(t, rendered, "kx 2 versions fix the parser")
+	assert.Contains(t, rendered, "kx/0 1a2b keep")
+	assert.Contains(t, rendered, "kx/1 5e6f")
+	assert.Contains(t, rendered, "first take")
+	assert.Contains(t, rendered, "9f8e 5 hours ago wip")
+	assert.Contains(t, rendered, "interdiff kx/1 → kx/0")
+	assert.Contains(t, rendered, "+return err")
+}
+
+func TestKeep_MovesBookmarksAndAbandonsTheOtherVersions(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DivergentCommits()).SetOutput([]byte(divergentOutput))
+	expectVersions(commandRunner)
+	commandRunner.Expect(jj.Interdiff("5e6f", "1a2b"))
+	commandRunner.Expect(jj.Interdiff("1a2b", "5e6f"))
+	commandRunner.Expect(jj.BookmarkMoveFrom(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kx/0", CommitId: "1a2b"}), "5e6f"))
+	commandRunner.Expect(jj.Abandon(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kx/0", CommitId: "1a2b"}), false))
+	commandRunner.Expect(jj.DivergentCommits())
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.DivergenceCycleVersion{Delta: 1} })
+	test.SimulateModel(model, func() tea.Msg { return intents.DivergenceKeep{} })
+
+	rendered := test.Stripped(test.RenderImmediate(model, 140, 40))
+	assert.Contains(t, rendered, "no divergent changes")
+}
+
+func TestSquash_SquashesTheOtherVersionsIn(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.DivergentCommits()).SetOutput([]byte(divergentOutput))
+	expectVersions(commandRunner)
+	commandRunner.Expect(jj.Interdiff("5e6f", "1a2b"))
+	commandRunner.Expect(jj.BookmarkMoveFrom(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kx/1", CommitId: "5e6f"}), "1a2b"))
+	commandRunner.Expect(jj.Squash(jj.NewSelectedRevisions(&jj.Commit{ChangeId: "kx/1", CommitId: "5e6f"}), "kx/0", nil, false, true, false, false))
+	commandRunner.Expect(jj.DivergentCommits())
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.DivergenceKeep{Squash: true} })
+}
@@ -0,0 +1,376 @@
+package explode
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ common.Editable = (*Model)(nil)
+
+type appliedMsg struct {
+	before  string
+	after   string
+	created []string
+	err     error
+}
+
+type fileClickMsg struct {
+	Index int
+}
+
+type fileScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m fileScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// Model splits a revision into several commits, one per file, per top-level
+// directory or per group of files the user puts together.
+type Model struct {
+	context             *context.MainContext
+	revision            *jj.Commit
+	files               []*file
+	mode                intents.ExplodeMode
+	template            string
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	editing             bool
+	input               textinput.Model
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	if m.editing {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeExplodeTemplate,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeExplode,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) IsEditing() bool {
+	return m.editing
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case appliedMsg:
+		changeId := m.revision.GetChangeId()
+		changed := msg.before != "" && msg.after != "" && msg.before != msg.after
+		if changed {
+			m.context.UndoPoint = &context.UndoPoint{
+				Description: "explode of " + changeId,
+				Before:      msg.before,
+				After:       msg.after,
+			}
+		}
+		if msg.err != nil {
+			text := fmt.Sprintf("exploding %s failed: %s", changeId, strings.TrimSpace(msg.err.Error()))
+			if changed {
+				text = fmt.Sprintf("exploding %s failed, undo reverts what was done: %s", changeId, strings.TrimSpace(msg.err.Error()))
+			}
+			return tea.Batch(common.Close, common.Refresh, intents.Invoke(intents.AddMessage{Text: text, Err: msg.err}))
+		}
+		var created []string
+		for _, id := range msg.created {
+			created = append(created, shortId(id))
+		}
+		text := fmt.Sprintf("exploded %s into %s, undo reverts the whole explode", changeId, strings.Join(created, " "))
+		return tea.Batch(common.Close, common.Refresh, intents.Invoke(intents.AddMessage{Text: text}))
+	case fileClickMsg:
+		if !m.editing && msg.Index >= 0 && msg.Index < len(m.files) {
+			m.cursor = msg.Index
+		}
+	case fileScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.editing {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	if m.editing {
+		switch intent.(type) {
+		case intents.Apply:
+			if template := strings.TrimSpace(m.input.Value()); template != "" {
+				m.template = template
+			}
+			m.editing = false
+			m.input.Blur()
+			return nil, true
+		case intents.Cancel:
+			m.editing = false
+			m.input.Blur()
+			return nil, true
+		}
+		return nil, false
+	}
+	switch intent := intent.(type) {
+	case intents.ExplodeNavigate:
+		if len(m.files) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.files)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.ExplodeSetMode:
+		m.mode = intent.Mode
+		m.message = ""
+		return nil, true
+	case intents.ExplodeAssignGroup:
+		if m.cursor < len(m.files) {
+			m.files[m.cursor].group = intent.Group
+			m.message = ""
+		}
+		return nil, true
+	case intents.ExplodeEditTemplate:
+		m.editing = true
+		m.input.SetValue(m.template)
+		m.input.CursorEnd()
+		return m.input.Focus(), true
+	case intents.Apply:
+		commits := plan(m.files, m.mode, m.template)
+		if len(commits) < 2 {
+			m.message = "nothing to explode, all files would stay in a single commit"
+			return nil, true
+		}
+		return m.apply(commits), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+// apply moves the files of each commit but the last one out of the revision
+// into a new commit inserted before it, so that the commits keep the order of
+// the plan and the revision ends up with the last one. The operations the repo
+// was at before and after are reported so that undo can revert the whole
+// explode at once.
+func (m *Model) apply(commits []commit) tea.Cmd {
+	revision := m.revision.GetChangeId()
+	return func() tea.Msg {
+		before, err := m.context.RunCommandImmediate(jj.OpLogId(true))
+		if err != nil {
+			return appliedMsg{err: err}
+		}
+		created, err := m.explode(revision, commits)
+		after, _ := m.context.RunCommandImmediate(jj.OpLogId(false))
+		return appliedMsg{before: string(before), after: string(after), created: created, err: err}
+	}
+}
+
+// explode runs the plan with whole change ids, as the shortest ones can become
+// ambiguous with every commit it creates
+func (m *Model) explode(revision string, commits []commit) ([]string, error) {
+	output, err := m.context.RunCommandImmediate(jj.GetFullIdsFromRevset(revision))
+	if err != nil {
+		return nil, err
+	}
+	revision = strings.TrimSpace(string(output))
+	var created []string
+	for _, c := range commits[:len(commits)-1] {
+		if _, err := m.context.RunCommandImmediate(jj.NewInsertBefore(revision, c.description)); err != nil {
+			return created, err
+		}
+		output, err := m.context.RunCommandImmediate(jj.GetFullIdsFromRevset(revision + "-"))
+		if err != nil {
+			return created, err
+		}
+		changeId := strings.TrimSpace(string(output))
+		from := jj.NewSelectedRevisions(&jj.Commit{ChangeId: revision})
+		if _, err := m.context.RunCommandImmediate(jj.Squash(from, changeId, c.files, false, true, false, false)); err != nil {
+			return created, err
+		}
+		created = append(created, changeId)
+	}
+	if _, err := m.context.RunCommandImmediate(jj.Reword(revision, commits[len(commits)-1].description)); err != nil {
+		return created, err
+	}
+	return append(created, revision), nil
+}
+
+// shortId shortens a whole change id like jj does for display
+func shortId(changeId string) string {
+	if len(changeId) > 12 && !strings.Contains(changeId, "/") {
+		return changeId[:12]
+	}
+	return changeId
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 40))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("explode text")
+	dimmedStyle := common.DefaultPalette.Get("explode dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("explode border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	commits := plan(m.files, m.mode, m.template)
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled(fmt.Sprintf("explode %s by %s into %d commits", m.revision.GetChangeId(), m.mode, len(commits)), common.DefaultPalette.Get("explode title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	var templateBox layout.Box
+	contentBox, templateBox = contentBox.CutBottom(1)
+	label := "template: "
+	dl.AddDraw(templateBox.R, dimmedStyle.Render(label), render.ZMenuContent)
+	_, templateBox = templateBox.CutLeft(len(label))
+	if m.editing {
+		m.input.SetWidth(max(templateBox.R.Dx()-1, 1))
+		dl.AddDraw(templateBox.R, m.input.View(), render.ZMenuContent)
+	} else {
+		dl.AddDraw(templateBox.R, textStyle.Render(ansi.Truncate(m.template, templateBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	contentBox, _ = contentBox.CutBottom(1)
+	m.renderFiles(dl, contentBox, commits)
+}
+
+func (m *Model) renderFiles(dl *render.DisplayContext, listBox layout.Box, commits []commit) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	// the commit each file goes into, and the first file of each commit
+	commitOf := map[string]int{}
+	first := map[string]bool{}
+	for i, c := range commits {
+		for _, f := range c.files {
+			commitOf[f] = i
+		}
+		first[c.files[0]] = true
+	}
+	pathWidth := 0
+	for _, f := range m.files {
+		pathWidth = max(pathWidth, ansi.StringWidth(f.path))
+	}
+	pathWidth = min(pathWidth, listBox.R.Dx()/2)
+
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.files))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.files),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			f := m.files[index]
+			numberStyle := common.DefaultPalette.Get("explode change_id")
+			groupStyle := common.DefaultPalette.Get("explode group")
+			textStyle := common.DefaultPalette.Get("explode text")
+			dimmedStyle := common.DefaultPalette.Get("explode dimmed")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("explode selected")
+				numberStyle = numberStyle.Inherit(selected)
+				groupStyle = groupStyle.Inherit(selected)
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			group := "  "
+			if f.group > 0 {
+				group = fmt.Sprintf("g%d", f.group)
+			}
+			description := ""
+			if first[f.path] {
+				description = commits[commitOf[f.path]].description
+			}
+			prefix := fmt.Sprintf("%3d %s %-*s  ", commitOf[f.path]+1, group, pathWidth, "")
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(fmt.Sprintf("%3d ", commitOf[f.path]+1), numberStyle).
+				Styled(group+" ", groupStyle).
+				Styled(fmt.Sprintf("%-*s  ", pathWidth, ansi.Truncate(f.path, pathWidth, "…")), textStyle).
+				Styled(ansi.Truncate(description, max(rect.Dx()-ansi.StringWidth(prefix), 0), "…"), dimmedStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return fileClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// NewModel creates the explode view for revision with its files, putting
+// the grouped ones together in the first user defined group.
+func NewModel(c *context.MainContext, revision *jj.Commit, files []string, grouped []string) *Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 200
+
+	m := &Model{
+		context:      c,
+		revision:     revision,
+		mode:         intents.ExplodeByFile,
+		template:     DefaultTemplate,
+		input:        input,
+		listRenderer: render.NewListRenderer(fileScrollMsg{}),
+	}
+	for _, f := range files {
+		group := 0
+		if len(grouped) > 1 && slices.Contains(grouped, f) {
+			group = 1
+		}
+		m.files = append(m.files, &file{path: f, group: group})
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
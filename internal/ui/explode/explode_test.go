@@ -0,0 +1,95 @@
+package explode
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var revision = &jj.Commit{ChangeId: "rrrrrrrr"}
+
+func TestView_ShowsCommitOfEachFile(t *testing.T) {
+	model := NewModel(test.NewTestContext(test.NewTestCommandRunner(t)), revision, []string{"a.go", "b.go", "cmd/c.go"}, []string{"a.go", "cmd/c.go"})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "explode rrrrrrrr by file into 2 commits")
+	assert.Contains(t, rendered, "1 g1 a.go")
+	assert.Contains(t, rendered, "2    b.go")
+	assert.Contains(t, rendered, "1 g1 cmd/c.go")
+}
+
+func TestApply_InsertsCommitsBeforeRevision(t *testing.T) {
+	const full = "rrrrrrrrrrrrrrrrrrrrrrrrrrrrrrrr"
+	from := jj.NewSelectedRevisions(&jj.Commit{ChangeId: full})
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.GetFullIdsFromRevset("rrrrrrrr")).SetOutput([]byte(full + "\n"))
+	commandRunner.Expect(jj.NewInsertBefore(full, "update a.go"))
+	commandRunner.Expect(jj.GetFullIdsFromRevset(full + "-")).SetOutput([]byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\n"))
+	commandRunner.Expect(jj.Squash(from, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", []string{"a.go"}, false, true, false, false))
+	commandRunner.Expect(jj.NewInsertBefore(full, "update b.go"))
+	commandRunner.Expect(jj.GetFullIdsFromRevset(full + "-")).SetOutput([]byte("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\n"))
+	commandRunner.Expect(jj.Squash(from, "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", []string{"b.go"}, false, true, false, false))
+	commandRunner.Expect(jj.Reword(full, "update c.go"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("4567cdef"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewModel(ctx, revision, []string{"a.go", "b.go", "c.go"}, nil)
+
+	var message string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if added, ok := msg.(intents.AddMessage); ok {
+			message = added.Text
+		}
+	})
+	assert.Contains(t, message, "aaaaaaaaaaaa bbbbbbbbbbbb rrrrrrrrrrrr")
+	assert.Contains(t, message, "undo reverts the whole explode")
+	assert.Equal(t, &context.UndoPoint{Description: "explode of rrrrrrrr", Before: "0123abcd", After: "4567cdef"}, ctx.UndoPoint)
+}
+
+func TestApply_FailureBeforeAnyChangeLeavesNoUndoPoint(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.GetFullIdsFromRevset("rrrrrrrr")).SetOutput([]byte("rrrrrrrrrrrr\n"))
+	commandRunner.Expect(jj.NewInsertBefore("rrrrrrrrrrrr", "update a.go")).SetError(errors.New("immutable"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("0123abcd"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewModel(ctx, revision, []string{"a.go", "b.go"}, nil)
+
+	var message string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if added, ok := msg.(intents.AddMessage); ok {
+			message = added.Text
+		}
+	})
+	assert.Equal(t, "exploding rrrrrrrr failed: immutable", message)
+	assert.Nil(t, ctx.UndoPoint)
+}
+
+func TestApply_RefusesSingleCommit(t *testing.T) {
+	model := NewModel(test.NewTestContext(test.NewTestCommandRunner(t)), revision, []string{"src/a.go", "src/b.go"}, nil)
+	model.Update(intents.ExplodeSetMode{Mode: intents.ExplodeByDirectory})
+	model.Update(intents.Apply{})
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "nothing to explode")
+}
+
+func TestEditTemplate_ChangesDescriptions(t *testing.T) {
+	model := NewModel(test.NewTestContext(test.NewTestCommandRunner(t)), revision, []string{"a.go", "b.go"}, nil)
+	test.SimulateModel(model, func() tea.Msg { return intents.ExplodeEditTemplate{} })
+	assert.True(t, model.IsEditing())
+
+	model.input.SetValue("{path}: fix")
+	model.Update(intents.Apply{})
+	assert.False(t, model.IsEditing())
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "a.go  a.go: fix")
+}
@@ -0,0 +1,80 @@
+package explode
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// DefaultTemplate is the description given to each new commit, {path} being
+// replaced by the path of the files it holds
+const DefaultTemplate = "update {path}"
+
+// file is a file of the exploded revision, group being the user defined
+// group it was put in or 0 if none
+type file struct {
+	path  string
+	group int
+}
+
+// commit is one of the commits the revision is exploded into
+type commit struct {
+	files       []string
+	description string
+}
+
+// plan groups files into commits, in the order their first file appears.
+// Files put in the same user defined group go together, the rest go one
+// commit per file or per top-level directory depending on mode.
+func plan(files []*file, mode intents.ExplodeMode, template string) []commit {
+	var keys []string
+	grouped := map[string][]string{}
+	for _, f := range files {
+		key := groupKey(f, mode)
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], f.path)
+	}
+	var commits []commit
+	for _, key := range keys {
+		files := grouped[key]
+		commits = append(commits, commit{
+			files:       files,
+			description: strings.ReplaceAll(template, "{path}", commonPath(files)),
+		})
+	}
+	return commits
+}
+
+func groupKey(f *file, mode intents.ExplodeMode) string {
+	if f.group > 0 {
+		return fmt.Sprintf("group:%d", f.group)
+	}
+	if mode == intents.ExplodeByDirectory {
+		if dir, _, ok := strings.Cut(f.path, "/"); ok {
+			return "dir:" + dir
+		}
+	}
+	return "file:" + f.path
+}
+
+// commonPath returns the deepest directory holding all files, or the files
+// joined together if they have nothing in common
+func commonPath(files []string) string {
+	if len(files) == 1 {
+		return files[0]
+	}
+	common := path.Dir(files[0])
+	for _, f := range files[1:] {
+		for common != "." && !strings.HasPrefix(f, common+"/") {
+			common = path.Dir(common)
+		}
+	}
+	if common == "." {
+		return strings.Join(files, ", ")
+	}
+	return common
+}
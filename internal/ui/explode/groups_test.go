@@ -0,0 +1,48 @@
+package explode
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/stretchr/testify/assert"
+)
+
+func files(groups map[string]int, paths ...string) []*file {
+	var ret []*file
+	for _, p := range paths {
+		ret = append(ret, &file{path: p, group: groups[p]})
+	}
+	return ret
+}
+
+func TestPlan_ByFile(t *testing.T) {
+	commits := plan(files(nil, "a.go", "cmd/main.go"), intents.ExplodeByFile, DefaultTemplate)
+	assert.Equal(t, []commit{
+		{files: []string{"a.go"}, description: "update a.go"},
+		{files: []string{"cmd/main.go"}, description: "update cmd/main.go"},
+	}, commits)
+}
+
+func TestPlan_ByDirectoryKeepsRootFilesApart(t *testing.T) {
+	commits := plan(files(nil, "internal/ui/a.go", "README.md", "internal/ui/b.go", "go.mod", "internal/jj/c.go"), intents.ExplodeByDirectory, "{path}: tidy")
+	assert.Equal(t, []commit{
+		{files: []string{"internal/ui/a.go", "internal/ui/b.go", "internal/jj/c.go"}, description: "internal: tidy"},
+		{files: []string{"README.md"}, description: "README.md: tidy"},
+		{files: []string{"go.mod"}, description: "go.mod: tidy"},
+	}, commits)
+}
+
+func TestPlan_UserGroupsGoTogether(t *testing.T) {
+	groups := map[string]int{"a/x.go": 2, "b/y.go": 2}
+	commits := plan(files(groups, "a/x.go", "a/z.go", "b/y.go"), intents.ExplodeByDirectory, DefaultTemplate)
+	assert.Equal(t, []commit{
+		{files: []string{"a/x.go", "b/y.go"}, description: "update a/x.go, b/y.go"},
+		{files: []string{"a/z.go"}, description: "update a/z.go"},
+	}, commits)
+}
+
+func TestCommonPath(t *testing.T) {
+	assert.Equal(t, "internal/ui", commonPath([]string{"internal/ui/a.go", "internal/ui/render/b.go"}))
+	assert.Equal(t, "internal", commonPath([]string{"internal/ui/a.go", "internal/jj/b.go"}))
+	assert.Equal(t, "a.go, b.go", commonPath([]string{"a.go", "b.go"}))
+}
@@ -0,0 +1,351 @@
+package filehistory
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+// maxRenames limits how many renames are followed back through the history
+const maxRenames = 10
+
+// entry is a revision that changed the file. path is the name of the file in
+// that revision, renamedFrom is set if the revision renamed it.
+type entry struct {
+	changeId    string
+	commitId    string
+	author      string
+	age         string
+	description string
+	path        string
+	renamedFrom string
+}
+
+type loadedMsg struct {
+	entries []entry
+	err     error
+}
+
+type diffLoadedMsg struct {
+	commitId string
+	content  string
+}
+
+type entryClickMsg struct {
+	Index int
+}
+
+type entryScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m entryScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type Model struct {
+	context             *context.MainContext
+	file                string
+	entries             []entry
+	loaded              bool
+	err                 error
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+	diffs               map[string]string
+	diffScroll          int
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeFileHistory,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+// load lists the revisions changing the file. When the oldest of them renamed
+// the file, the history of the old path before it is appended.
+func (m *Model) load() tea.Msg {
+	var entries []entry
+	path := m.file
+	revset := fmt.Sprintf("files(%s)", jj.EscapeFileName(path))
+	for range maxRenames {
+		output, err := m.context.RunCommandImmediate(jj.FileLog(revset, path))
+		if err != nil {
+			return loadedMsg{err: err}
+		}
+		parsed := parseEntries(string(output), path)
+		entries = append(entries, parsed...)
+		if len(parsed) == 0 {
+			break
+		}
+		oldest := parsed[len(parsed)-1]
+		if oldest.renamedFrom == "" {
+			break
+		}
+		path = oldest.renamedFrom
+		revset = fmt.Sprintf("files(%s) & ::%s-", jj.EscapeFileName(path), oldest.commitId)
+	}
+	return loadedMsg{entries: entries}
+}
+
+// parseEntries parses the output of jj.FileLog for the given path
+func parseEntries(output string, path string) []entry {
+	var entries []entry
+	for _, row := range strings.Split(output, "\n") {
+		fields := strings.SplitN(row, "\t", 6)
+		if len(fields) < 6 {
+			continue
+		}
+		entries = append(entries, entry{
+			changeId:    fields[0],
+			commitId:    fields[1],
+			author:      fields[2],
+			age:         fields[3],
+			renamedFrom: fields[4],
+			description: fields[5],
+			path:        path,
+		})
+	}
+	return entries
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.entries = msg.entries
+		m.err = msg.err
+		m.cursor = 0
+		m.ensureCursorVisible = true
+		return m.loadDiff()
+	case diffLoadedMsg:
+		m.diffs[msg.commitId] = msg.content
+	case entryClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.entries) {
+			m.cursor = msg.Index
+			m.diffScroll = 0
+			return m.loadDiff()
+		}
+	case entryScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.FileHistoryNavigate:
+		return m.navigate(intent.Delta, intent.IsPage), true
+	case intents.FileHistoryScrollDiff:
+		m.diffScroll = max(m.diffScroll+intent.Delta, 0)
+		return nil, true
+	case intents.FileHistoryRestore:
+		current, ok := m.current()
+		if !ok {
+			return nil, true
+		}
+		return m.context.RunCommand(jj.RestoreFile(current.commitId, "@", current.path), common.Refresh, common.Close), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) current() (entry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return entry{}, false
+	}
+	return m.entries[m.cursor], true
+}
+
+func (m *Model) navigate(delta int, page bool) tea.Cmd {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	if page {
+		span := max(m.listRenderer.GetLastRowIndex()-m.listRenderer.GetFirstRowIndex()-1, 1)
+		delta *= span
+	}
+	cursor := max(min(m.cursor+delta, len(m.entries)-1), 0)
+	if cursor == m.cursor {
+		return nil
+	}
+	m.cursor = cursor
+	m.ensureCursorVisible = true
+	m.diffScroll = 0
+	return m.loadDiff()
+}
+
+// loadDiff loads the diff of the file in the current revision unless it is
+// already loaded
+func (m *Model) loadDiff() tea.Cmd {
+	current, ok := m.current()
+	if !ok {
+		return nil
+	}
+	if _, ok := m.diffs[current.commitId]; ok {
+		return nil
+	}
+	return func() tea.Msg {
+		output, err := m.context.RunCommandImmediate(jj.Diff(current.commitId, current.path))
+		if err != nil {
+			return diffLoadedMsg{commitId: current.commitId, content: err.Error()}
+		}
+		return diffLoadedMsg{commitId: current.commitId, content: string(output)}
+	}
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	frame := box.Inset(1)
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("file_history text")
+	borderStyle := common.DefaultPalette.GetBorder("file_history border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	title := fmt.Sprintf("history of %s", m.file)
+	if m.loaded && m.err == nil {
+		title = fmt.Sprintf("%s (%d revisions)", title, len(m.entries))
+	}
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled(title, common.DefaultPalette.Get("file_history title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.entries) == 0:
+		dl.AddDraw(contentBox.R, textStyle.Render("no revisions changed this file"), render.ZMenuContent)
+		return
+	}
+
+	listBox, diffBox := contentBox.CutLeft(min(max(contentBox.R.Dx()*2/5, 30), contentBox.R.Dx()))
+	separatorBox, diffBox := diffBox.CutLeft(1)
+	if separatorBox.R.Dy() > 0 {
+		separator := strings.TrimSuffix(strings.Repeat("│\n", separatorBox.R.Dy()), "\n")
+		dl.AddDraw(separatorBox.R, common.DefaultPalette.Get("file_history dimmed").Render(separator), render.ZMenuContent)
+	}
+	_, diffBox = diffBox.CutLeft(1)
+	m.renderEntries(dl, listBox)
+	m.renderDiff(dl, diffBox)
+}
+
+func (m *Model) renderEntries(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	// each entry takes two lines, the second one being the description
+	const itemHeight = 2
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.entries)*itemHeight)
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.entries),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return itemHeight },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			e := m.entries[index]
+			changeIdStyle := common.DefaultPalette.Get("file_history change_id")
+			textStyle := common.DefaultPalette.Get("file_history text")
+			dimmedStyle := common.DefaultPalette.Get("file_history dimmed")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("file_history selected")
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			description := e.description
+			if description == "" {
+				description = "(no description set)"
+			}
+			if e.renamedFrom != "" {
+				description = fmt.Sprintf("renamed from %s: %s", e.renamedFrom, description)
+			}
+			width := rect.Dx()
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(ansi.Truncate(e.changeId, width, "…"), changeIdStyle).
+				Styled(ansi.Truncate(fmt.Sprintf(" %s %s", e.author, e.age), max(width-ansi.StringWidth(e.changeId), 0), "…"), dimmedStyle).
+				NewLine().
+				Styled(ansi.Truncate("  "+description, width, "…"), textStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return entryClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+func (m *Model) renderDiff(dl *render.DisplayContext, box layout.Box) {
+	current, ok := m.current()
+	if !ok || box.R.Dx() <= 0 || box.R.Dy() <= 0 {
+		return
+	}
+	content, ok := m.diffs[current.commitId]
+	if !ok {
+		dl.AddDraw(box.R, common.DefaultPalette.Get("file_history dimmed").Render("loading..."), render.ZMenuContent)
+		return
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	m.diffScroll = min(m.diffScroll, max(len(lines)-box.R.Dy(), 0))
+	lines = lines[m.diffScroll:min(m.diffScroll+box.R.Dy(), len(lines))]
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(render.ExpandTabs(line), box.R.Dx(), "")
+	}
+	dl.AddDraw(box.R, strings.Join(lines, "\n"), render.ZMenuContent)
+}
+
+func NewModel(c *context.MainContext, file string) *Model {
+	m := &Model{
+		context:      c,
+		file:         file,
+		listRenderer: render.NewListRenderer(entryScrollMsg{}),
+		diffs:        make(map[string]string),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
@@ -0,0 +1,75 @@
+package filehistory
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	newLog = "kkmpptxz\t1a2b3c4d\tAlice\t2 days ago\t\tfix typo\n" +
+		"zsuskuln\t5e6f7a8b\tBob\t3 weeks ago\told.go\tmove to new.go\n"
+	oldLog = "rlvkpnrz\t9c8d7e6f\tAlice\t2 months ago\t\tadd old.go\n"
+)
+
+func expectLoad(commandRunner *test.CommandRunner) {
+	commandRunner.Expect(jj.FileLog(`files(file:"new.go")`, "new.go")).SetOutput([]byte(newLog))
+	commandRunner.Expect(jj.FileLog(`files(file:"old.go") & ::5e6f7a8b-`, "old.go")).SetOutput([]byte(oldLog))
+	commandRunner.Expect(jj.Diff("1a2b3c4d", "new.go")).SetOutput([]byte("+fixed line"))
+}
+
+func TestLoad_FollowsRenames(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "new.go")
+	test.SimulateModel(model, model.Init())
+
+	assert.Len(t, model.entries, 3)
+	assert.Equal(t, "new.go", model.entries[1].path)
+	assert.Equal(t, "old.go", model.entries[2].path)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 120, 20))
+	assert.Contains(t, rendered, "history of new.go (3 revisions)")
+	assert.Contains(t, rendered, "renamed from old.go: move to new.go")
+	assert.Contains(t, rendered, "+fixed line")
+}
+
+func TestNavigate_LoadsDiffOfPathInThatRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.Diff("9c8d7e6f", "old.go")).SetOutput([]byte("+old line"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "new.go")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.FileHistoryNavigate{Delta: 2} })
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 120, 20)), "+old line")
+}
+
+func TestRestore_RestoresSelectedVersionIntoWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.Diff("5e6f7a8b", "new.go"))
+	commandRunner.Expect(jj.RestoreFile("5e6f7a8b", "@", "new.go"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "new.go")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.FileHistoryNavigate{Delta: 1} })
+	test.SimulateModel(model, func() tea.Msg { return intents.FileHistoryRestore{} })
+}
+
+func TestParseEntries_KeepsColumnsWithEmptyAuthor(t *testing.T) {
+	entries := parseEntries("zzzzzzzz\t00000000\t\t56 years ago\t\t\n", "new.go")
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "", entries[0].author)
+	assert.Equal(t, "56 years ago", entries[0].age)
+	assert.Equal(t, "", entries[0].renamedFrom)
+}
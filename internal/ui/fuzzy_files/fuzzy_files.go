@@ -114,6 +114,16 @@ func (fzf *fuzzyFiles) handleIntent(intent intents.Intent) tea.Cmd {
 			Line: config.GetDefaultEditor() + " " + path,
 			Mode: common.ExecShell,
 		})
+	case intents.FileSearchHistory:
+		path := fuzzy_search.SelectedMatch(fzf)
+		if path == "" {
+			return nil
+		}
+		return tea.Batch(
+			common.UpdateRevSet(fzf.revset),
+			newCmd(common.ShowPreview(fzf.wasPreviewShown)),
+			intents.Invoke(intents.OpenFileHistory{File: path}),
+		)
 	case intents.FileSearchTogglePreview:
 		fzf.revsetPreview = !fzf.revsetPreview
 		return tea.Batch(
@@ -219,12 +229,15 @@ func NewModel(msg common.FileSearchMsg) fuzzy_search.Model {
 		wasPreviewShown: msg.PreviewShown,
 		max:             30,
 		commit:          msg.Commit,
-		paths:           buildPathEntries(msg.RawFileOut),
+		paths:           BuildPathEntries(msg.RawFileOut),
 	}
 	return model
 }
 
-func buildPathEntries(rawFileOut []byte) []string {
+// BuildPathEntries returns the paths in the output of jj.FilesInRevision
+// along with their ancestor directories, which end with a slash, each
+// directory coming before the first file in it.
+func BuildPathEntries(rawFileOut []byte) []string {
 	lines := strings.Split(string(rawFileOut), "\n")
 	entries := make([]string, 0, len(lines))
 	seen := make(map[string]struct{}, len(lines))
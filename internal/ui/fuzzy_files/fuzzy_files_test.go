@@ -121,7 +121,7 @@ func TestUpdateRevSet_EmptyMatches(t *testing.T) {
 }
 
 func TestBuildPathEntries_IncludesDirectories(t *testing.T) {
-	entries := buildPathEntries([]byte("src/pkg/main.go\nsrc/other.go\nREADME.md\n"))
+	entries := BuildPathEntries([]byte("src/pkg/main.go\nsrc/other.go\nREADME.md\n"))
 
 	assert.Equal(t, []string{
 		"src/",
@@ -133,5 +133,5 @@ func TestBuildPathEntries_IncludesDirectories(t *testing.T) {
 }
 
 func TestBuildPathEntries_EmptyOutput(t *testing.T) {
-	assert.Empty(t, buildPathEntries(nil))
+	assert.Empty(t, BuildPathEntries(nil))
 }
@@ -2,6 +2,8 @@ package git
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -11,10 +13,12 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/actions"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/dispatch"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/progress"
 	"github.com/idursun/jjui/internal/ui/render"
 )
 
@@ -36,6 +40,9 @@ type item struct {
 	desc     string
 	command  []string
 	key      string
+	// variant names the kind of push, for git.preview_push to select which
+	// ones are previewed
+	variant string
 }
 
 func (i item) FilterValue() string {
@@ -80,6 +87,7 @@ const (
 var _ common.ImmediateModel = (*Model)(nil)
 var _ common.Focusable = (*Model)(nil)
 var _ common.Editable = (*Model)(nil)
+var _ common.Disposable = (*Model)(nil)
 
 type Model struct {
 	context             *context.MainContext
@@ -95,18 +103,43 @@ type Model struct {
 	revisions           jj.SelectedRevisions
 	remoteNames         []string
 	selectedRemoteIdx   int
+	tags                []jj.Tag
+	remotes             *remotesModel
+	preview             *confirmation.Model
+	progress            *progress.Model
 	title               string
 }
 
 func (m *Model) IsFocused() bool {
+	if m.remotes != nil {
+		return m.remotes.IsEditing()
+	}
 	return m.filterState == filterEditing
 }
 
 func (m *Model) IsEditing() bool {
+	if m.remotes != nil {
+		return m.remotes.IsEditing()
+	}
 	return m.filterState == filterEditing
 }
 
 func (m *Model) Scopes() []dispatch.Scope {
+	if m.progress != nil {
+		return m.progress.Scopes()
+	}
+	if m.preview != nil {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeGitPushPreview,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	if m.remotes != nil {
+		return m.remotes.Scopes()
+	}
 	if m.IsEditing() {
 		return []dispatch.Scope{
 			{
@@ -153,6 +186,29 @@ func (m *Model) cycleRemotes(step int) tea.Cmd {
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case remotesClosedMsg:
+		m.closeRemotes()
+		return nil
+	case pushPreviewMsg:
+		m.openPreview(msg)
+		return nil
+	case pushPreviewClosedMsg:
+		m.preview = nil
+		return nil
+	case pushConfirmedMsg:
+		m.preview = nil
+		return m.startProgress(msg.item)
+	}
+	if m.progress != nil {
+		return m.progress.Update(msg)
+	}
+	if m.preview != nil {
+		return m.preview.Update(msg)
+	}
+	if m.remotes != nil {
+		return m.remotes.Update(msg)
+	}
 	switch msg := msg.(type) {
 	case itemClickMsg:
 		items := m.visibleItems()
@@ -200,8 +256,31 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// Dispose kills the command running in the progress panel
+func (m *Model) Dispose() {
+	if m.progress != nil {
+		m.progress.Dispose()
+	}
+}
+
 func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	if m.progress != nil {
+		return m.progress.HandleIntent(intent)
+	}
+	if m.preview != nil {
+		switch intent.(type) {
+		case intents.Apply, intents.Cancel, intents.OptionSelect:
+			return m.preview.Update(intent), true
+		}
+		return nil, false
+	}
+	if m.remotes != nil {
+		return m.remotes.HandleIntent(intent)
+	}
 	switch msg := intent.(type) {
+	case intents.GitOpenRemotes:
+		m.remotes = newRemotesModel(m.context)
+		return m.remotes.Init(), true
 	case intents.Apply:
 		if m.filterState == filterEditing {
 			m.filterText = strings.TrimSpace(m.filterInput.Value())
@@ -220,7 +299,7 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		if !ok {
 			return nil, true
 		}
-		return tea.Batch(common.CloseApplied, m.context.RunCommand(jj.Args(selected.command...), common.Refresh)), true
+		return m.run(selected), true
 	case intents.GitFilter:
 		filter := string(msg.Kind)
 		if filter == "" {
@@ -263,7 +342,7 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		}
 		for _, listItem := range m.visibleItems() {
 			if listItem.key == msg.Key {
-				return tea.Batch(common.CloseApplied, m.context.RunCommand(jj.Args(listItem.command...), common.Refresh)), true
+				return m.run(listItem), true
 			}
 		}
 		return nil, true
@@ -271,6 +350,23 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 	return nil, false
 }
 
+// startProgress runs the command of item, replacing the menu with its
+// output until it exits
+func (m *Model) startProgress(item item) tea.Cmd {
+	m.progress = progress.NewModel(m.context, jj.Args(item.command...))
+	return m.progress.Init()
+}
+
+// closeRemotes goes back to the menu, reloading the remotes as they may
+// have been changed
+func (m *Model) closeRemotes() {
+	m.remotes = nil
+	m.remoteNames = loadRemoteNames(m.context)
+	m.selectedRemoteIdx = min(m.selectedRemoteIdx, max(len(m.remoteNames)-1, 0))
+	m.items = m.createMenuItems()
+	m.applyFilters(false)
+}
+
 func (m *Model) executeDefaultForFilter(kind intents.GitFilterKind) tea.Cmd {
 	selectedRemote := ""
 	if len(m.remoteNames) > 0 && m.selectedRemoteIdx >= 0 && m.selectedRemoteIdx < len(m.remoteNames) {
@@ -286,24 +382,32 @@ func (m *Model) executeDefaultForFilter(kind intents.GitFilterKind) tea.Cmd {
 	if ok {
 		for _, listItem := range m.visibleItems() {
 			if slices.Equal(listItem.command, defaultCommand) {
-				return tea.Batch(common.CloseApplied, m.context.RunCommand(jj.Args(listItem.command...), common.Refresh))
+				return m.run(listItem)
 			}
 		}
 	}
 
 	if selected, ok := m.selectedItem(); ok {
-		return tea.Batch(common.CloseApplied, m.context.RunCommand(jj.Args(selected.command...), common.Refresh))
+		return m.run(selected)
 	}
 
 	for _, listItem := range m.visibleItems() {
 		if string(listItem.category) == string(kind) {
-			return tea.Batch(common.CloseApplied, m.context.RunCommand(jj.Args(listItem.command...), common.Refresh))
+			return m.run(listItem)
 		}
 	}
 	return nil
 }
 
 func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	if m.progress != nil {
+		m.progress.ViewRect(dl, box)
+		return
+	}
+	if m.remotes != nil {
+		m.remotes.ViewRect(dl, box)
+		return
+	}
 	menuTitleStyle := common.DefaultPalette.Get("git menu title")
 	menuTextStyle := common.DefaultPalette.Get("git menu text")
 	menuMatchedStyle := common.DefaultPalette.Get("git menu matched")
@@ -356,6 +460,10 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 
 	_, listBox := contentBox.CutTop(1)
 	m.renderList(dl, listBox)
+
+	if m.preview != nil {
+		m.renderPreview(dl, box)
+	}
 }
 
 func (m *Model) renderRemotes(dl *render.DisplayContext, lineBox layout.Box) {
@@ -396,6 +504,33 @@ func loadBookmarks(c context.CommandRunner, changeId string) []jj.Bookmark {
 	return bookmarks
 }
 
+// loadTags lists the tags when the repository is colocated, as tags can only
+// be pushed by running git there
+func loadTags(c *context.MainContext) []jj.Tag {
+	if c.Location == "" {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(c.Location, ".git")); err != nil {
+		return nil
+	}
+	bytes, _ := c.RunCommandImmediate(jj.TagListAll())
+	return jj.ParseTagListOutput(string(bytes))
+}
+
+// tagsOf returns the tags pointing at commit
+func (m *Model) tagsOf(commit *jj.Commit) []jj.Tag {
+	if commit == nil || commit.CommitId == "" {
+		return nil
+	}
+	var tags []jj.Tag
+	for _, t := range m.tags {
+		if t.CommitId != "" && (strings.HasPrefix(commit.CommitId, t.CommitId) || strings.HasPrefix(t.CommitId, commit.CommitId)) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
 func loadRemoteNames(c context.CommandRunner) []string {
 	bytes, _ := c.RunCommandImmediate(jj.GitRemoteList())
 	remotes := jj.ParseRemoteListOutput(string(bytes))
@@ -413,6 +548,9 @@ func NewModel(c *context.MainContext, revisions jj.SelectedRevisions) *Model {
 		listRenderer:      render.NewListRenderer(itemScrollMsg{}),
 		title:             "Git Operations",
 	}
+	if len(revisions.Revisions) > 0 {
+		m.tags = loadTags(c)
+	}
 	m.listRenderer.Z = render.ZMenuContent
 
 	items := m.createMenuItems()
@@ -639,6 +777,7 @@ func (m *Model) createMenuItems() []item {
 					name:     fmt.Sprintf("git push --bookmark %s --remote %s", b.Name, remote.Remote),
 					desc:     fmt.Sprintf("Git push bookmark %s to %s", b.Name, remote.Remote),
 					command:  jj.GitPush("--bookmark", b.Name, "--remote", remote.Remote),
+					variant:  "bookmark",
 					category: itemCategoryPush,
 				})
 			}
@@ -649,6 +788,7 @@ func (m *Model) createMenuItems() []item {
 			name:     fmt.Sprintf("git push --remote %s", selectedRemote),
 			desc:     "Push tracking bookmarks in the current revset",
 			command:  jj.GitPush("--remote", selectedRemote),
+			variant:  "remote",
 			category: itemCategoryPush,
 			key:      "p",
 		},
@@ -656,6 +796,7 @@ func (m *Model) createMenuItems() []item {
 			name:     fmt.Sprintf("git push --all --deleted --remote %s", selectedRemote),
 			desc:     "Push all bookmarks (including new and deleted bookmarks)",
 			command:  jj.GitPush("--all", "--deleted", "--remote", selectedRemote),
+			variant:  "all",
 			category: itemCategoryPush,
 			key:      "a",
 		},
@@ -672,16 +813,29 @@ func (m *Model) createMenuItems() []item {
 				name:     fmt.Sprintf("git push %s", strings.Join(revisions.AsPrefixedArgs("--change"), " ")),
 				desc:     fmt.Sprintf("Push selected changes (%s)", strings.Join(revisions.GetIds(), " ")),
 				command:  jj.GitPush(flags...),
+				variant:  "change",
 				key:      "c",
 			})
 	}
 
+	for _, commit := range revisions.Revisions {
+		for _, t := range m.tagsOf(commit) {
+			items = append(items, item{
+				name:     fmt.Sprintf("git push %s tag %s", selectedRemote, t.Name),
+				desc:     fmt.Sprintf("Push tag %s to %s with git", t.Name, selectedRemote),
+				command:  jj.GitPushTag(selectedRemote, t.Name),
+				category: itemCategoryPush,
+			})
+		}
+	}
+
 	for _, commit := range revisions.Revisions {
 		item := item{
 			category: itemCategoryPush,
 			name:     fmt.Sprintf("git push --change %s --remote %s", commit.GetChangeId(), selectedRemote),
 			desc:     fmt.Sprintf("Push the current change (%s)", commit.GetChangeId()),
 			command:  jj.GitPush("--change", commit.GetChangeId(), "--remote", selectedRemote),
+			variant:  "change",
 		}
 		if !hasMultipleRevisions {
 			item.key = "c"
@@ -694,6 +848,7 @@ func (m *Model) createMenuItems() []item {
 			name:     fmt.Sprintf("git push --deleted --remote %s", selectedRemote),
 			desc:     "Push all deleted bookmarks",
 			command:  jj.GitPush("--deleted", "--remote", selectedRemote),
+			variant:  "deleted",
 			category: itemCategoryPush,
 			key:      "d",
 		},
@@ -701,6 +856,7 @@ func (m *Model) createMenuItems() []item {
 			name:     fmt.Sprintf("git push --tracked --remote %s", selectedRemote),
 			desc:     "Push all tracked bookmarks",
 			command:  jj.GitPush("--tracked", "--remote", selectedRemote),
+			variant:  "tracked",
 			category: itemCategoryPush,
 			key:      "t",
 		},
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -91,6 +93,29 @@ func Test_PushChange(t *testing.T) {
 	test.SimulateModel(op, func() tea.Msg { return intents.Apply{} })
 }
 
+func Test_PushTagInColocatedRepository(t *testing.T) {
+	location := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(location, ".git"), 0o755))
+
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin"))
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte("v1.0.0\t\tabc123\t1a2b3c4d\trelease\nv0.9.0\t\txyz789\t9f8e7d6c\told\n"))
+	commandRunner.Expect(jj.BookmarkList("abc123")).SetOutput([]byte(""))
+	commandRunner.Expect(jj.GitPushTag("origin", "v1.0.0"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.Location = location
+	op := NewModel(ctx, jj.NewSelectedRevisions(&jj.Commit{ChangeId: "abc123", CommitId: "1a2b3c4d5e6f"}))
+	_ = test.RenderImmediate(op, 100, 40)
+
+	test.SimulateModel(op, func() tea.Msg { return intents.GitOpenFilter{} })
+	test.SimulateModel(op, test.Type("tag"))
+	test.SimulateModel(op, func() tea.Msg { return intents.Apply{} })
+	assert.Len(t, op.visibleItems(), 1)
+	test.SimulateModel(op, func() tea.Msg { return intents.Apply{} })
+}
+
 func Test_NewModel_DoesNotPanicWithNilSelectedRevision(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(""))
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+type pushPreviewMsg struct {
+	item    item
+	changes []jj.PushChange
+	err     error
+}
+
+// pushPreviewClosedMsg is sent when the preview is dismissed without pushing
+type pushPreviewClosedMsg struct{}
+
+// pushConfirmedMsg is sent when the previewed push is confirmed
+type pushConfirmedMsg struct {
+	item item
+}
+
+// run runs the command of item, first previewing what it would push when the
+// push variant is listed in git.preview_push
+func (m *Model) run(item item) tea.Cmd {
+	if item.category == itemCategoryPush && item.variant != "" && slices.Contains(config.Current.Git.PreviewPush, item.variant) {
+		return m.previewPush(item)
+	}
+	return m.startProgress(item)
+}
+
+func (m *Model) previewPush(item item) tea.Cmd {
+	// the dry run contacts the remote too, so it runs like the push itself with
+	// the askpass prompts wired up. jj reports what it would push on stderr.
+	args := append(slices.Clone(item.command), "--dry-run", "--color", "never")
+	return func() tea.Msg {
+		command, err := m.context.RunCommandWithProgress(jj.Args(args...))
+		if err != nil {
+			return pushPreviewMsg{item: item, err: err}
+		}
+		output, err := command.Wait()
+		if err != nil {
+			return pushPreviewMsg{item: item, err: err}
+		}
+		return pushPreviewMsg{item: item, changes: jj.ParsePushDryRunOutput(output)}
+	}
+}
+
+// openPreview shows what the push would change on the remote, letting the
+// user confirm it or go back to the menu
+func (m *Model) openPreview(msg pushPreviewMsg) {
+	closePreview := func() tea.Msg { return pushPreviewClosedMsg{} }
+	cancelKey := key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "cancel"))
+
+	title := "jj " + strings.Join(msg.item.command, " ")
+	var messages []string
+	var options []confirmation.Option
+	switch {
+	case msg.err != nil:
+		messages = []string{title, "", "dry run failed: " + strings.TrimSpace(msg.err.Error())}
+		options = append(options, confirmation.WithOption("Close", closePreview, cancelKey))
+	case len(msg.changes) == 0:
+		messages = []string{title, "", "Nothing to push"}
+		options = append(options, confirmation.WithOption("Close", closePreview, cancelKey))
+	default:
+		messages = append([]string{title, ""}, pushTable(msg.changes)...)
+		messages = append(messages, "")
+		push := func() tea.Msg { return pushConfirmedMsg{item: msg.item} }
+		options = append(options,
+			confirmation.WithOption("Push", push, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "push"))),
+			confirmation.WithOption("Cancel", closePreview, cancelKey),
+		)
+	}
+	// the preview opens on top of the menu, above its content
+	options = append(options, confirmation.WithStylePrefix("git"), confirmation.WithZIndex(render.ZMenuContent+2))
+	m.preview = confirmation.New(messages, options...)
+}
+
+// pushTable lays out the changes as aligned rows of bookmark, old target, new
+// target and what is notable about the change
+func pushTable(changes []jj.PushChange) []string {
+	rows := [][]string{{"bookmark", "from", "to", ""}}
+	for _, c := range changes {
+		note := ""
+		switch {
+		case c.Action == jj.PushAdd:
+			note = "new"
+		case c.Action == jj.PushDelete:
+			note = "deleted"
+		case c.Forced():
+			note = "forced"
+		}
+		rows = append(rows, []string{c.Bookmark, orDash(c.OldTarget), orDash(c.NewTarget), note})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], ansi.StringWidth(cell))
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			fmt.Fprintf(&line, "%-*s  ", widths[i], cell)
+		}
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+	}
+	return lines
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func (m *Model) renderPreview(dl *render.DisplayContext, box layout.Box) {
+	m.preview.Styles.Border = common.DefaultPalette.GetBorder("git confirmation border", lipgloss.NormalBorder()).Padding(1)
+	v := m.preview.View()
+	w, h := lipgloss.Size(v)
+	pw, ph := box.R.Dx(), box.R.Dy()
+	sx := box.R.Min.X + max((pw-w)/2, 0)
+	sy := box.R.Min.Y + max((ph-h)/2, 0)
+	frame := layout.Rect(sx, sy, w, h)
+	m.preview.ViewRect(dl, layout.Box{R: frame})
+}
@@ -0,0 +1,107 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/askpass"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const dryRunOutput = `Changes to push to origin:
+  Move sideways bookmark main from 1a2b3c4d to 5e6f7a8b
+  Add bookmark feature to 9a8b7c6d
+Dry-run requested, not pushing.`
+
+func previewAllPushes(t *testing.T, variants ...string) {
+	t.Helper()
+	previous := config.Current.Git.PreviewPush
+	config.Current.Git.PreviewPush = variants
+	t.Cleanup(func() { config.Current.Git.PreviewPush = previous })
+}
+
+func TestPushPreview_ShowsChangesAndPushesOnConfirm(t *testing.T) {
+	previewAllPushes(t, "all")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin"))
+	commandRunner.Expect(jj.GitPush("--all", "--deleted", "--remote", "origin", "--dry-run", "--color", "never")).SetOutput([]byte(dryRunOutput))
+	commandRunner.Expect(jj.GitPush("--all", "--deleted", "--remote", "origin"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), jj.NewSelectedRevisions())
+	test.SimulateModel(model, func() tea.Msg { return intents.GitFilter{Kind: intents.GitFilterPush} })
+	test.SimulateModel(model, func() tea.Msg { return intents.GitApplyShortcut{Key: "a"} })
+	assert.NotNil(t, model.preview)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 120, 40))
+	assert.Contains(t, rendered, "bookmark  from      to")
+	assert.Contains(t, rendered, "main      1a2b3c4d  5e6f7a8b  forced")
+	assert.Contains(t, rendered, "feature   -         9a8b7c6d  new")
+
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestPushPreview_CancelGoesBackToMenu(t *testing.T) {
+	previewAllPushes(t, "remote")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin"))
+	commandRunner.Expect(jj.GitPush("--remote", "origin", "--dry-run", "--color", "never")).SetOutput([]byte("Nothing changed."))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), jj.NewSelectedRevisions())
+	test.SimulateModel(model, func() tea.Msg { return intents.GitFilter{Kind: intents.GitFilterPush} })
+	test.SimulateModel(model, func() tea.Msg { return intents.GitFilter{Kind: intents.GitFilterPush} })
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 120, 40)), "Nothing to push")
+
+	test.SimulateModel(model, func() tea.Msg { return intents.Cancel{} })
+	assert.Nil(t, model.preview)
+}
+
+func TestPushPreview_SkipsVariantsNotConfigured(t *testing.T) {
+	previewAllPushes(t, "all")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte("origin"))
+	commandRunner.Expect(jj.GitPush("--tracked", "--remote", "origin"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), jj.NewSelectedRevisions())
+	test.SimulateModel(model, func() tea.Msg { return intents.GitFilter{Kind: intents.GitFilterPush} })
+	test.SimulateModel(model, func() tea.Msg { return intents.GitApplyShortcut{Key: "t"} })
+	assert.Nil(t, model.preview)
+}
+
+func TestPushPreview_ReadsDryRunOfRealCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for jj")
+	}
+	// a jj that reports the dry run on stderr like the real one
+	dir := t.TempDir()
+	script := "#!/bin/sh\nfor i in 1 2 3; do echo \"  Add bookmark b$i to 9a8b7c6d\" >&2; done\n"
+	if err := os.WriteFile(filepath.Join(dir, "jj"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx := &context.MainContext{CommandRunner: &context.MainCommandRunner{Location: dir, Askpass: askpass.NewUnstartedServer("JJUI_TEST")}}
+	model := &Model{context: ctx}
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- model.previewPush(item{command: jj.GitPush("--remote", "origin")})() }()
+
+	select {
+	case msg := <-msgs:
+		preview := msg.(pushPreviewMsg)
+		assert.NoError(t, preview.err)
+		assert.Len(t, preview.changes, 3)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dry run never finished")
+	}
+}
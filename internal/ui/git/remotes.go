@@ -0,0 +1,443 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+type remotesLoadedMsg struct {
+	remotes []jj.Remote
+	err     error
+}
+
+// remotesClosedMsg is sent when leaving the remotes view to go back to the
+// git menu
+type remotesClosedMsg struct{}
+
+// remoteRemoveConfirmedMsg is sent when removing the remote is confirmed
+type remoteRemoveConfirmedMsg struct {
+	name string
+}
+
+// remoteRemoveCancelledMsg is sent when the remote is kept
+type remoteRemoveCancelledMsg struct{}
+
+type remoteClickMsg struct {
+	Index int
+}
+
+type remoteScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m remoteScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type remoteInputKind int
+
+const (
+	remoteInputNone remoteInputKind = iota
+	remoteInputAddName
+	remoteInputAddUrl
+	remoteInputRename
+	remoteInputSetUrl
+)
+
+// remotesModel lists the git remotes with their URLs and adds, removes,
+// renames and re-URLs them
+type remotesModel struct {
+	context             *context.MainContext
+	remotes             []jj.Remote
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	editing             remoteInputKind
+	newName             string
+	input               textinput.Model
+	listRenderer        *render.ListRenderer
+	// confirm asks before removing a remote, as that also forgets its
+	// remote-tracking bookmarks
+	confirm *confirmation.Model
+}
+
+func newRemotesModel(c *context.MainContext) *remotesModel {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 0
+
+	m := &remotesModel{
+		context:      c,
+		input:        input,
+		listRenderer: render.NewListRenderer(remoteScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
+
+func (m *remotesModel) Scopes() []dispatch.Scope {
+	if m.confirm != nil {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeGitRemotesConfirmation,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	if m.editing != remoteInputNone {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeGitRemotesInput,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeGitRemotes,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *remotesModel) IsEditing() bool {
+	return m.editing != remoteInputNone
+}
+
+func (m *remotesModel) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *remotesModel) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.GitRemoteList())
+	if err != nil {
+		return remotesLoadedMsg{err: err}
+	}
+	return remotesLoadedMsg{remotes: jj.ParseRemotes(string(output))}
+}
+
+func (m *remotesModel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case remoteRemoveConfirmedMsg:
+		m.confirm = nil
+		return m.run(jj.GitRemoteRemove(msg.name))
+	case remoteRemoveCancelledMsg:
+		m.confirm = nil
+		return nil
+	}
+	if m.confirm != nil {
+		return m.confirm.Update(msg)
+	}
+	switch msg := msg.(type) {
+	case remotesLoadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.remotes = msg.remotes
+		m.cursor = min(m.cursor, max(len(m.remotes)-1, 0))
+	case remoteClickMsg:
+		if m.editing == remoteInputNone && msg.Index >= 0 && msg.Index < len(m.remotes) {
+			m.cursor = msg.Index
+		}
+	case remoteScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.editing != remoteInputNone {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *remotesModel) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	if m.confirm != nil {
+		switch intent.(type) {
+		case intents.Apply, intents.Cancel, intents.OptionSelect:
+			return m.confirm.Update(intent), true
+		}
+		return nil, false
+	}
+	if m.editing != remoteInputNone {
+		switch intent.(type) {
+		case intents.Apply:
+			return m.accept(), true
+		case intents.Cancel:
+			m.stopEditing()
+			return nil, true
+		}
+		return nil, false
+	}
+	switch intent := intent.(type) {
+	case intents.GitRemotesNavigate:
+		if len(m.remotes) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.remotes)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.GitRemotesAdd:
+		return m.startEditing(remoteInputAddName, ""), true
+	case intents.GitRemotesRename:
+		if r := m.current(); r != nil {
+			return m.startEditing(remoteInputRename, r.Name), true
+		}
+		return nil, true
+	case intents.GitRemotesSetUrl:
+		if r := m.current(); r != nil {
+			return m.startEditing(remoteInputSetUrl, r.URL), true
+		}
+		return nil, true
+	case intents.GitRemotesRemove:
+		if r := m.current(); r != nil {
+			m.confirmRemove(r.Name)
+		}
+		return nil, true
+	case intents.Cancel:
+		return func() tea.Msg { return remotesClosedMsg{} }, true
+	}
+	return nil, false
+}
+
+func (m *remotesModel) current() *jj.Remote {
+	if m.cursor < 0 || m.cursor >= len(m.remotes) {
+		return nil
+	}
+	return &m.remotes[m.cursor]
+}
+
+func (m *remotesModel) startEditing(kind remoteInputKind, value string) tea.Cmd {
+	m.editing = kind
+	m.message = ""
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *remotesModel) stopEditing() {
+	m.editing = remoteInputNone
+	m.newName = ""
+	m.input.Blur()
+}
+
+// accept runs the command for the value entered, keeping the input open with
+// a message when the value is not valid
+func (m *remotesModel) accept() tea.Cmd {
+	value := strings.TrimSpace(m.input.Value())
+	switch m.editing {
+	case remoteInputAddName:
+		if m.message = m.validateName(value, ""); m.message != "" {
+			return nil
+		}
+		m.newName = value
+		return m.startEditing(remoteInputAddUrl, "")
+	case remoteInputAddUrl:
+		if value == "" {
+			m.message = "url cannot be empty"
+			return nil
+		}
+		name := m.newName
+		m.stopEditing()
+		return m.run(jj.GitRemoteAdd(name, value))
+	case remoteInputRename:
+		r := m.current()
+		if r == nil || value == r.Name {
+			m.stopEditing()
+			return nil
+		}
+		if m.message = m.validateName(value, r.Name); m.message != "" {
+			return nil
+		}
+		m.stopEditing()
+		return m.run(jj.GitRemoteRename(r.Name, value))
+	case remoteInputSetUrl:
+		r := m.current()
+		if value == "" {
+			m.message = "url cannot be empty"
+			return nil
+		}
+		m.stopEditing()
+		if r == nil || value == r.URL {
+			return nil
+		}
+		return m.run(jj.GitRemoteSetUrl(r.Name, value))
+	}
+	return nil
+}
+
+// validateName returns why name cannot be used for a remote, or an empty
+// string if it can. current is the name of the remote being renamed.
+func (m *remotesModel) validateName(name string, current string) string {
+	switch {
+	case name == "":
+		return "name cannot be empty"
+	case name == "git":
+		return `"git" is reserved by jj`
+	case strings.ContainsAny(name, " \t/\\:?*[~^") || strings.Contains(name, "..") || strings.HasPrefix(name, "-") || strings.HasSuffix(name, ".lock"):
+		return fmt.Sprintf("%q is not a valid remote name", name)
+	case name != current && slices.ContainsFunc(m.remotes, func(r jj.Remote) bool { return r.Name == name }):
+		return fmt.Sprintf("remote %s already exists", name)
+	}
+	return ""
+}
+
+func (m *remotesModel) confirmRemove(name string) {
+	remove := func() tea.Msg { return remoteRemoveConfirmedMsg{name: name} }
+	keep := func() tea.Msg { return remoteRemoveCancelledMsg{} }
+	m.confirm = confirmation.New(
+		[]string{fmt.Sprintf("Remove remote %s?", name), "Its remote-tracking bookmarks are forgotten too.", ""},
+		confirmation.WithOption("Remove", remove, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "remove"))),
+		confirmation.WithOption("Cancel", keep, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "cancel"))),
+		confirmation.WithStylePrefix("git"),
+		confirmation.WithZIndex(render.ZMenuContent+2),
+	)
+}
+
+func (m *remotesModel) run(args jj.CommandArgs) tea.Cmd {
+	m.message = ""
+	return m.context.RunCommand(args, m.load, common.Refresh)
+}
+
+func (m *remotesModel) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 80), min(ph, 20))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("git menu text")
+	dimmedStyle := common.DefaultPalette.Get("git menu dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("git menu border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("Git Remotes", common.DefaultPalette.Get("git menu title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	if m.editing != remoteInputNone {
+		var inputBox layout.Box
+		contentBox, inputBox = contentBox.CutBottom(1)
+		label := m.inputLabel()
+		dl.AddDraw(inputBox.R, dimmedStyle.Render(label), render.ZMenuContent)
+		_, inputBox = inputBox.CutLeft(ansi.StringWidth(label))
+		m.input.SetWidth(max(inputBox.R.Dx()-1, 1))
+		dl.AddDraw(inputBox.R, m.input.View(), render.ZMenuContent)
+		contentBox, _ = contentBox.CutBottom(1)
+	}
+	if len(m.remotes) == 0 {
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("no remotes"), render.ZMenuContent)
+		return
+	}
+	m.renderRemotes(dl, contentBox)
+
+	if m.confirm != nil {
+		m.confirm.Styles.Border = common.DefaultPalette.GetBorder("git confirmation border", lipgloss.NormalBorder()).Padding(1)
+		v := m.confirm.View()
+		w, h := lipgloss.Size(v)
+		confirmBox := layout.Box{R: layout.Rect(frame.R.Min.X+max((frame.R.Dx()-w)/2, 0), frame.R.Min.Y+max((frame.R.Dy()-h)/2, 0), w, h)}
+		m.confirm.ViewRect(dl, confirmBox)
+	}
+}
+
+func (m *remotesModel) inputLabel() string {
+	r := m.current()
+	switch {
+	case m.editing == remoteInputAddName:
+		return "name of the new remote: "
+	case m.editing == remoteInputAddUrl:
+		return fmt.Sprintf("url of %s: ", m.newName)
+	case m.editing == remoteInputRename && r != nil:
+		return fmt.Sprintf("rename %s to: ", r.Name)
+	case m.editing == remoteInputSetUrl && r != nil:
+		return fmt.Sprintf("url of %s: ", r.Name)
+	}
+	return ""
+}
+
+func (m *remotesModel) renderRemotes(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	nameWidth := 0
+	for _, r := range m.remotes {
+		nameWidth = max(nameWidth, ansi.StringWidth(r.Name))
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.remotes))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.remotes),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			r := m.remotes[index]
+			textStyle := common.DefaultPalette.Get("git menu text")
+			dimmedStyle := common.DefaultPalette.Get("git menu dimmed")
+			if index == m.cursor {
+				textStyle = common.DefaultPalette.Get("git menu selected text")
+				dimmedStyle = common.DefaultPalette.Get("git menu selected dimmed")
+				dl.AddFill(rect, ' ', textStyle, render.ZMenuContent)
+			}
+			name := fmt.Sprintf(" %-*s ", nameWidth, r.Name)
+			remaining := max(rect.Dx()-ansi.StringWidth(name), 0)
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(name, textStyle).
+				Styled(ansi.Truncate(r.URL, remaining, "…"), dimmedStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return remoteClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const remoteListOutput = "origin https://github.com/user/repo.git\nupstream https://github.com/upstream/repo.git\n"
+
+func openRemotes(t *testing.T, commandRunner *test.CommandRunner) *Model {
+	t.Helper()
+	model := NewModel(test.NewTestContext(commandRunner), jj.NewSelectedRevisions())
+	test.SimulateModel(model, func() tea.Msg { return intents.GitOpenRemotes{} })
+	return model
+}
+
+func TestRemotes_ListsRemotesWithUrls(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(remoteListOutput))
+	defer commandRunner.Verify()
+
+	model := openRemotes(t, commandRunner)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 40))
+	assert.Contains(t, rendered, "origin   https://github.com/user/repo.git")
+	assert.Contains(t, rendered, "upstream https://github.com/upstream/repo.git")
+}
+
+func TestRemotes_AddAsksForNameThenUrl(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(remoteListOutput))
+	commandRunner.Expect(jj.GitRemoteAdd("fork", "git@github.com:me/repo.git"))
+	defer commandRunner.Verify()
+
+	model := openRemotes(t, commandRunner)
+	test.SimulateModel(model, func() tea.Msg { return intents.GitRemotesAdd{} })
+	assert.True(t, model.IsEditing())
+
+	model.remotes.input.SetValue("origin")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.Equal(t, "remote origin already exists", model.remotes.message)
+
+	model.remotes.input.SetValue("my fork")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.Equal(t, `"my fork" is not a valid remote name`, model.remotes.message)
+
+	model.remotes.input.SetValue("fork")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.Equal(t, remoteInputAddUrl, model.remotes.editing)
+
+	model.remotes.input.SetValue("git@github.com:me/repo.git")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.False(t, model.IsEditing())
+}
+
+func TestRemotes_RenameSetUrlAndRemoveTheSelectedRemote(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(remoteListOutput))
+	commandRunner.Expect(jj.GitRemoteRename("upstream", "main"))
+	commandRunner.Expect(jj.GitRemoteSetUrl("upstream", "https://example.com/repo.git"))
+	commandRunner.Expect(jj.GitRemoteRemove("upstream"))
+	defer commandRunner.Verify()
+
+	model := openRemotes(t, commandRunner)
+	model.Update(intents.GitRemotesNavigate{Delta: 1})
+
+	test.SimulateModel(model, func() tea.Msg { return intents.GitRemotesRename{} })
+	assert.Equal(t, "upstream", model.remotes.input.Value())
+	model.remotes.input.SetValue("main")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+
+	test.SimulateModel(model, func() tea.Msg { return intents.GitRemotesSetUrl{} })
+	assert.Equal(t, "https://github.com/upstream/repo.git", model.remotes.input.Value())
+	model.remotes.input.SetValue("https://example.com/repo.git")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+
+	test.SimulateModel(model, func() tea.Msg { return intents.GitRemotesRemove{} })
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 40)), "Remove remote upstream?")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.Nil(t, model.remotes.confirm)
+}
+
+func TestRemotes_RemoveCanBeCancelled(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(remoteListOutput))
+	defer commandRunner.Verify()
+
+	model := openRemotes(t, commandRunner)
+	test.SimulateModel(model, func() tea.Msg { return intents.GitRemotesRemove{} })
+	test.SimulateModel(model, func() tea.Msg { return intents.Cancel{} })
+
+	assert.Nil(t, model.remotes.confirm)
+	assert.NotContains(t, test.Stripped(test.RenderImmediate(model, 100, 40)), "Remove remote")
+}
+
+func TestRemotes_CancelGoesBackToMenuWithReloadedRemotes(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitRemoteList()).SetOutput([]byte(remoteListOutput))
+	defer commandRunner.Verify()
+
+	model := openRemotes(t, commandRunner)
+	test.SimulateModel(model, func() tea.Msg { return intents.Cancel{} })
+
+	assert.Nil(t, model.remotes)
+	assert.Equal(t, []string{"origin", "upstream"}, model.remoteNames)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 40)), "Git Operations")
+}
@@ -29,6 +29,7 @@ var scopeDisplayNames = map[string]string{
 	"revisions.squash":               "Squash",
 	"revisions.revert":               "Revert",
 	"revisions.duplicate":            "Duplicate",
+	"revisions.compare":              "Compare",
 	"revisions.abandon":              "Abandon",
 	"revisions.set_parents":          "Set Parents",
 	"revisions.details":              "File Details",
@@ -46,6 +47,7 @@ var scopeDisplayNames = map[string]string{
 	"git.filter":                     "Git Filter",
 	"oplog":                          "Operation Log",
 	"oplog.quick_search":             "Operation Log Search",
+	"oplog.filter":                   "Operation Log Filter",
 	"diff":                           "Diff Viewer",
 	"undo":                           "Undo",
 	"redo":                           "Redo",
@@ -66,6 +68,7 @@ var scopeOrder = []string{
 	"revisions.squash",
 	"revisions.revert",
 	"revisions.duplicate",
+	"revisions.compare",
 	"revisions.abandon",
 	"revisions.set_parents",
 	"revisions.details",
@@ -83,6 +86,7 @@ var scopeOrder = []string{
 	"git.filter",
 	"oplog",
 	"oplog.quick_search",
+	"oplog.filter",
 	"diff",
 	"file_search",
 	"command_history",
@@ -0,0 +1,29 @@
+package intents
+
+type OpenAnnotate struct {
+	ChangeId string
+	File     string
+}
+
+func (OpenAnnotate) isIntent() {}
+
+//jjui:bind scope=annotate action=move_up set=Delta:-1
+//jjui:bind scope=annotate action=move_down set=Delta:1
+//jjui:bind scope=annotate action=page_up set=Delta:-1,IsPage:true
+//jjui:bind scope=annotate action=page_down set=Delta:1,IsPage:true
+type AnnotateNavigate struct {
+	Delta  int
+	IsPage bool
+}
+
+func (AnnotateNavigate) isIntent() {}
+
+//jjui:bind scope=annotate action=jump_to_revision
+type AnnotateJump struct{}
+
+func (AnnotateJump) isIntent() {}
+
+//jjui:bind scope=annotate action=diff
+type AnnotateShowDiff struct{}
+
+func (AnnotateShowDiff) isIntent() {}
@@ -4,6 +4,8 @@ package intents
 //jjui:bind scope=revisions.target_picker action=autocomplete_back set=Reverse:true
 //jjui:bind scope=revisions.set_bookmark action=autocomplete
 //jjui:bind scope=revisions.set_bookmark action=autocomplete_back set=Reverse:true
+//jjui:bind scope=revisions.set_tag action=autocomplete
+//jjui:bind scope=revisions.set_tag action=autocomplete_back set=Reverse:true
 type AutocompleteCycle struct {
 	Reverse bool
 }
@@ -0,0 +1,32 @@
+package intents
+
+//jjui:bind scope=ui action=show_compare set=From:$string(from),To:$string(to)
+type ShowCompare struct {
+	From string
+	To   string
+}
+
+func (ShowCompare) isIntent() {}
+
+//jjui:bind scope=revisions.compare action=target_picker
+type CompareOpenTargetPicker struct{}
+
+func (CompareOpenTargetPicker) isIntent() {}
+
+//jjui:bind scope=compare_files action=move_up set=Delta:-1
+//jjui:bind scope=compare_files action=move_down set=Delta:1
+type CompareNavigate struct {
+	Delta int
+}
+
+func (CompareNavigate) isIntent() {}
+
+//jjui:bind scope=compare_files action=diff
+type CompareDiff struct{}
+
+func (CompareDiff) isIntent() {}
+
+//jjui:bind scope=compare_files action=swap
+type CompareSwap struct{}
+
+func (CompareSwap) isIntent() {}
@@ -0,0 +1,38 @@
+package intents
+
+//jjui:bind scope=ui action=open_conflicts
+type OpenConflicts struct{}
+
+func (OpenConflicts) isIntent() {}
+
+//jjui:bind scope=conflicts action=move_up set=Delta:-1
+//jjui:bind scope=conflicts action=move_down set=Delta:1
+type ConflictsNavigate struct {
+	Delta int
+}
+
+func (ConflictsNavigate) isIntent() {}
+
+//jjui:bind scope=conflicts action=diff
+type ConflictsDiff struct{}
+
+func (ConflictsDiff) isIntent() {}
+
+// ConflictsResolveTool is the merge tool a conflicted file is resolved with,
+// empty meaning jjui's own merge tool
+type ConflictsResolveTool string
+
+const (
+	ConflictsResolveOurs   ConflictsResolveTool = ":ours"
+	ConflictsResolveTheirs ConflictsResolveTool = ":theirs"
+	ConflictsResolveMerge  ConflictsResolveTool = ""
+)
+
+//jjui:bind scope=conflicts action=take_ours set=Tool:ConflictsResolveOurs
+//jjui:bind scope=conflicts action=take_theirs set=Tool:ConflictsResolveTheirs
+//jjui:bind scope=conflicts action=resolve set=Tool:ConflictsResolveMerge
+type ConflictsResolve struct {
+	Tool ConflictsResolveTool
+}
+
+func (ConflictsResolve) isIntent() {}
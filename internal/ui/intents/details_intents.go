@@ -30,6 +30,11 @@ type DetailsSplit struct {
 
 func (DetailsSplit) isIntent() {}
 
+//jjui:bind scope=revisions.details action=explode
+type DetailsExplode struct{}
+
+func (DetailsExplode) isIntent() {}
+
 //jjui:bind scope=revisions.details action=squash
 type DetailsSquash struct{}
 
@@ -40,6 +45,11 @@ type DetailsRestore struct{}
 
 func (DetailsRestore) isIntent() {}
 
+//jjui:bind scope=revisions.details action=resolve
+type DetailsResolve struct{}
+
+func (DetailsResolve) isIntent() {}
+
 //jjui:bind scope=revisions.details action=absorb
 type DetailsAbsorb struct{}
 
@@ -55,6 +65,16 @@ type DetailsRevisionsChangingFile struct{}
 
 func (DetailsRevisionsChangingFile) isIntent() {}
 
+//jjui:bind scope=revisions.details action=annotate
+type DetailsAnnotate struct{}
+
+func (DetailsAnnotate) isIntent() {}
+
+//jjui:bind scope=revisions.details action=file_history
+type DetailsFileHistory struct{}
+
+func (DetailsFileHistory) isIntent() {}
+
 //jjui:bind scope=revisions.details action=select_file set=File:$string(file)
 type DetailsSelectFile struct {
 	File string
@@ -0,0 +1,43 @@
+package intents
+
+//jjui:bind scope=diff_editor action=move_up set=Delta:-1
+//jjui:bind scope=diff_editor action=move_down set=Delta:1
+//jjui:bind scope=diff_editor action=page_up set=Delta:-1,IsPage:true
+//jjui:bind scope=diff_editor action=page_down set=Delta:1,IsPage:true
+type DiffEditorNavigate struct {
+	Delta  int
+	IsPage bool
+}
+
+func (DiffEditorNavigate) isIntent() {}
+
+//jjui:bind scope=diff_editor action=next_file set=Delta:1
+//jjui:bind scope=diff_editor action=prev_file set=Delta:-1
+type DiffEditorJumpFile struct {
+	Delta int
+}
+
+func (DiffEditorJumpFile) isIntent() {}
+
+//jjui:bind scope=diff_editor action=next_hunk set=Delta:1
+//jjui:bind scope=diff_editor action=prev_hunk set=Delta:-1
+type DiffEditorJumpHunk struct {
+	Delta int
+}
+
+func (DiffEditorJumpHunk) isIntent() {}
+
+//jjui:bind scope=diff_editor action=toggle
+type DiffEditorToggle struct{}
+
+func (DiffEditorToggle) isIntent() {}
+
+//jjui:bind scope=diff_editor action=toggle_all
+type DiffEditorToggleAll struct{}
+
+func (DiffEditorToggleAll) isIntent() {}
+
+//jjui:bind scope=diff_editor action=toggle_collapse
+type DiffEditorToggleCollapse struct{}
+
+func (DiffEditorToggleCollapse) isIntent() {}
@@ -47,9 +47,48 @@ type DiffToggleWrap struct{}
 
 func (DiffToggleWrap) isIntent() {}
 
+//jjui:bind scope=diff action=toggle_side_by_side
+type DiffToggleSideBySide struct{}
+
+func (DiffToggleSideBySide) isIntent() {}
+
+//jjui:bind scope=diff.quick_search action=clear
+type DiffQuickSearchClear struct{}
+
+func (DiffQuickSearchClear) isIntent() {}
+
 //jjui:bind scope=diff action=show set=Content:$string(content)
 type DiffShow struct {
 	Content string
 }
 
 func (DiffShow) isIntent() {}
+
+//jjui:bind scope=diff action=next_file set=Delta:1
+//jjui:bind scope=diff action=prev_file set=Delta:-1
+type DiffJumpFile struct {
+	Delta int
+}
+
+func (DiffJumpFile) isIntent() {}
+
+//jjui:bind scope=diff action=next_hunk set=Delta:1
+//jjui:bind scope=diff action=prev_hunk set=Delta:-1
+type DiffJumpHunk struct {
+	Delta int
+}
+
+func (DiffJumpHunk) isIntent() {}
+
+//jjui:bind scope=diff action=toggle_file_list
+type DiffToggleFileList struct{}
+
+func (DiffToggleFileList) isIntent() {}
+
+//jjui:bind scope=diff action=toggle_collapse
+//jjui:bind scope=diff action=toggle_collapse_all set=All:true
+type DiffToggleCollapse struct {
+	All bool
+}
+
+func (DiffToggleCollapse) isIntent() {}
@@ -0,0 +1,35 @@
+package intents
+
+//jjui:bind scope=ui action=open_divergence
+type OpenDivergence struct{}
+
+func (OpenDivergence) isIntent() {}
+
+//jjui:bind scope=divergence action=move_up set=Delta:-1
+//jjui:bind scope=divergence action=move_down set=Delta:1
+type DivergenceNavigate struct {
+	Delta int
+}
+
+func (DivergenceNavigate) isIntent() {}
+
+//jjui:bind scope=divergence action=prev_version set=Delta:-1
+//jjui:bind scope=divergence action=next_version set=Delta:1
+type DivergenceCycleVersion struct {
+	Delta int
+}
+
+func (DivergenceCycleVersion) isIntent() {}
+
+//jjui:bind scope=divergence action=keep
+//jjui:bind scope=divergence action=squash set=Squash:true
+type DivergenceKeep struct {
+	Squash bool
+}
+
+func (DivergenceKeep) isIntent() {}
+
+//jjui:bind scope=divergence action=interdiff
+type DivergenceInterdiff struct{}
+
+func (DivergenceInterdiff) isIntent() {}
@@ -20,3 +20,13 @@ func (EvologDiff) isIntent() {}
 type EvologRestore struct{}
 
 func (EvologRestore) isIntent() {}
+
+//jjui:bind scope=revisions.evolog action=mark
+type EvologMark struct{}
+
+func (EvologMark) isIntent() {}
+
+//jjui:bind scope=revisions.evolog action=interdiff
+type EvologInterdiff struct{}
+
+func (EvologInterdiff) isIntent() {}
@@ -0,0 +1,57 @@
+package intents
+
+import "github.com/idursun/jjui/internal/jj"
+
+// OpenExplode opens the explode view for Revision. Grouped are the files
+// checked in the details list, which start out in the same commit.
+type OpenExplode struct {
+	Revision *jj.Commit
+	Files    []string
+	Grouped  []string
+}
+
+func (OpenExplode) isIntent() {}
+
+//jjui:bind scope=explode action=move_up set=Delta:-1
+//jjui:bind scope=explode action=move_down set=Delta:1
+type ExplodeNavigate struct {
+	Delta int
+}
+
+func (ExplodeNavigate) isIntent() {}
+
+type ExplodeMode string
+
+const (
+	ExplodeByFile      ExplodeMode = "file"
+	ExplodeByDirectory ExplodeMode = "directory"
+)
+
+//jjui:bind scope=explode action=by_file set=Mode:ExplodeByFile
+//jjui:bind scope=explode action=by_directory set=Mode:ExplodeByDirectory
+type ExplodeSetMode struct {
+	Mode ExplodeMode
+}
+
+func (ExplodeSetMode) isIntent() {}
+
+//jjui:bind scope=explode action=ungroup set=Group:0
+//jjui:bind scope=explode action=group_1 set=Group:1
+//jjui:bind scope=explode action=group_2 set=Group:2
+//jjui:bind scope=explode action=group_3 set=Group:3
+//jjui:bind scope=explode action=group_4 set=Group:4
+//jjui:bind scope=explode action=group_5 set=Group:5
+//jjui:bind scope=explode action=group_6 set=Group:6
+//jjui:bind scope=explode action=group_7 set=Group:7
+//jjui:bind scope=explode action=group_8 set=Group:8
+//jjui:bind scope=explode action=group_9 set=Group:9
+type ExplodeAssignGroup struct {
+	Group int
+}
+
+func (ExplodeAssignGroup) isIntent() {}
+
+//jjui:bind scope=explode action=edit_template
+type ExplodeEditTemplate struct{}
+
+func (ExplodeEditTemplate) isIntent() {}
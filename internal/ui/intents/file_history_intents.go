@@ -0,0 +1,31 @@
+package intents
+
+type OpenFileHistory struct {
+	File string
+}
+
+func (OpenFileHistory) isIntent() {}
+
+//jjui:bind scope=file_history action=move_up set=Delta:-1
+//jjui:bind scope=file_history action=move_down set=Delta:1
+//jjui:bind scope=file_history action=page_up set=Delta:-1,IsPage:true
+//jjui:bind scope=file_history action=page_down set=Delta:1,IsPage:true
+type FileHistoryNavigate struct {
+	Delta  int
+	IsPage bool
+}
+
+func (FileHistoryNavigate) isIntent() {}
+
+//jjui:bind scope=file_history action=diff_scroll_up set=Delta:-10
+//jjui:bind scope=file_history action=diff_scroll_down set=Delta:10
+type FileHistoryScrollDiff struct {
+	Delta int
+}
+
+func (FileHistoryScrollDiff) isIntent() {}
+
+//jjui:bind scope=file_history action=restore
+type FileHistoryRestore struct{}
+
+func (FileHistoryRestore) isIntent() {}
@@ -0,0 +1,48 @@
+package intents
+
+//jjui:bind scope=merge_tool action=next_conflict set=Delta:1
+//jjui:bind scope=merge_tool action=prev_conflict set=Delta:-1
+type MergeToolNavigate struct {
+	Delta int
+}
+
+func (MergeToolNavigate) isIntent() {}
+
+type MergeToolSide string
+
+const (
+	MergeToolSideLeft  MergeToolSide = "left"
+	MergeToolSideRight MergeToolSide = "right"
+	MergeToolSideBoth  MergeToolSide = "both"
+	MergeToolSideBase  MergeToolSide = "base"
+	MergeToolSideNone  MergeToolSide = "none"
+)
+
+//jjui:bind scope=merge_tool action=take_left set=Side:MergeToolSideLeft
+//jjui:bind scope=merge_tool action=take_right set=Side:MergeToolSideRight
+//jjui:bind scope=merge_tool action=take_both set=Side:MergeToolSideBoth
+//jjui:bind scope=merge_tool action=take_base set=Side:MergeToolSideBase
+//jjui:bind scope=merge_tool action=unresolve set=Side:MergeToolSideNone
+type MergeToolPick struct {
+	Side MergeToolSide
+}
+
+func (MergeToolPick) isIntent() {}
+
+//jjui:bind scope=merge_tool action=edit
+type MergeToolEdit struct{}
+
+func (MergeToolEdit) isIntent() {}
+
+//jjui:bind scope=merge_tool.edit action=accept
+type MergeToolEditAccept struct{}
+
+func (MergeToolEditAccept) isIntent() {}
+
+//jjui:bind scope=merge_tool action=scroll_up set=Delta:-1
+//jjui:bind scope=merge_tool action=scroll_down set=Delta:1
+type MergeToolScroll struct {
+	Delta int
+}
+
+func (MergeToolScroll) isIntent() {}
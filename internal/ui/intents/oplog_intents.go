@@ -42,6 +42,47 @@ type OpLogRevert struct {
 
 func (OpLogRevert) isIntent() {}
 
+//jjui:bind scope=oplog action=mark
+type OpLogMark struct{}
+
+func (OpLogMark) isIntent() {}
+
+//jjui:bind scope=oplog action=compare
+type OpLogCompare struct{}
+
+func (OpLogCompare) isIntent() {}
+
+//jjui:bind scope=oplog action=filter
+type OpLogOpenFilter struct{}
+
+func (OpLogOpenFilter) isIntent() {}
+
+//jjui:bind scope=oplog action=toggle_collapse
+type OpLogToggleCollapse struct{}
+
+func (OpLogToggleCollapse) isIntent() {}
+
+//jjui:bind scope=ui action=show_op_diff set=From:$string(from),To:$string(to)
+type ShowOpDiff struct {
+	From string
+	To   string
+}
+
+func (ShowOpDiff) isIntent() {}
+
+//jjui:bind scope=op_diff action=move_up set=Delta:-1
+//jjui:bind scope=op_diff action=move_down set=Delta:1
+type OpDiffNavigate struct {
+	Delta int
+}
+
+func (OpDiffNavigate) isIntent() {}
+
+//jjui:bind scope=op_diff action=diff
+type OpDiffShowDiff struct{}
+
+func (OpDiffShowDiff) isIntent() {}
+
 //jjui:bind scope=oplog.quick_search action=clear
 type OpLogQuickSearchClear struct{}
 
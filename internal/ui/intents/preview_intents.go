@@ -10,6 +10,11 @@ type PreviewToggleBottom struct{}
 
 func (PreviewToggleBottom) isIntent() {}
 
+//jjui:bind scope=ui action=preview_toggle_side_by_side
+type PreviewToggleSideBySide struct{}
+
+func (PreviewToggleSideBySide) isIntent() {}
+
 //jjui:bind scope=ui action=preview_expand
 type PreviewExpand struct{}
 
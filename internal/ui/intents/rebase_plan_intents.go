@@ -0,0 +1,46 @@
+package intents
+
+//jjui:bind scope=ui action=open_rebase_plan
+type OpenRebasePlan struct{}
+
+func (OpenRebasePlan) isIntent() {}
+
+//jjui:bind scope=rebase_plan action=move_up set=Delta:-1
+//jjui:bind scope=rebase_plan action=move_down set=Delta:1
+type RebasePlanNavigate struct {
+	Delta int
+}
+
+func (RebasePlanNavigate) isIntent() {}
+
+//jjui:bind scope=rebase_plan action=move_entry_up set=Delta:-1
+//jjui:bind scope=rebase_plan action=move_entry_down set=Delta:1
+type RebasePlanMove struct {
+	Delta int
+}
+
+func (RebasePlanMove) isIntent() {}
+
+type RebasePlanAction string
+
+const (
+	RebasePlanPick   RebasePlanAction = "pick"
+	RebasePlanDrop   RebasePlanAction = "drop"
+	RebasePlanSquash RebasePlanAction = "squash"
+	RebasePlanReword RebasePlanAction = "reword"
+)
+
+//jjui:bind scope=rebase_plan action=pick set=Action:RebasePlanPick
+//jjui:bind scope=rebase_plan action=drop set=Action:RebasePlanDrop
+//jjui:bind scope=rebase_plan action=squash set=Action:RebasePlanSquash
+//jjui:bind scope=rebase_plan action=reword set=Action:RebasePlanReword
+type RebasePlanSetAction struct {
+	Action RebasePlanAction
+}
+
+func (RebasePlanSetAction) isIntent() {}
+
+//jjui:bind scope=rebase_plan.reword action=accept
+type RebasePlanRewordAccept struct{}
+
+func (RebasePlanRewordAccept) isIntent() {}
@@ -115,6 +115,15 @@ type Navigate struct {
 
 func (Navigate) isIntent() {}
 
+// JumpToRevision closes every view open on top of the revisions, whatever the
+// depth, and selects the change
+type JumpToRevision struct {
+	ChangeID   string
+	FallbackID string
+}
+
+func (JumpToRevision) isIntent() {}
+
 //jjui:bind scope=revisions action=new
 type StartNew struct {
 	Selected jj.SelectedRevisions
@@ -199,3 +208,10 @@ type Refresh struct {
 }
 
 func (Refresh) isIntent() {}
+
+//jjui:bind scope=revisions action=open_compare
+type OpenCompare struct {
+	Selected jj.SelectedRevisions
+}
+
+func (OpenCompare) isIntent() {}
@@ -9,6 +9,8 @@ func (QuickSearch) isIntent() {}
 //jjui:bind scope=revisions.quick_search action=prev set=Reverse:true
 //jjui:bind scope=oplog.quick_search action=next
 //jjui:bind scope=oplog.quick_search action=prev set=Reverse:true
+//jjui:bind scope=diff.quick_search action=next
+//jjui:bind scope=diff.quick_search action=prev set=Reverse:true
 type QuickSearchCycle struct {
 	Reverse bool
 }
@@ -48,6 +50,11 @@ type FileSearchEdit struct{}
 
 func (FileSearchEdit) isIntent() {}
 
+//jjui:bind scope=file_search action=file_history
+type FileSearchHistory struct{}
+
+func (FileSearchHistory) isIntent() {}
+
 //jjui:bind scope=file_search action=toggle
 type FileSearchTogglePreview struct{}
 
@@ -0,0 +1,31 @@
+package intents
+
+//jjui:bind scope=ui action=open_sparse
+type OpenSparse struct{}
+
+func (OpenSparse) isIntent() {}
+
+//jjui:bind scope=sparse action=move_up set=Delta:-1
+//jjui:bind scope=sparse action=move_down set=Delta:1
+//jjui:bind scope=sparse.picker action=move_up set=Delta:-1
+//jjui:bind scope=sparse.picker action=move_down set=Delta:1
+type SparseNavigate struct {
+	Delta int
+}
+
+func (SparseNavigate) isIntent() {}
+
+//jjui:bind scope=sparse action=add
+type SparseAdd struct{}
+
+func (SparseAdd) isIntent() {}
+
+//jjui:bind scope=sparse action=remove
+type SparseRemove struct{}
+
+func (SparseRemove) isIntent() {}
+
+//jjui:bind scope=sparse action=reset
+type SparseReset struct{}
+
+func (SparseReset) isIntent() {}
@@ -0,0 +1,29 @@
+package intents
+
+//jjui:bind scope=ui action=open_tags
+type OpenTags struct{}
+
+func (OpenTags) isIntent() {}
+
+//jjui:bind scope=revisions action=open_set_tag
+type OpenSetTag struct{}
+
+func (OpenSetTag) isIntent() {}
+
+//jjui:bind scope=tags action=move_up set=Delta:-1
+//jjui:bind scope=tags action=move_down set=Delta:1
+type TagsNavigate struct {
+	Delta int
+}
+
+func (TagsNavigate) isIntent() {}
+
+//jjui:bind scope=tags action=move
+type TagsMove struct{}
+
+func (TagsMove) isIntent() {}
+
+//jjui:bind scope=tags action=delete
+type TagsDelete struct{}
+
+func (TagsDelete) isIntent() {}
@@ -113,6 +113,11 @@ type BookmarksCycleRemotes struct {
 
 func (BookmarksCycleRemotes) isIntent() {}
 
+//jjui:bind scope=bookmarks action=cycle_sync_filter
+type BookmarksCycleSyncFilter struct{}
+
+func (BookmarksCycleSyncFilter) isIntent() {}
+
 //jjui:bind scope=bookmarks action=filter
 type BookmarksOpenFilter struct{}
 
@@ -180,6 +185,39 @@ type GitApplyShortcut struct {
 
 func (GitApplyShortcut) isIntent() {}
 
+//jjui:bind scope=git action=remotes
+type GitOpenRemotes struct{}
+
+func (GitOpenRemotes) isIntent() {}
+
+//jjui:bind scope=git.remotes action=move_up set=Delta:-1
+//jjui:bind scope=git.remotes action=move_down set=Delta:1
+type GitRemotesNavigate struct {
+	Delta int
+}
+
+func (GitRemotesNavigate) isIntent() {}
+
+//jjui:bind scope=git.remotes action=add
+type GitRemotesAdd struct{}
+
+func (GitRemotesAdd) isIntent() {}
+
+//jjui:bind scope=git.remotes action=remove
+type GitRemotesRemove struct{}
+
+func (GitRemotesRemove) isIntent() {}
+
+//jjui:bind scope=git.remotes action=rename
+type GitRemotesRename struct{}
+
+func (GitRemotesRename) isIntent() {}
+
+//jjui:bind scope=git.remotes action=set_url
+type GitRemotesSetUrl struct{}
+
+func (GitRemotesSetUrl) isIntent() {}
+
 //jjui:bind scope=choose action=move_up set=Delta:-1
 //jjui:bind scope=choose action=move_down set=Delta:1
 type ChooseNavigate struct {
@@ -202,6 +240,7 @@ func (ChooseCancel) isIntent() {}
 //jjui:bind scope=revisions.squash action=cancel
 //jjui:bind scope=revisions.revert action=cancel
 //jjui:bind scope=revisions.duplicate action=cancel
+//jjui:bind scope=revisions.compare action=cancel
 //jjui:bind scope=revisions action=cancel
 //jjui:bind scope=revisions.details.confirmation action=cancel
 //jjui:bind scope=revisions.evolog action=cancel
@@ -209,6 +248,7 @@ func (ChooseCancel) isIntent() {}
 //jjui:bind scope=revisions.absorb action=cancel
 //jjui:bind scope=revisions.set_parents action=cancel
 //jjui:bind scope=revisions.set_bookmark action=cancel
+//jjui:bind scope=revisions.set_tag action=cancel
 //jjui:bind scope=revisions.inline_describe action=cancel
 //jjui:bind scope=revisions.ace_jump action=cancel
 //jjui:bind scope=ui action=cancel
@@ -223,6 +263,31 @@ func (ChooseCancel) isIntent() {}
 //jjui:bind scope=input action=cancel
 //jjui:bind scope=undo action=cancel
 //jjui:bind scope=redo action=cancel
+//jjui:bind scope=diff_editor action=cancel
+//jjui:bind scope=merge_tool action=cancel
+//jjui:bind scope=merge_tool.edit action=cancel
+//jjui:bind scope=annotate action=cancel
+//jjui:bind scope=file_history action=cancel
+//jjui:bind scope=rebase_plan action=cancel
+//jjui:bind scope=rebase_plan.reword action=cancel
+//jjui:bind scope=explode action=cancel
+//jjui:bind scope=explode.template action=cancel
+//jjui:bind scope=workspaces action=cancel
+//jjui:bind scope=workspaces.input action=cancel
+//jjui:bind scope=sparse action=cancel
+//jjui:bind scope=sparse.picker action=cancel
+//jjui:bind scope=tags action=cancel
+//jjui:bind scope=git.remotes action=cancel
+//jjui:bind scope=git.remotes.input action=cancel
+//jjui:bind scope=git.push_preview action=cancel
+//jjui:bind scope=git.remotes.confirmation action=cancel
+//jjui:bind scope=workspaces.confirmation action=cancel
+//jjui:bind scope=progress action=cancel
+//jjui:bind scope=conflicts action=cancel
+//jjui:bind scope=divergence action=cancel
+//jjui:bind scope=compare_files action=cancel
+//jjui:bind scope=op_diff action=cancel
+//jjui:bind scope=oplog.filter action=cancel
 type Cancel struct{}
 
 func (Cancel) isIntent() {}
@@ -235,6 +300,7 @@ func (Cancel) isIntent() {}
 //jjui:bind scope=revisions.revert action=force_apply set=Force:true
 //jjui:bind scope=revisions.duplicate action=apply set=Force:$bool(force)
 //jjui:bind scope=revisions.duplicate action=force_apply set=Force:true
+//jjui:bind scope=revisions.compare action=apply
 //jjui:bind scope=revisions.details.confirmation action=apply set=Force:$bool(force)
 //jjui:bind scope=revisions.details.confirmation action=force_apply set=Force:true
 //jjui:bind scope=revisions.evolog action=apply set=Force:$bool(force)
@@ -243,6 +309,7 @@ func (Cancel) isIntent() {}
 //jjui:bind scope=revisions.absorb action=apply
 //jjui:bind scope=revisions.set_parents action=apply
 //jjui:bind scope=revisions.set_bookmark action=apply
+//jjui:bind scope=revisions.set_tag action=apply
 //jjui:bind scope=revisions.ace_jump action=apply
 //jjui:bind scope=bookmarks action=apply
 //jjui:bind scope=git action=apply
@@ -257,6 +324,24 @@ func (Cancel) isIntent() {}
 //jjui:bind scope=help action=apply
 //jjui:bind scope=undo action=apply
 //jjui:bind scope=redo action=apply
+//jjui:bind scope=diff_editor action=apply
+//jjui:bind scope=merge_tool action=apply
+//jjui:bind scope=rebase_plan action=apply
+//jjui:bind scope=explode action=apply
+//jjui:bind scope=explode.template action=apply
+//jjui:bind scope=workspaces action=apply
+//jjui:bind scope=workspaces.input action=apply
+//jjui:bind scope=sparse action=apply
+//jjui:bind scope=sparse.picker action=apply
+//jjui:bind scope=sparse.picker action=force_apply set=Force:true
+//jjui:bind scope=tags action=apply
+//jjui:bind scope=git.remotes.input action=apply
+//jjui:bind scope=git.push_preview action=apply
+//jjui:bind scope=git.remotes.confirmation action=apply
+//jjui:bind scope=workspaces.confirmation action=apply
+//jjui:bind scope=conflicts action=apply
+//jjui:bind scope=op_diff action=apply
+//jjui:bind scope=oplog.filter action=apply
 type Apply struct {
 	Value string
 	Force bool
@@ -270,6 +355,12 @@ func (Apply) isIntent() {}
 //jjui:bind scope=redo action=next set=Delta:1
 //jjui:bind scope=revisions.details.confirmation action=prev set=Delta:-1
 //jjui:bind scope=revisions.details.confirmation action=next set=Delta:1
+//jjui:bind scope=git.push_preview action=prev set=Delta:-1
+//jjui:bind scope=git.push_preview action=next set=Delta:1
+//jjui:bind scope=git.remotes.confirmation action=prev set=Delta:-1
+//jjui:bind scope=git.remotes.confirmation action=next set=Delta:1
+//jjui:bind scope=workspaces.confirmation action=prev set=Delta:-1
+//jjui:bind scope=workspaces.confirmation action=next set=Delta:1
 type OptionSelect struct {
 	Delta int
 }
@@ -0,0 +1,39 @@
+package intents
+
+//jjui:bind scope=ui action=open_workspaces
+type OpenWorkspaces struct{}
+
+func (OpenWorkspaces) isIntent() {}
+
+//jjui:bind scope=workspaces action=move_up set=Delta:-1
+//jjui:bind scope=workspaces action=move_down set=Delta:1
+type WorkspacesNavigate struct {
+	Delta int
+}
+
+func (WorkspacesNavigate) isIntent() {}
+
+//jjui:bind scope=workspaces action=add
+type WorkspacesAdd struct{}
+
+func (WorkspacesAdd) isIntent() {}
+
+//jjui:bind scope=workspaces action=rename
+type WorkspacesRename struct{}
+
+func (WorkspacesRename) isIntent() {}
+
+//jjui:bind scope=workspaces action=forget
+type WorkspacesForget struct{}
+
+func (WorkspacesForget) isIntent() {}
+
+//jjui:bind scope=workspaces action=update_stale
+type WorkspacesUpdateStale struct{}
+
+func (WorkspacesUpdateStale) isIntent() {}
+
+//jjui:bind scope=workspaces action=check_stale
+type WorkspacesCheckStale struct{}
+
+func (WorkspacesCheckStale) isIntent() {}
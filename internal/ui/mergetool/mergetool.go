@@ -0,0 +1,484 @@
+package mergetool
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/textdiff"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+type conflict struct {
+	chunk  int
+	side   intents.MergeToolSide
+	edited []string
+}
+
+func (c *conflict) resolved() bool {
+	return c.side != intents.MergeToolSideNone
+}
+
+// lines returns the resolution of the conflict, or nil if it is unresolved.
+func (c *conflict) lines(chunk textdiff.MergeChunk) []string {
+	switch c.side {
+	case intents.MergeToolSideLeft:
+		return chunk.Left
+	case intents.MergeToolSideRight:
+		return chunk.Right
+	case intents.MergeToolSideBase:
+		return chunk.Base
+	case intents.MergeToolSideBoth:
+		return append(append([]string(nil), chunk.Left...), chunk.Right...)
+	case sideEdited:
+		return c.edited
+	}
+	return nil
+}
+
+// sideEdited marks a conflict whose resolution was typed in by the user
+const sideEdited intents.MergeToolSide = "edited"
+
+type resultLine struct {
+	text     string
+	conflict int
+	marker   bool
+}
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ dispatch.ScopeProvider = (*Model)(nil)
+
+// Model resolves the conflicts of a three-way merge and writes the result to
+// the output file. It implements the merge tool protocol of `jj resolve`.
+type Model struct {
+	output    string
+	chunks    []textdiff.MergeChunk
+	conflicts []*conflict
+	current   int
+	scroll    int
+	editing   bool
+	input     textarea.Model
+	message   string
+	applied   bool
+	err       error
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	if m.editing {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeMergeToolEdit,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeMergeTool,
+			Leak:    dispatch.LeakNone,
+			Handler: m,
+		},
+	}
+}
+
+// Applied reports whether the merged result was written to the output file.
+func (m *Model) Applied() bool {
+	return m.applied
+}
+
+// Err returns the error encountered while writing the output, if any.
+func (m *Model) Err() error {
+	return m.err
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.editing {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.MergeToolNavigate:
+		if len(m.conflicts) > 0 {
+			m.current = max(0, min(m.current+intent.Delta, len(m.conflicts)-1))
+		}
+		m.scroll = 0
+		return nil, true
+	case intents.MergeToolScroll:
+		m.scroll += intent.Delta
+		return nil, true
+	case intents.MergeToolPick:
+		if c := m.currentConflict(); c != nil {
+			c.side = intent.Side
+			m.message = ""
+			if c.resolved() {
+				m.scroll = 0
+				m.advance()
+			}
+		}
+		return nil, true
+	case intents.MergeToolEdit:
+		c := m.currentConflict()
+		if c == nil {
+			return nil, true
+		}
+		m.editing = true
+		m.input.SetValue(strings.TrimSuffix(strings.Join(m.conflictText(c), ""), "\n"))
+		return m.input.Focus(), true
+	case intents.MergeToolEditAccept:
+		if c := m.currentConflict(); c != nil {
+			value := m.input.Value()
+			c.edited = nil
+			if value != "" {
+				c.edited = textdiff.SplitLines(value + "\n")
+			}
+			c.side = sideEdited
+		}
+		m.editing = false
+		m.input.Blur()
+		return nil, true
+	case intents.Cancel:
+		if m.editing {
+			m.editing = false
+			m.input.Blur()
+			return nil, true
+		}
+		return tea.Quit, true
+	case intents.Apply:
+		if unresolved := m.unresolved(); unresolved > 0 {
+			m.message = fmt.Sprintf("%d conflicts are still unresolved", unresolved)
+			return nil, true
+		}
+		if err := os.WriteFile(m.output, []byte(m.mergedContent()), 0o644); err != nil {
+			m.err = err
+			return tea.Quit, true
+		}
+		m.applied = true
+		return tea.Quit, true
+	}
+	return nil, false
+}
+
+func (m *Model) currentConflict() *conflict {
+	if m.current < 0 || m.current >= len(m.conflicts) {
+		return nil
+	}
+	return m.conflicts[m.current]
+}
+
+// advance moves to the next unresolved conflict, if there is one
+func (m *Model) advance() {
+	for i := 1; i < len(m.conflicts); i++ {
+		next := (m.current + i) % len(m.conflicts)
+		if !m.conflicts[next].resolved() {
+			m.current = next
+			return
+		}
+	}
+}
+
+func (m *Model) unresolved() int {
+	count := 0
+	for _, c := range m.conflicts {
+		if !c.resolved() {
+			count++
+		}
+	}
+	return count
+}
+
+// conflictText is what the user starts editing with: the current resolution,
+// or the conflict with markers if it is not resolved yet.
+func (m *Model) conflictText(c *conflict) []string {
+	if c.resolved() {
+		return c.lines(m.chunks[c.chunk])
+	}
+	var lines []string
+	for _, l := range m.conflictLines(c) {
+		lines = append(lines, l.text)
+	}
+	return lines
+}
+
+func (m *Model) conflictLines(c *conflict) []resultLine {
+	chunk := m.chunks[c.chunk]
+	index := m.conflictIndex(c)
+	var lines []resultLine
+	add := func(text string, marker bool) {
+		lines = append(lines, resultLine{text: ensureNewline(text), conflict: index, marker: marker})
+	}
+	add("<<<<<<< left", true)
+	for _, l := range chunk.Left {
+		add(l, false)
+	}
+	add("||||||| base", true)
+	for _, l := range chunk.Base {
+		add(l, false)
+	}
+	add("=======", true)
+	for _, l := range chunk.Right {
+		add(l, false)
+	}
+	add(">>>>>>> right", true)
+	return lines
+}
+
+func (m *Model) conflictIndex(c *conflict) int {
+	for i, other := range m.conflicts {
+		if other == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func ensureNewline(line string) string {
+	if strings.HasSuffix(line, "\n") {
+		return line
+	}
+	return line + "\n"
+}
+
+func (m *Model) resultLines() []resultLine {
+	var lines []resultLine
+	conflictIndex := 0
+	for i, chunk := range m.chunks {
+		if !chunk.Conflict {
+			for _, l := range chunk.Lines {
+				lines = append(lines, resultLine{text: l, conflict: -1})
+			}
+			continue
+		}
+		c := m.conflicts[conflictIndex]
+		if c.chunk != i {
+			continue
+		}
+		if c.resolved() {
+			for _, l := range c.lines(chunk) {
+				lines = append(lines, resultLine{text: l, conflict: conflictIndex})
+			}
+		} else {
+			lines = append(lines, m.conflictLines(c)...)
+		}
+		conflictIndex++
+	}
+	return lines
+}
+
+func (m *Model) mergedContent() string {
+	var b strings.Builder
+	for _, l := range m.resultLines() {
+		b.WriteString(l.text)
+	}
+	return b.String()
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	titleBox, rest := box.CutTop(1)
+	m.renderTitle(dl, titleBox)
+
+	rows := rest.V(layout.Percent(40), layout.Fixed(1), layout.Fill(1))
+	if len(rows) < 3 {
+		return
+	}
+	m.renderSides(dl, rows[0])
+
+	headerStyle := common.DefaultPalette.Get("merge_tool header")
+	dl.AddFill(rows[1].R, ' ', headerStyle, 0)
+	dl.Text(rows[1].R.Min.X, rows[1].R.Min.Y, 0).Styled(" result ", headerStyle).Done()
+
+	if m.editing {
+		input := m.input
+		input.SetWidth(rows[2].R.Dx())
+		input.SetHeight(rows[2].R.Dy())
+		dl.AddDraw(rows[2].R, input.View(), 0)
+		return
+	}
+	m.renderResult(dl, rows[2])
+}
+
+func (m *Model) renderTitle(dl *render.DisplayContext, box layout.Box) {
+	titleStyle := common.DefaultPalette.Get("merge_tool title")
+	dimmedStyle := common.DefaultPalette.Get("merge_tool dimmed")
+	errorStyle := common.DefaultPalette.Get("merge_tool error")
+
+	tb := dl.Text(box.R.Min.X, box.R.Min.Y, 0).Styled("merge tool", titleStyle).Space(1)
+	if len(m.conflicts) == 0 {
+		tb.Styled("no conflicts", dimmedStyle)
+	} else {
+		tb.Styled(fmt.Sprintf("conflict %d of %d, %d unresolved", m.current+1, len(m.conflicts), m.unresolved()), dimmedStyle)
+	}
+	if m.message != "" {
+		tb.Space(1).Styled(m.message, errorStyle)
+	}
+	tb.Done()
+}
+
+func (m *Model) renderSides(dl *render.DisplayContext, box layout.Box) {
+	columns := box.H(layout.Fill(1), layout.Fill(1), layout.Fill(1))
+	if len(columns) < 3 {
+		return
+	}
+	c := m.currentConflict()
+	var chunk textdiff.MergeChunk
+	if c != nil {
+		chunk = m.chunks[c.chunk]
+	}
+
+	removed := removedFromBase(chunk)
+	m.renderSide(dl, columns[0], "base", chunk.Base, func(i int) bool { return removed[i] }, "merge_tool removed")
+	m.renderSide(dl, columns[1], "left", chunk.Left, addedTo(chunk.Base, chunk.Left), "merge_tool added")
+	m.renderSide(dl, columns[2], "right", chunk.Right, addedTo(chunk.Base, chunk.Right), "merge_tool added")
+}
+
+// renderSide draws the lines of one side of the current conflict, scrolled
+// along with the result so long hunks can be read to their end
+func (m *Model) renderSide(dl *render.DisplayContext, box layout.Box, title string, lines []string, changed func(int) bool, changedStyleKey string) {
+	headerStyle := common.DefaultPalette.Get("merge_tool header")
+	textStyle := common.DefaultPalette.Get("merge_tool text")
+	changedStyle := common.DefaultPalette.Get(changedStyleKey)
+
+	headerBox, content := box.CutTop(1)
+	dl.AddFill(headerBox.R, ' ', headerStyle, 0)
+	dl.Text(headerBox.R.Min.X, headerBox.R.Min.Y, 0).Styled(" "+title+" ", headerStyle).Done()
+
+	width := max(content.R.Dx()-1, 0)
+	start := render.ClampStartLine(m.scroll, content.R.Dy(), len(lines))
+	for y := 0; y < content.R.Dy() && start+y < len(lines); y++ {
+		i := start + y
+		style := textStyle
+		if changed(i) {
+			style = changedStyle
+		}
+		rect := layout.Rect(content.R.Min.X, content.R.Min.Y+y, width, 1)
+		dl.AddDraw(rect, style.Render(truncate(lines[i], width)), 0)
+	}
+}
+
+func (m *Model) renderResult(dl *render.DisplayContext, box layout.Box) {
+	textStyle := common.DefaultPalette.Get("merge_tool text")
+	conflictStyle := common.DefaultPalette.Get("merge_tool conflict")
+	resolvedStyle := common.DefaultPalette.Get("merge_tool resolved")
+	selectedStyle := common.DefaultPalette.Get("merge_tool selected")
+
+	lines := m.resultLines()
+	// keep the current conflict in view, a few lines below the top
+	start := 0
+	for i, l := range lines {
+		if l.conflict == m.current {
+			start = max(0, i-3)
+			break
+		}
+	}
+	clamped := render.ClampStartLine(start+m.scroll, box.R.Dy(), len(lines))
+	m.scroll = clamped - start
+	start = clamped
+
+	width := box.R.Dx()
+	for y := 0; y < box.R.Dy() && start+y < len(lines); y++ {
+		l := lines[start+y]
+		style := textStyle
+		switch {
+		case l.marker:
+			style = conflictStyle
+		case l.conflict >= 0 && m.conflicts[l.conflict].resolved():
+			style = resolvedStyle
+		}
+		rect := layout.Rect(box.R.Min.X, box.R.Min.Y+y, width, 1)
+		dl.AddDraw(rect, style.Render(truncate(l.text, width)), 0)
+		if l.conflict == m.current && l.conflict >= 0 {
+			dl.AddHighlight(rect, selectedStyle, 1)
+		}
+	}
+}
+
+func truncate(line string, width int) string {
+	line = strings.TrimRight(line, "\r\n")
+	return ansi.Truncate(render.ExpandTabs(line), width, "")
+}
+
+func addedTo(base, side []string) func(int) bool {
+	added := make(map[int]bool)
+	for _, op := range textdiff.Diff(base, side) {
+		if op.Kind == textdiff.Insert {
+			added[op.NewIndex] = true
+		}
+	}
+	return func(i int) bool { return added[i] }
+}
+
+func removedFromBase(chunk textdiff.MergeChunk) map[int]bool {
+	removed := make(map[int]bool)
+	for _, side := range [][]string{chunk.Left, chunk.Right} {
+		for _, op := range textdiff.Diff(chunk.Base, side) {
+			if op.Kind == textdiff.Delete {
+				removed[op.OldIndex] = true
+			}
+		}
+	}
+	return removed
+}
+
+func newModel(base, left, right, output string) *Model {
+	chunks := textdiff.Merge3(textdiff.SplitLines(base), textdiff.SplitLines(left), textdiff.SplitLines(right))
+	var conflicts []*conflict
+	for i, chunk := range chunks {
+		if chunk.Conflict {
+			conflicts = append(conflicts, &conflict{chunk: i, side: intents.MergeToolSideNone})
+		}
+	}
+
+	input := textarea.New()
+	input.CharLimit = 0
+	input.Prompt = ""
+	input.ShowLineNumbers = false
+	styles := input.Styles()
+	styles.Focused.Base = lipgloss.NewStyle()
+	styles.Focused.CursorLine = styles.Focused.Base
+	input.SetStyles(styles)
+
+	return &Model{
+		output:    output,
+		chunks:    chunks,
+		conflicts: conflicts,
+		input:     input,
+	}
+}
+
+// New reads the three sides of a conflicted file. The merged result is
+// written to output once every conflict is resolved.
+func New(basePath, leftPath, rightPath, output string) (*Model, error) {
+	var contents [3]string
+	for i, path := range []string{basePath, leftPath, rightPath} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = string(content)
+	}
+	return newModel(contents[0], contents[1], contents[2], output), nil
+}
@@ -0,0 +1,115 @@
+package mergetool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	base  = "one\ntwo\nthree\n"
+	left  = "one\nleft\nthree\n"
+	right = "one\nright\nthree\n"
+)
+
+func newTestModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	output := filepath.Join(t.TempDir(), "output")
+	return newModel(base, left, right, output), output
+}
+
+func readOutput(t *testing.T, output string) string {
+	t.Helper()
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestNew_RendersSidesOfCurrentConflict(t *testing.T) {
+	model, _ := newTestModel(t)
+
+	rendered := test.Stripped(test.RenderImmediate(model, 90, 20))
+	assert.Contains(t, rendered, "conflict 1 of 1, 1 unresolved")
+	assert.Contains(t, rendered, "left")
+	assert.Contains(t, rendered, "right")
+	assert.Contains(t, rendered, "<<<<<<< left")
+}
+
+func TestScroll_MovesSidesAlongWithResult(t *testing.T) {
+	var long strings.Builder
+	for i := range 30 {
+		fmt.Fprintf(&long, "left %d\n", i)
+	}
+	model := newModel(base, long.String(), right, filepath.Join(t.TempDir(), "output"))
+
+	// only the side panes are drawn above the result header
+	sides := func() string {
+		rendered := test.Stripped(test.RenderImmediate(model, 90, 30))
+		before, _, _ := strings.Cut(rendered, "\nresult")
+		return before
+	}
+	assert.Contains(t, sides(), "left 0")
+	assert.NotContains(t, sides(), "left 29")
+
+	model.HandleIntent(intents.MergeToolScroll{Delta: 25})
+	assert.Contains(t, sides(), "left 29")
+}
+
+func TestApply_WritesPickedSide(t *testing.T) {
+	model, output := newTestModel(t)
+
+	model.Update(intents.MergeToolPick{Side: intents.MergeToolSideRight})
+	model.Update(intents.Apply{})
+
+	assert.True(t, model.Applied())
+	assert.Equal(t, "one\nright\nthree\n", readOutput(t, output))
+}
+
+func TestApply_TakeBothKeepsLeftThenRight(t *testing.T) {
+	model, output := newTestModel(t)
+
+	model.Update(intents.MergeToolPick{Side: intents.MergeToolSideBoth})
+	model.Update(intents.Apply{})
+
+	assert.Equal(t, "one\nleft\nright\nthree\n", readOutput(t, output))
+}
+
+func TestApply_RefusesWhileConflictsRemain(t *testing.T) {
+	model, output := newTestModel(t)
+
+	model.Update(intents.Apply{})
+
+	assert.False(t, model.Applied())
+	assert.NoFileExists(t, output)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 90, 20)), "1 conflicts are still unresolved")
+}
+
+func TestEdit_AcceptedTextBecomesResolution(t *testing.T) {
+	model, output := newTestModel(t)
+
+	model.Update(intents.MergeToolEdit{})
+	assert.True(t, model.editing)
+	model.input.SetValue("merged")
+	model.Update(intents.MergeToolEditAccept{})
+	model.Update(intents.Apply{})
+
+	assert.False(t, model.editing)
+	assert.Equal(t, "one\nmerged\nthree\n", readOutput(t, output))
+}
+
+func TestCancel_LeavesOutputUntouched(t *testing.T) {
+	model, output := newTestModel(t)
+
+	model.Update(intents.MergeToolPick{Side: intents.MergeToolSideLeft})
+	model.Update(intents.Cancel{})
+
+	assert.False(t, model.Applied())
+	assert.NoFileExists(t, output)
+}
@@ -0,0 +1,258 @@
+package opdiff
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+type loadedMsg struct {
+	entries []jj.OpDiffEntry
+	err     error
+}
+
+type rowClickMsg struct {
+	Index int
+}
+
+type rowScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m rowScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// row is a line of the list, either the heading of a section or, when entry
+// is not negative, one of the entries under it
+type row struct {
+	section string
+	entry   int
+}
+
+// Model lists the commits and refs that changed between two operations.
+// Applying an entry jumps to its revision in the graph.
+type Model struct {
+	context             *context.MainContext
+	from                string
+	to                  string
+	entries             []jj.OpDiffEntry
+	rows                []row
+	loaded              bool
+	err                 error
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeOpDiff,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.OpDiff(m.from, m.to, false))
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	return loadedMsg{entries: jj.ParseOpDiff(string(output))}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.err = msg.err
+		m.entries = msg.entries
+		m.loaded = true
+		m.rows = nil
+		for i, entry := range m.entries {
+			if i == 0 || m.entries[i-1].Section != entry.Section {
+				m.rows = append(m.rows, row{section: entry.Section, entry: -1})
+			}
+			m.rows = append(m.rows, row{section: entry.Section, entry: i})
+		}
+		// start on the first entry rather than on a heading
+		m.cursor = min(1, max(len(m.rows)-1, 0))
+	case rowClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.rows) {
+			m.cursor = msg.Index
+		}
+	case rowScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.OpDiffNavigate:
+		if len(m.rows) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.rows)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.OpDiffShowDiff:
+		args := jj.OpDiff(m.from, m.to, true)
+		return func() tea.Msg {
+			output, _ := m.context.RunCommandImmediate(args)
+			return intents.DiffShow{Content: string(output)}
+		}, true
+	case intents.Apply:
+		entry := m.current()
+		if entry == nil || entry.Absent() {
+			return nil, true
+		}
+		return intents.Invoke(intents.JumpToRevision{ChangeID: entry.ChangeId, FallbackID: entry.CommitId}), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+// current returns the entry under the cursor, nil when on a heading
+func (m *Model) current() *jj.OpDiffEntry {
+	if m.cursor < 0 || m.cursor >= len(m.rows) || m.rows[m.cursor].entry < 0 {
+		return nil
+	}
+	return &m.entries[m.rows[m.cursor].entry]
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("op_diff text")
+	dimmedStyle := common.DefaultPalette.Get("op_diff dimmed")
+	idStyle := common.DefaultPalette.Get("op_diff id")
+	borderStyle := common.DefaultPalette.GetBorder("op_diff border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("operation diff ", common.DefaultPalette.Get("op_diff title")).
+		Styled("from ", dimmedStyle).
+		Styled(m.from, idStyle).
+		Styled(" to ", dimmedStyle).
+		Styled(m.to, idStyle).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.rows) == 0:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("no changes"), render.ZMenuContent)
+		return
+	}
+	m.renderRows(dl, contentBox)
+}
+
+func (m *Model) renderRows(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.rows))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.rows),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			r := m.rows[index]
+			textStyle := common.DefaultPalette.Get("op_diff text")
+			dimmedStyle := common.DefaultPalette.Get("op_diff dimmed")
+			idStyle := common.DefaultPalette.Get("op_diff id")
+			signStyle := common.DefaultPalette.Get("op_diff added")
+			if r.entry >= 0 && !m.entries[r.entry].Added {
+				signStyle = common.DefaultPalette.Get("op_diff removed")
+			}
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("op_diff selected")
+				textStyle = textStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				idStyle = idStyle.Inherit(selected)
+				signStyle = signStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent)
+			if r.entry < 0 {
+				tb.Styled(r.section, common.DefaultPalette.Get("op_diff title").Inherit(dimmedStyle)).Done()
+				return
+			}
+			entry := m.entries[r.entry]
+			sign := "+ "
+			if !entry.Added {
+				sign = "- "
+			}
+			tb.Styled("  "+sign, signStyle)
+			width := 4
+			if entry.Ref != "" {
+				tb.Styled(entry.Ref+" ", textStyle)
+				width += ansi.StringWidth(entry.Ref) + 1
+			}
+			if !entry.Absent() {
+				ids := entry.ChangeId + " " + entry.CommitId + " "
+				tb.Styled(ids, idStyle)
+				width += ansi.StringWidth(ids)
+			}
+			tb.Styled(ansi.Truncate(entry.Description, max(rect.Dx()-width, 0), "…"), dimmedStyle).Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return rowClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+func NewModel(c *context.MainContext, from string, to string) *Model {
+	m := &Model{
+		context:      c,
+		from:         from,
+		to:           to,
+		listRenderer: render.NewListRenderer(rowScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
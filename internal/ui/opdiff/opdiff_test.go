@@ -0,0 +1,58 @@
+package opdiff
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const opDiffOutput = `From operation: 8f4a4c2bd2d8 (2026-10-16 09:12:01) snapshot working copy
+  To operation: 0c9e1b7a3f21 (2026-10-16 09:15:44) rebase commit 4b3a0c1d
+
+Changed commits:
++ qpvuntsm 4b3a0c1d fix the parser
+- qpvuntsm/1 2d3e4f5a (hidden) fix the parser
+
+Changed local bookmarks:
+main:
++ qpvuntsm 4b3a0c1d fix the parser
+- (absent)
+`
+
+func TestLoad_ListsChangedCommitsAndBookmarks(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpDiff("8f4a4c2bd2d8", "0c9e1b7a3f21", false)).SetOutput([]byte(opDiffOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "8f4a4c2bd2d8", "0c9e1b7a3f21")
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "operation diff from 8f4a4c2bd2d8 to 0c9e1b7a3f21")
+	assert.Contains(t, rendered, "Changed commits")
+	assert.Contains(t, rendered, "+ qpvuntsm 4b3a0c1d fix the parser")
+	assert.Contains(t, rendered, "Changed local bookmarks")
+	assert.Contains(t, rendered, "+ main qpvuntsm 4b3a0c1d fix the parser")
+	assert.Contains(t, rendered, "- main (absent)")
+}
+
+func TestApply_ClosesAndJumpsToRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpDiff("8f4a4c2bd2d8", "0c9e1b7a3f21", false)).SetOutput([]byte(opDiffOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "8f4a4c2bd2d8", "0c9e1b7a3f21")
+	test.SimulateModel(model, model.Init())
+
+	var msgs []tea.Msg
+	test.SimulateModel(model, model.Update(intents.Apply{}), func(msg tea.Msg) {
+		msgs = append(msgs, msg)
+	})
+	require.Len(t, msgs, 1)
+	assert.Equal(t, intents.JumpToRevision{ChangeID: "qpvuntsm", FallbackID: "4b3a0c1d"}, msgs[0])
+}
@@ -0,0 +1,123 @@
+package compare
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/target_picker"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ operations.Operation = (*Operation)(nil)
+var _ operations.TracksSelectedRevision = (*Operation)(nil)
+var _ common.Focusable = (*Operation)(nil)
+var _ dispatch.ScopeProvider = (*Operation)(nil)
+
+// Operation picks the base To is compared against, either the revision
+// under the cursor or a bookmark or tag from the target picker
+type Operation struct {
+	context    *context.MainContext
+	To         *jj.Commit
+	Base       *jj.Commit
+	targetName string
+}
+
+func (o *Operation) IsFocused() bool {
+	return true
+}
+
+func (o *Operation) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeCompare,
+			Leak:    dispatch.LeakAll,
+			Handler: o,
+		},
+	}
+}
+
+func (o *Operation) Init() tea.Cmd {
+	return nil
+}
+
+func (o *Operation) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case target_picker.TargetSelectedMsg:
+		o.targetName = strings.TrimSpace(msg.Target)
+		cmd, _ := o.HandleIntent(intents.Apply{Force: msg.Force})
+		return cmd
+	case intents.Intent:
+		cmd, _ := o.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (o *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent.(type) {
+	case intents.CompareOpenTargetPicker:
+		return common.OpenTargetPicker(), true
+	case intents.Apply:
+		base := o.baseArg()
+		if base == "" {
+			return nil, true
+		}
+		if base == o.To.GetChangeId() {
+			return intents.Invoke(intents.AddMessage{Text: "move to another revision or pick a base to compare with"}), true
+		}
+		return tea.Sequence(common.Close, intents.Invoke(intents.ShowCompare{From: base, To: o.To.GetChangeId()})), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (o *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
+	o.Base = commit
+	return nil
+}
+
+func (o *Operation) baseArg() string {
+	if o.targetName != "" {
+		return o.targetName
+	}
+	if o.Base != nil {
+		return o.Base.GetChangeId()
+	}
+	return ""
+}
+
+func (o *Operation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	if pos != operations.RenderBeforeChangeId {
+		return ""
+	}
+	changeId := commit.GetChangeId()
+	if o.To != nil && o.To.GetChangeId() == changeId {
+		return common.DefaultPalette.Get("compare source_marker").Render("<< to >>")
+	}
+	if o.Base != nil && o.Base.GetChangeId() == changeId {
+		return common.DefaultPalette.Get("compare target_marker").Render("<< from >>")
+	}
+	return ""
+}
+
+func (o *Operation) Name() string {
+	return "compare"
+}
+
+func (o *Operation) ViewRect(_ *render.DisplayContext, _ layout.Box) {}
+
+func NewOperation(context *context.MainContext, to *jj.Commit) *Operation {
+	return &Operation{
+		context: context,
+		To:      to,
+	}
+}
@@ -3,9 +3,8 @@ package details
 import (
 	"bufio"
 	"fmt"
-	"path"
+	"os"
 	"reflect"
-	"regexp"
 	"slices"
 	"strings"
 
@@ -271,6 +270,20 @@ func (s *Operation) handleIntentInner(intent intents.Intent) (tea.Cmd, bool) {
 		)
 		s.confirmation = model
 		return s.confirmation.Init(), true
+	case intents.DetailsResolve:
+		current := s.current()
+		if current == nil {
+			return nil, true
+		}
+		if !current.conflict {
+			return intents.Invoke(intents.AddMessage{Text: "file has no conflicts"}), true
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err}), true
+		}
+		args := jj.Resolve(s.revision.GetChangeId(), current.fileName, jj.BuiltinMergeTool, jj.BuiltinMergeToolConfig(exe)...)
+		return s.context.RunInteractiveCommand(args, common.Refresh), true
 	case intents.DetailsAbsorb:
 		selectedFiles := s.getSelectedFiles(true)
 		s.selectedHint = "might get absorbed into parents"
@@ -311,6 +324,25 @@ func (s *Operation) handleIntentInner(intent intents.Intent) (tea.Cmd, bool) {
 			return tea.Batch(common.Close, common.UpdateRevSet(fmt.Sprintf("files(%s)", jj.EscapeFileName(current.fileName)))), true
 		}
 		return nil, true
+	case intents.DetailsAnnotate:
+		if current := s.current(); current != nil {
+			return intents.Invoke(intents.OpenAnnotate{ChangeId: s.revision.GetChangeId(), File: current.fileName}), true
+		}
+		return nil, true
+	case intents.DetailsFileHistory:
+		if current := s.current(); current != nil {
+			return intents.Invoke(intents.OpenFileHistory{File: current.fileName}), true
+		}
+		return nil, true
+	case intents.DetailsExplode:
+		if len(s.files) == 0 {
+			return nil, true
+		}
+		var files []string
+		for _, f := range s.files {
+			files = append(files, f.fileName)
+		}
+		return intents.Invoke(intents.OpenExplode{Revision: s.revision, Files: files, Grouped: s.getSelectedFiles(false)}), true
 	case intents.DetailsSelectFile:
 		for i := range s.files {
 			if s.files[i].fileName == intent.File {
@@ -476,9 +508,8 @@ func (s *Operation) createListItems(content string, selectedFiles []string) []*i
 		fileName := file[2:]
 
 		actualFileName := fileName
-		if (status == Renamed || status == Copied) && strings.Contains(actualFileName, "{") {
-			re := regexp.MustCompile(`\{[^}]*? => \s*([^}]*?)\s*\}`)
-			actualFileName = path.Clean(re.ReplaceAllString(actualFileName, "$1"))
+		if status == Renamed || status == Copied {
+			actualFileName = jj.RenameTarget(actualFileName)
 		}
 		items = append(items, &item{
 			status:   status,
@@ -13,6 +13,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/predict"
 	"github.com/idursun/jjui/internal/ui/operations/target_picker"
 	"github.com/idursun/jjui/internal/ui/render"
 )
@@ -28,6 +29,7 @@ var (
 var _ operations.Operation = (*Operation)(nil)
 var _ common.Focusable = (*Operation)(nil)
 var _ dispatch.ScopeProvider = (*Operation)(nil)
+var _ operations.PredictsGraph = (*Operation)(nil)
 
 type Operation struct {
 	context     *appContext.MainContext
@@ -36,6 +38,7 @@ type Operation struct {
 	To          *jj.Commit
 	Target      intents.ModeTarget
 	targetName  string
+	preview     *predict.Preview
 }
 
 func (r *Operation) IsFocused() bool {
@@ -53,10 +56,14 @@ func (r *Operation) Scopes() []dispatch.Scope {
 }
 
 func (r *Operation) Init() tea.Cmd {
-	return nil
+	return r.preview.Load()
 }
 
 func (r *Operation) Update(msg tea.Msg) tea.Cmd {
+	if r.preview.Update(msg) {
+		r.predict()
+		return nil
+	}
 	switch msg := msg.(type) {
 	case target_picker.TargetSelectedMsg:
 		r.targetName = strings.TrimSpace(msg.Target)
@@ -78,6 +85,7 @@ func (r *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		if r.Target == intents.ModeTargetInsert {
 			r.InsertStart = r.To
 		}
+		r.predict()
 		return nil, true
 	case intents.DuplicateOpenTargetPicker:
 		return common.OpenTargetPicker(), true
@@ -97,6 +105,7 @@ func (r *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 
 func (r *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
 	r.To = commit
+	r.predict()
 	return nil
 }
 
@@ -177,6 +186,21 @@ func (r *Operation) Name() string {
 
 func (r *Operation) ViewRect(_ *render.DisplayContext, _ layout.Box) {}
 
+func (r *Operation) RenderPredictedGraph(dl *render.DisplayContext, box layout.Box) {
+	r.preview.ViewRect(dl, box)
+}
+
+// predict updates the predicted graph for the current target
+func (r *Operation) predict() {
+	placement, ok := predict.NewPlacement(r.Target, r.To, r.InsertStart)
+	if !ok {
+		return
+	}
+	r.preview.Predict(func(g *predict.Graph) (*predict.Graph, error) {
+		return predict.Duplicate(g, r.From.GetIds(), placement)
+	})
+}
+
 func (r *Operation) targetArg() string {
 	if strings.TrimSpace(r.targetName) != "" {
 		return r.targetName
@@ -192,5 +216,6 @@ func NewOperation(context *appContext.MainContext, from jj.SelectedRevisions, ta
 		context: context,
 		From:    from,
 		Target:  target,
+		preview: predict.NewPreview(context, "after duplicate"),
 	}
 }
@@ -57,6 +57,9 @@ type Operation struct {
 	cursor           int
 	target           *jj.Commit
 	ensureCursorView bool
+	// marked is the index of the entry the selected one is compared with, or
+	// -1 when nothing is marked
+	marked int
 }
 
 func (o *Operation) Len() int {
@@ -117,6 +120,7 @@ func (o *Operation) Update(msg tea.Msg) tea.Cmd {
 	case updateEvologMsg:
 		o.rows = msg.rows
 		o.cursor = 0
+		o.marked = -1
 		o.ensureCursorView = true
 		return o.updateSelection()
 	case EvologClickedMsg:
@@ -159,6 +163,32 @@ func (o *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 			output, _ := o.context.RunCommandImmediate(jj.Diff(selectedCommitId, ""))
 			return intents.DiffShow{Content: string(output)}
 		}, true
+	case intents.EvologMark:
+		if o.mode != selectMode || o.Len() == 0 {
+			return nil, true
+		}
+		if o.marked == o.cursor {
+			o.marked = -1
+		} else {
+			o.marked = o.cursor
+		}
+		return nil, true
+	case intents.EvologInterdiff:
+		if o.mode != selectMode || o.Len() == 0 {
+			return nil, true
+		}
+		if o.marked < 0 || o.marked == o.cursor {
+			return intents.Invoke(intents.AddMessage{Text: "mark an entry and move to another one to compare them"}), true
+		}
+		from := o.rows[o.marked].Commit.CommitId
+		to := o.getSelectedEvolog().CommitId
+		return func() tea.Msg {
+			output, err := o.context.RunCommandImmediate(jj.Interdiff(from, to))
+			if err != nil {
+				return intents.AddMessage{Text: err.Error(), Err: err}
+			}
+			return intents.DiffShow{Content: string(output)}
+		}, true
 	case intents.EvologRestore:
 		if o.mode != selectMode {
 			return nil, true
@@ -292,6 +322,7 @@ func NewOperation(context *context.MainContext, revision *jj.Commit) *Operation
 		revision:   revision,
 		rows:       nil,
 		cursor:     0,
+		marked:     -1,
 		dlRenderer: render.NewListRenderer(EvologScrollMsg{}),
 	}
 	return o
@@ -309,6 +340,7 @@ func (o *Operation) renderListToDisplayContext(
 	}
 	textStyle := common.DefaultPalette.Get("evolog text")
 	selectedStyle := common.DefaultPalette.Get("evolog selected")
+	markerStyle := common.DefaultPalette.Get("evolog source_marker")
 
 	totalHeight := 0
 	for _, row := range o.rows {
@@ -347,6 +379,15 @@ func (o *Operation) renderListToDisplayContext(
 			}
 			y++
 		}
+
+		if index == o.marked {
+			marker := markerStyle.Render("<< from >>")
+			markerWidth := lipgloss.Width(marker)
+			if markerWidth < itemRect.Dx() {
+				markerRect := layout.Rect(itemRect.Max.X-markerWidth, itemRect.Min.Y, markerWidth, 1)
+				dl.AddDraw(markerRect, marker, 2)
+			}
+		}
 	}
 
 	clickMsg := func(index int, _ tea.Mouse) render.ClickMessage {
@@ -119,3 +119,52 @@ func TestOperation_RestoreMode_CancelClosesEvolog(t *testing.T) {
 	_, ok := msg.(common.CloseViewMsg)
 	assert.True(t, ok, "cancel in restore mode should close evolog")
 }
+
+func TestOperation_Interdiff_ComparesMarkedWithSelected(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.Interdiff("111", "333")).SetOutput([]byte("interdiff output"))
+	defer commandRunner.Verify()
+
+	context := test.NewTestContext(commandRunner)
+	operation := NewOperation(context, revision)
+	operation.Update(updateEvologMsg{
+		rows: []parser.Row{
+			{Commit: &jj.Commit{ChangeId: "abc", CommitId: "111"}},
+			{Commit: &jj.Commit{ChangeId: "abc", CommitId: "222"}},
+			{Commit: &jj.Commit{ChangeId: "abc", CommitId: "333"}},
+		},
+	})
+
+	operation.Update(intents.EvologMark{})
+	assert.Equal(t, 0, operation.marked)
+	operation.Update(intents.EvologNavigate{Delta: 2})
+
+	cmd := operation.Update(intents.EvologInterdiff{})
+	require.NotNil(t, cmd)
+	diff, ok := cmd().(intents.DiffShow)
+	require.True(t, ok)
+	assert.Equal(t, "interdiff output", diff.Content)
+}
+
+func TestOperation_Interdiff_RequiresMarkedEntry(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	context := test.NewTestContext(commandRunner)
+	operation := NewOperation(context, revision)
+	operation.Update(updateEvologMsg{
+		rows: []parser.Row{
+			{Commit: &jj.Commit{ChangeId: "abc", CommitId: "111"}},
+			{Commit: &jj.Commit{ChangeId: "abc", CommitId: "222"}},
+		},
+	})
+
+	operation.Update(intents.EvologMark{})
+	operation.Update(intents.EvologMark{})
+	assert.Equal(t, -1, operation.marked, "marking the marked entry again unmarks it")
+
+	cmd := operation.Update(intents.EvologInterdiff{})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(intents.AddMessage)
+	assert.True(t, ok)
+}
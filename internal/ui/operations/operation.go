@@ -7,6 +7,8 @@ import (
 	"github.com/idursun/jjui/internal/parser"
 	"github.com/idursun/jjui/internal/screen"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
 )
 
 type RenderPosition int
@@ -43,3 +45,9 @@ type CheckedItemsSynchronizer interface {
 type SegmentRenderer interface {
 	RenderSegment(currentStyle lipgloss.Style, segment *screen.Segment, row parser.Row) string
 }
+
+// PredictsGraph is implemented by operations drawing the graph as it would
+// look after they are applied
+type PredictsGraph interface {
+	RenderPredictedGraph(dl *render.DisplayContext, box layout.Box)
+}
@@ -0,0 +1,454 @@
+package predict
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// State tells how a node of a predicted graph relates to the current graph
+type State int
+
+const (
+	StateUnchanged State = iota
+	StateMoved
+	StateCreated
+)
+
+// Source mirrors the way rebase picks the revisions to move
+type Source int
+
+const (
+	SourceRevisions Source = iota
+	SourceBranch
+	SourceDescendants
+)
+
+var (
+	errCycle         = errors.New("the revisions would become their own ancestors")
+	errTargetMissing = errors.New("the target is not in the current revset")
+)
+
+// Node is a revision of the graph. Parents are the keys of its parents that
+// are part of the graph.
+type Node struct {
+	ChangeId    string
+	Description string
+	Parents     []string
+	State       State
+	key         string
+	rank        float64
+}
+
+// Graph is the revisions of a revset in the order jj log lists them
+type Graph struct {
+	nodes []*Node
+	byKey map[string]*Node
+}
+
+// Placement is where revisions are put, mirroring the target flags of jj.
+// InsertAfter is only used by intents.ModeTargetInsert, where To is the
+// revision inserted before.
+type Placement struct {
+	Target      intents.ModeTarget
+	To          string
+	InsertAfter string
+}
+
+// NewPlacement returns the placement of an operation targeting to, with
+// insertAfter being the other end of intents.ModeTargetInsert. It returns
+// false if there is no target yet.
+func NewPlacement(target intents.ModeTarget, to *jj.Commit, insertAfter *jj.Commit) (Placement, bool) {
+	if to == nil || (target == intents.ModeTargetInsert && insertAfter == nil) {
+		return Placement{}, false
+	}
+	placement := Placement{Target: target, To: to.GetChangeId()}
+	if target == intents.ModeTargetInsert {
+		placement.InsertAfter = insertAfter.GetChangeId()
+	}
+	return placement, true
+}
+
+// Parse parses the output of jj.GraphLog. Parents outside the revset are
+// dropped.
+func Parse(output string) *Graph {
+	g := &Graph{byKey: make(map[string]*Node)}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		node := &Node{
+			ChangeId:    fields[0],
+			Description: fields[2],
+			key:         fields[0],
+			rank:        float64(len(g.nodes)),
+		}
+		if fields[1] != "" {
+			node.Parents = strings.Split(fields[1], ",")
+		}
+		g.add(node)
+	}
+	for _, node := range g.nodes {
+		node.Parents = slices.DeleteFunc(node.Parents, func(parent string) bool {
+			return g.byKey[parent] == nil
+		})
+	}
+	return g
+}
+
+func (g *Graph) add(node *Node) {
+	g.nodes = append(g.nodes, node)
+	g.byKey[node.key] = node
+}
+
+func (g *Graph) clone() *Graph {
+	c := &Graph{byKey: make(map[string]*Node, len(g.nodes))}
+	for _, node := range g.nodes {
+		copied := *node
+		copied.Parents = slices.Clone(node.Parents)
+		c.add(&copied)
+	}
+	return c
+}
+
+func (g *Graph) children(key string) []*Node {
+	var children []*Node
+	for _, node := range g.nodes {
+		if slices.Contains(node.Parents, key) {
+			children = append(children, node)
+		}
+	}
+	return children
+}
+
+// descendants returns the keys and all their descendants
+func (g *Graph) descendants(keys []string) map[string]bool {
+	set := make(map[string]bool)
+	// nodes are listed children first, so walking backwards visits parents
+	// before their children
+	for i := len(g.nodes) - 1; i >= 0; i-- {
+		node := g.nodes[i]
+		if slices.Contains(keys, node.key) || slices.ContainsFunc(node.Parents, func(p string) bool { return set[p] }) {
+			set[node.key] = true
+		}
+	}
+	return set
+}
+
+// ancestors returns the keys and all their ancestors
+func (g *Graph) ancestors(keys []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, node := range g.nodes {
+		if slices.Contains(keys, node.key) || set[node.key] {
+			set[node.key] = true
+			for _, parent := range node.Parents {
+				set[parent] = true
+			}
+		}
+	}
+	return set
+}
+
+// ends returns the nodes of the set without parents in the set (roots) and
+// the ones without children in the set (heads), in graph order
+func (g *Graph) ends(set map[string]bool) (roots []string, heads []string) {
+	for _, node := range g.nodes {
+		if !set[node.key] {
+			continue
+		}
+		if !slices.ContainsFunc(node.Parents, func(p string) bool { return set[p] }) {
+			roots = append(roots, node.key)
+		}
+		if !slices.ContainsFunc(g.children(node.key), func(c *Node) bool { return set[c.key] }) {
+			heads = append(heads, node.key)
+		}
+	}
+	return roots, heads
+}
+
+// anchors returns the revisions the placement puts new parents next to
+func (p Placement) anchors() []string {
+	if p.Target == intents.ModeTargetInsert {
+		return []string{p.To, p.InsertAfter}
+	}
+	return []string{p.To}
+}
+
+// attach makes roots children of the placement target and, for the insert
+// targets, moves the children of the target on top of heads. Revisions in
+// moving are never moved on top of heads as they are the ones being placed.
+func (g *Graph) attach(p Placement, roots []string, heads []string, moving map[string]bool) error {
+	for _, anchor := range p.anchors() {
+		if g.byKey[anchor] == nil {
+			return errTargetMissing
+		}
+	}
+	to := g.byKey[p.To]
+	var parents []string
+	switch p.Target {
+	case intents.ModeTargetBefore:
+		parents = slices.Clone(to.Parents)
+		to.Parents = slices.Clone(heads)
+	case intents.ModeTargetInsert:
+		parents = []string{p.InsertAfter}
+		to.Parents = replace(to.Parents, p.InsertAfter, heads)
+	case intents.ModeTargetAfter:
+		parents = []string{p.To}
+		for _, child := range g.children(p.To) {
+			if !moving[child.key] {
+				child.Parents = replace(child.Parents, p.To, heads)
+			}
+		}
+	default:
+		parents = []string{p.To}
+	}
+	for _, root := range roots {
+		g.byKey[root].Parents = slices.Clone(parents)
+	}
+	return nil
+}
+
+// replace replaces old in parents with the given ones, or adds them if old is
+// not one of the parents
+func replace(parents []string, old string, with []string) []string {
+	var ret []string
+	for _, parent := range parents {
+		if parent != old {
+			ret = append(ret, parent)
+		}
+	}
+	for _, parent := range with {
+		if !slices.Contains(ret, parent) {
+			ret = append(ret, parent)
+		}
+	}
+	return ret
+}
+
+// Rebase predicts the graph after rebasing revisions with the given source
+// flag to the placement
+func Rebase(g *Graph, revisions []string, source Source, p Placement) (*Graph, error) {
+	g = g.clone()
+	set := make(map[string]bool)
+	switch source {
+	case SourceRevisions:
+		for _, revision := range revisions {
+			if g.byKey[revision] != nil {
+				set[revision] = true
+			}
+		}
+	case SourceDescendants:
+		set = g.descendants(revisions)
+	case SourceBranch:
+		destination := p.To
+		if p.Target == intents.ModeTargetInsert {
+			destination = p.InsertAfter
+		}
+		excluded := g.ancestors([]string{destination})
+		if p.Target == intents.ModeTargetBefore {
+			excluded = g.ancestors(g.byKey[p.To].parentKeys())
+		}
+		branch := make(map[string]bool)
+		for key := range g.ancestors(revisions) {
+			if !excluded[key] {
+				branch[key] = true
+			}
+		}
+		roots, _ := g.ends(branch)
+		set = g.descendants(roots)
+	}
+	if len(set) == 0 {
+		return g, nil
+	}
+	for _, anchor := range p.anchors() {
+		if set[anchor] {
+			return nil, errCycle
+		}
+	}
+
+	if source == SourceRevisions {
+		// children of the moved revisions are left on their parents, and the
+		// moved revisions only keep the parents that move along with them
+		parents := make(map[string][]string)
+		for _, node := range g.nodes {
+			if set[node.key] {
+				parents[node.key] = slices.DeleteFunc(slices.Clone(node.Parents), func(p string) bool { return !set[p] })
+				continue
+			}
+			for _, parent := range node.Parents {
+				for _, resolved := range g.resolve(parent, set) {
+					if !slices.Contains(parents[node.key], resolved) {
+						parents[node.key] = append(parents[node.key], resolved)
+					}
+				}
+			}
+		}
+		for _, node := range g.nodes {
+			node.Parents = parents[node.key]
+		}
+	}
+	roots, heads := g.ends(set)
+	if err := g.attach(p, roots, heads, set); err != nil {
+		return nil, err
+	}
+	for key := range set {
+		g.byKey[key].State = StateMoved
+	}
+	return g, nil
+}
+
+func (n *Node) parentKeys() []string {
+	if n == nil {
+		return nil
+	}
+	return n.Parents
+}
+
+// resolve returns the closest ancestors of key outside the set
+func (g *Graph) resolve(key string, set map[string]bool) []string {
+	if !set[key] {
+		return []string{key}
+	}
+	var ret []string
+	for _, parent := range g.byKey[key].Parents {
+		ret = append(ret, g.resolve(parent, set)...)
+	}
+	return ret
+}
+
+// Duplicate predicts the graph after duplicating revisions to the placement
+func Duplicate(g *Graph, revisions []string, p Placement) (*Graph, error) {
+	g = g.clone()
+	set := make(map[string]bool)
+	for _, revision := range revisions {
+		if g.byKey[revision] != nil {
+			set[revision] = true
+		}
+	}
+	if len(set) == 0 {
+		return g, nil
+	}
+	roots, heads := g.ends(set)
+	copyKey := func(key string) string { return "duplicate:" + key }
+	// parents come after their children, so copies are created bottom up
+	for i := len(g.nodes) - 1; i >= 0; i-- {
+		node := g.nodes[i]
+		if !set[node.key] {
+			continue
+		}
+		copied := &Node{
+			ChangeId:    node.ChangeId,
+			Description: node.Description,
+			State:       StateCreated,
+			key:         copyKey(node.key),
+		}
+		for _, parent := range node.Parents {
+			if set[parent] {
+				copied.Parents = append(copied.Parents, copyKey(parent))
+			}
+		}
+		g.add(copied)
+	}
+	for i := range roots {
+		roots[i] = copyKey(roots[i])
+	}
+	for i := range heads {
+		heads[i] = copyKey(heads[i])
+	}
+	if err := g.attach(p, roots, heads, nil); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Revert predicts the graph after reverting revisions to the placement. The
+// reverting revisions are stacked on top of each other, the newest revision
+// being reverted first.
+func Revert(g *Graph, revisions []string, p Placement) (*Graph, error) {
+	g = g.clone()
+	var chain []string
+	for _, node := range slices.Clone(g.nodes) {
+		if !slices.Contains(revisions, node.key) {
+			continue
+		}
+		reverted := &Node{
+			Description: `Revert "` + node.Description + `"`,
+			State:       StateCreated,
+			key:         "revert:" + node.key,
+		}
+		if len(chain) > 0 {
+			reverted.Parents = []string{chain[len(chain)-1]}
+		}
+		g.add(reverted)
+		chain = append(chain, reverted.key)
+	}
+	if len(chain) == 0 {
+		return g, nil
+	}
+	if err := g.attach(p, chain[:1], chain[len(chain)-1:], nil); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Sorted returns the nodes with children before their parents. Among the
+// nodes that can come next, the one listed earliest in the current graph is
+// picked, so unchanged parts of the graph keep their order. New nodes are
+// listed right above their parents.
+func (g *Graph) Sorted() []*Node {
+	for _, node := range g.nodes {
+		if node.State == StateCreated {
+			node.rank = -1
+		}
+	}
+	var rankOf func(node *Node) float64
+	rankOf = func(node *Node) float64 {
+		if node.rank >= 0 {
+			return node.rank
+		}
+		node.rank = float64(len(g.nodes))
+		for _, parent := range node.Parents {
+			node.rank = min(node.rank, rankOf(g.byKey[parent])-0.5)
+		}
+		return node.rank
+	}
+	pending := make(map[string]int)
+	for _, node := range g.nodes {
+		rankOf(node)
+		for _, parent := range node.Parents {
+			pending[parent]++
+		}
+	}
+
+	var sorted []*Node
+	var ready []*Node
+	for _, node := range g.nodes {
+		if pending[node.key] == 0 {
+			ready = append(ready, node)
+		}
+	}
+	for len(ready) > 0 {
+		next := slices.MinFunc(ready, func(a, b *Node) int {
+			switch {
+			case a.rank < b.rank:
+				return -1
+			case a.rank > b.rank:
+				return 1
+			}
+			return 0
+		})
+		ready = slices.DeleteFunc(ready, func(n *Node) bool { return n == next })
+		sorted = append(sorted, next)
+		for _, parent := range next.Parents {
+			pending[parent]--
+			if pending[parent] == 0 {
+				ready = append(ready, g.byKey[parent])
+			}
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,144 @@
+package predict
+
+import (
+	"slices"
+	"strings"
+)
+
+// line is a line of a drawn graph. node is nil for the lines connecting
+// lanes, otherwise column is the lane it is drawn in.
+type line struct {
+	graph  string
+	node   *Node
+	column int
+}
+
+// drawLanes draws the sorted nodes one lane per pending parent. A node takes
+// the leftmost lane waiting for it, other lanes waiting for it are merged
+// into that one, and a lane is opened for each of its parents after the
+// first one. Lanes never move sideways, so a moved revision stays in the
+// lane it was drawn in.
+func drawLanes(nodes []*Node) []line {
+	var lanes []string
+	var lines []line
+	for _, node := range nodes {
+		var waiting []int
+		for i, key := range lanes {
+			if key == node.key {
+				waiting = append(waiting, i)
+			}
+		}
+		var column int
+		if len(waiting) > 0 {
+			column = waiting[0]
+		} else {
+			column = freeLane(&lanes)
+		}
+		if len(waiting) > 1 {
+			lines = append(lines, line{graph: connector(lanes, column, waiting[1:], true)})
+			for _, i := range waiting[1:] {
+				lanes[i] = ""
+			}
+		}
+
+		var b strings.Builder
+		for i, key := range lanes {
+			switch {
+			case i == column:
+				b.WriteString(glyphs[node.State])
+			case key != "":
+				b.WriteString("│")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(" ")
+		}
+		lines = append(lines, line{graph: b.String(), node: node, column: column})
+
+		lanes[column] = ""
+		var opened []int
+		for i, parent := range node.Parents {
+			if i == 0 {
+				lanes[column] = parent
+				continue
+			}
+			lane := freeLane(&lanes)
+			lanes[lane] = parent
+			opened = append(opened, lane)
+		}
+		if len(opened) > 0 {
+			lines = append(lines, line{graph: connector(lanes, column, opened, false)})
+		}
+		for len(lanes) > 0 && lanes[len(lanes)-1] == "" {
+			lanes = lanes[:len(lanes)-1]
+		}
+	}
+	return lines
+}
+
+var glyphs = map[State]string{
+	StateUnchanged: "○",
+	StateMoved:     "●",
+	StateCreated:   "◆",
+}
+
+// freeLane returns the leftmost unused lane, adding one if all are in use
+func freeLane(lanes *[]string) int {
+	if i := slices.Index(*lanes, ""); i >= 0 {
+		return i
+	}
+	*lanes = append(*lanes, "")
+	return len(*lanes) - 1
+}
+
+// connector draws the line joining the lanes in others to column, either
+// merging them into it (up) or branching them off it
+func connector(lanes []string, column int, others []int, merge bool) string {
+	left, right := column, column
+	for _, i := range others {
+		left, right = min(left, i), max(right, i)
+	}
+	var b strings.Builder
+	for i, key := range lanes {
+		switch {
+		case i == column && left < column && right > column:
+			b.WriteString("┼")
+		case i == column && right > column:
+			b.WriteString("├")
+		case i == column:
+			b.WriteString("┤")
+		case slices.Contains(others, i):
+			b.WriteString(connectorEnd(i == left, i == right, merge))
+		case i > left && i < right && key != "":
+			b.WriteString("┼")
+		case i > left && i < right:
+			b.WriteString("─")
+		case key != "":
+			b.WriteString("│")
+		default:
+			b.WriteString(" ")
+		}
+		if i >= left && i < right {
+			b.WriteString("─")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+func connectorEnd(leftmost bool, rightmost bool, merge bool) string {
+	switch {
+	case leftmost && merge:
+		return "╰"
+	case leftmost:
+		return "╭"
+	case rightmost && merge:
+		return "╯"
+	case rightmost:
+		return "╮"
+	case merge:
+		return "┴"
+	}
+	return "┬"
+}
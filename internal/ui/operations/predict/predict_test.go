@@ -0,0 +1,145 @@
+package predict
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// a linear stack a-b-c-d-e with x branching off b
+const graphLog = "e\td\tfifth\n" +
+	"d\tc\tfourth\n" +
+	"x\tb\tside\n" +
+	"c\tb\tthird\n" +
+	"b\ta\tsecond\n" +
+	"a\tzz\tfirst\n"
+
+func parents(t *testing.T, g *Graph) map[string]string {
+	t.Helper()
+	ret := make(map[string]string)
+	for _, node := range g.nodes {
+		ret[node.key] = strings.Join(node.Parents, ",")
+	}
+	return ret
+}
+
+func drawn(g *Graph) string {
+	var lines []string
+	for _, l := range drawLanes(g.Sorted()) {
+		text := strings.TrimRight(l.graph, " ")
+		if l.node != nil {
+			text += " " + l.node.key
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestParse_DropsParentsOutsideTheRevset(t *testing.T) {
+	g := Parse(graphLog)
+	assert.Len(t, g.nodes, 6)
+	assert.Empty(t, g.byKey["a"].Parents)
+	assert.Equal(t, "second", g.byKey["b"].Description)
+}
+
+func TestRebase_RevisionLeavesChildrenBehind(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"c"}, SourceRevisions, Placement{Target: intents.ModeTargetDestination, To: "e"})
+	assert.NoError(t, err)
+	assert.Equal(t, "e", parents(t, g)["c"])
+	assert.Equal(t, "b", parents(t, g)["d"])
+	assert.Equal(t, StateMoved, g.byKey["c"].State)
+	assert.Equal(t, StateUnchanged, g.byKey["d"].State)
+}
+
+func TestRebase_DescendantsMoveAlong(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"c"}, SourceDescendants, Placement{Target: intents.ModeTargetDestination, To: "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "x", parents(t, g)["c"])
+	assert.Equal(t, "c", parents(t, g)["d"])
+	assert.Equal(t, StateMoved, g.byKey["e"].State)
+}
+
+func TestRebase_BranchMovesEverythingNotInDestination(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"e"}, SourceBranch, Placement{Target: intents.ModeTargetDestination, To: "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "x", parents(t, g)["c"])
+	assert.Equal(t, StateUnchanged, g.byKey["b"].State)
+	assert.Equal(t, StateMoved, g.byKey["e"].State)
+}
+
+func TestRebase_InsertBefore(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"d"}, SourceRevisions, Placement{Target: intents.ModeTargetBefore, To: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"e": "c", "d": "b", "x": "b", "c": "d", "b": "a", "a": ""}, parents(t, g))
+	assert.Equal(t, "○ e\n│ ○ x\n○ │ c\n● │ d\n├─╯\n○ b\n○ a", drawn(g))
+}
+
+func TestRebase_InsertAfterMovesChildrenOnTop(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"e"}, SourceRevisions, Placement{Target: intents.ModeTargetAfter, To: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", parents(t, g)["e"])
+	assert.Equal(t, "e", parents(t, g)["c"])
+	assert.Equal(t, "e", parents(t, g)["x"])
+}
+
+func TestRebase_Insert(t *testing.T) {
+	g, err := Rebase(Parse(graphLog), []string{"x"}, SourceRevisions, Placement{Target: intents.ModeTargetInsert, To: "d", InsertAfter: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, "c", parents(t, g)["x"])
+	assert.Equal(t, "x", parents(t, g)["d"])
+}
+
+func TestRebase_OntoOwnDescendantIsACycle(t *testing.T) {
+	_, err := Rebase(Parse(graphLog), []string{"b"}, SourceDescendants, Placement{Target: intents.ModeTargetDestination, To: "d"})
+	assert.ErrorIs(t, err, errCycle)
+}
+
+func TestRebase_TargetOutsideTheRevset(t *testing.T) {
+	_, err := Rebase(Parse(graphLog), []string{"c"}, SourceRevisions, Placement{Target: intents.ModeTargetDestination, To: "unknown"})
+	assert.ErrorIs(t, err, errTargetMissing)
+}
+
+func TestDuplicate_CopiesOntoTarget(t *testing.T) {
+	g, err := Duplicate(Parse(graphLog), []string{"c", "d"}, Placement{Target: intents.ModeTargetDestination, To: "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "x", parents(t, g)["duplicate:c"])
+	assert.Equal(t, "duplicate:c", parents(t, g)["duplicate:d"])
+	assert.Equal(t, "c", parents(t, g)["d"])
+	assert.Equal(t, "○ e\n│ ◆ duplicate:d\n○ │ d\n│ ◆ duplicate:c\n│ ○ x\n○ │ c\n├─╯\n○ b\n○ a", drawn(g))
+}
+
+func TestRevert_StacksRevertsNewestFirst(t *testing.T) {
+	g, err := Revert(Parse(graphLog), []string{"c", "d"}, Placement{Target: intents.ModeTargetDestination, To: "e"})
+	assert.NoError(t, err)
+	assert.Equal(t, "e", parents(t, g)["revert:d"])
+	assert.Equal(t, "revert:d", parents(t, g)["revert:c"])
+	assert.Equal(t, `Revert "third"`, g.byKey["revert:c"].Description)
+}
+
+func TestDrawLanes_MergesAndBranches(t *testing.T) {
+	g := Parse("m\tc,x\tmerge\n" + graphLog)
+	assert.Equal(t, "○ m\n├─╮\n│ │ ○ e\n│ │ ○ d\n│ ○ │ x\n├─┼─╯\n○ │ c\n├─╯\n○ b\n○ a", drawn(g))
+}
+
+func TestPreview_DrawsPredictedGraph(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GraphLog("", 0)).SetOutput([]byte(graphLog))
+	defer commandRunner.Verify()
+
+	preview := NewPreview(test.NewTestContext(commandRunner), "after rebase")
+	assert.True(t, preview.Update(preview.Load()()))
+	preview.Predict(func(g *Graph) (*Graph, error) {
+		return Rebase(g, []string{"c"}, SourceRevisions, Placement{Target: intents.ModeTargetDestination, To: "e"})
+	})
+
+	rendered := test.Stripped(test.RenderImmediate(preview, 120, 20))
+	assert.Contains(t, rendered, "after rebase")
+	assert.Contains(t, rendered, "● c third")
+	assert.Contains(t, rendered, "○ e fifth")
+
+	assert.Empty(t, strings.TrimSpace(test.RenderImmediate(preview, 80, 20)), "too narrow to draw next to the revisions")
+}
@@ -0,0 +1,156 @@
+package predict
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// minWidth is the narrowest revisions view the preview is drawn next to
+const minWidth = 100
+
+type loadedMsg struct {
+	preview *Preview
+	graph   *Graph
+	err     error
+}
+
+// Preview draws the graph of the current revset as it would look after an
+// operation is applied. The graph is loaded once, and the operation calls
+// Predict whenever its source or target changes.
+type Preview struct {
+	context   *context.MainContext
+	title     string
+	graph     *Graph
+	predicted []line
+	err       error
+}
+
+func NewPreview(context *context.MainContext, title string) *Preview {
+	return &Preview{context: context, title: title}
+}
+
+// Load loads the graph of the current revset
+func (p *Preview) Load() tea.Cmd {
+	return func() tea.Msg {
+		output, err := p.context.RunCommandImmediate(jj.GraphLog(p.context.CurrentRevset, config.Current.Limit))
+		if err != nil {
+			return loadedMsg{preview: p, err: err}
+		}
+		return loadedMsg{preview: p, graph: Parse(string(output))}
+	}
+}
+
+// Update handles the loaded graph, returning true if msg was the one loaded
+// by this preview
+func (p *Preview) Update(msg tea.Msg) bool {
+	loaded, ok := msg.(loadedMsg)
+	if !ok || loaded.preview != p {
+		return false
+	}
+	p.graph = loaded.graph
+	p.err = loaded.err
+	return true
+}
+
+// Predict replaces the predicted graph with the one simulate returns from the
+// current graph
+func (p *Preview) Predict(simulate func(g *Graph) (*Graph, error)) {
+	if p.graph == nil {
+		return
+	}
+	predicted, err := simulate(p.graph)
+	p.err = err
+	p.predicted = nil
+	if err == nil {
+		p.predicted = drawLanes(predicted.Sorted())
+	}
+}
+
+// ViewRect draws the predicted graph on the right side of box, the revisions
+// view, unless it is too narrow to fit both.
+func (p *Preview) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	if box.R.Dx() < minWidth || box.R.Dy() <= 4 {
+		return
+	}
+	_, frame := box.CutRight(box.R.Dx() * 2 / 5)
+	textStyle := common.DefaultPalette.Get("predicted_graph text")
+	dimmedStyle := common.DefaultPalette.Get("predicted_graph dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("predicted_graph border", lipgloss.NormalBorder())
+
+	contentBox := frame.Inset(1)
+	dl.AddBackdrop(frame.R, render.ZPreview)
+	dl.AddFill(frame.R, ' ', textStyle, render.ZPreview)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZPreview)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZPreview+1).
+		Styled(p.title, common.DefaultPalette.Get("predicted_graph title")).
+		Done()
+
+	switch {
+	case p.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(p.err.Error())), render.ZPreview+1)
+		return
+	case p.predicted == nil:
+		dl.AddDraw(contentBox.R, dimmedStyle.Render("loading..."), render.ZPreview+1)
+		return
+	}
+
+	// keep the first changed revision in view
+	start := 0
+	for i, l := range p.predicted {
+		if l.node != nil && l.node.State != StateUnchanged {
+			start = max(i-contentBox.R.Dy()/3, 0)
+			break
+		}
+	}
+	start = render.ClampStartLine(start, contentBox.R.Dy(), len(p.predicted))
+
+	width := contentBox.R.Dx()
+	for i, l := range p.predicted[start:min(start+contentBox.R.Dy(), len(p.predicted))] {
+		text := dl.Text(contentBox.R.Min.X, contentBox.R.Min.Y+i, render.ZPreview+1)
+		if l.node == nil {
+			text.Styled(ansi.Truncate(l.graph, width, ""), dimmedStyle).Done()
+			continue
+		}
+		style := textStyle
+		switch l.node.State {
+		case StateMoved:
+			style = common.DefaultPalette.Get("predicted_graph moved")
+		case StateCreated:
+			style = common.DefaultPalette.Get("predicted_graph created")
+		}
+		// each lane is two cells wide, the node being in the first one
+		graph := []rune(l.graph)
+		before, node, after := string(graph[:l.column*2]), string(graph[l.column*2]), string(graph[l.column*2+1:])
+		description := l.node.Description
+		if description == "" {
+			description = "(no description set)"
+		}
+		remaining := width
+		for _, segment := range []struct {
+			text  string
+			style lipgloss.Style
+		}{
+			{before, dimmedStyle},
+			{node, style},
+			{after, dimmedStyle},
+			{l.node.ChangeId, common.DefaultPalette.Get("predicted_graph change_id")},
+			{" " + description, style},
+		} {
+			text.Styled(ansi.Truncate(segment.text, remaining, "…"), segment.style)
+			remaining = max(remaining-ansi.StringWidth(segment.text), 0)
+		}
+		text.Done()
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/predict"
 	"github.com/idursun/jjui/internal/ui/operations/target_picker"
 	"github.com/idursun/jjui/internal/ui/render"
 )
@@ -34,6 +35,11 @@ var (
 		SourceRevision:    "--revisions",
 		SourceDescendants: "--source",
 	}
+	sourceToPredict = map[Source]predict.Source{
+		SourceBranch:      predict.SourceBranch,
+		SourceRevision:    predict.SourceRevisions,
+		SourceDescendants: predict.SourceDescendants,
+	}
 	targetToFlags = map[intents.ModeTarget]string{
 		intents.ModeTargetAfter:       "--insert-after",
 		intents.ModeTargetBefore:      "--insert-before",
@@ -42,9 +48,10 @@ var (
 )
 
 var (
-	_ operations.Operation   = (*Operation)(nil)
-	_ common.Focusable       = (*Operation)(nil)
-	_ dispatch.ScopeProvider = (*Operation)(nil)
+	_ operations.Operation     = (*Operation)(nil)
+	_ common.Focusable         = (*Operation)(nil)
+	_ dispatch.ScopeProvider   = (*Operation)(nil)
+	_ operations.PredictsGraph = (*Operation)(nil)
 )
 
 type Operation struct {
@@ -57,6 +64,7 @@ type Operation struct {
 	targetName     string
 	highlightedIds []string
 	SkipEmptied    bool
+	preview        *predict.Preview
 }
 
 type updateHighlightedIdsMsg struct {
@@ -80,10 +88,14 @@ func (r *Operation) Scopes() []dispatch.Scope {
 }
 
 func (r *Operation) Init() tea.Cmd {
-	return nil
+	return r.preview.Load()
 }
 
 func (r *Operation) Update(msg tea.Msg) tea.Cmd {
+	if r.preview.Update(msg) {
+		r.predict()
+		return nil
+	}
 	switch msg := msg.(type) {
 	case target_picker.TargetSelectedMsg:
 		r.targetName = strings.TrimSpace(msg.Target)
@@ -105,12 +117,14 @@ func (r *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return common.StartAceJump(), true
 	case intents.RebaseSetSource:
 		r.Source = rebaseSourceFromIntent(msg.Source)
+		r.predict()
 		return nil, true
 	case intents.RebaseSetTarget:
 		r.Target = msg.Target
 		if r.Target == intents.ModeTargetInsert {
 			r.InsertStart = r.To
 		}
+		r.predict()
 		return nil, true
 	case intents.RebaseOpenTargetPicker:
 		return common.OpenTargetPicker(), true
@@ -148,6 +162,7 @@ func rebaseSourceFromIntent(source intents.RebaseSource) Source {
 
 func (r *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
 	r.To = commit
+	r.predict()
 	identifier := fmt.Sprintf("rebase-highlight-%p", r)
 
 	revset := ""
@@ -274,6 +289,22 @@ func (r *Operation) Name() string {
 
 func (r *Operation) ViewRect(_ *render.DisplayContext, _ layout.Box) {}
 
+func (r *Operation) RenderPredictedGraph(dl *render.DisplayContext, box layout.Box) {
+	r.preview.ViewRect(dl, box)
+}
+
+// predict updates the predicted graph for the current source and target
+func (r *Operation) predict() {
+	placement, ok := predict.NewPlacement(r.Target, r.To, r.InsertStart)
+	if !ok {
+		return
+	}
+	source := sourceToPredict[r.Source]
+	r.preview.Predict(func(g *predict.Graph) (*predict.Graph, error) {
+		return predict.Rebase(g, r.From.GetIds(), source, placement)
+	})
+}
+
 func (r *Operation) targetArg() string {
 	if strings.TrimSpace(r.targetName) != "" {
 		return r.targetName
@@ -290,5 +321,6 @@ func NewOperation(context *context.MainContext, from jj.SelectedRevisions, sourc
 		From:    from,
 		Source:  source,
 		Target:  target,
+		preview: predict.NewPreview(context, "after rebase"),
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/predict"
 	"github.com/idursun/jjui/internal/ui/operations/target_picker"
 	"github.com/idursun/jjui/internal/ui/render"
 )
@@ -29,6 +30,7 @@ var (
 var _ operations.Operation = (*Operation)(nil)
 var _ common.Focusable = (*Operation)(nil)
 var _ dispatch.ScopeProvider = (*Operation)(nil)
+var _ operations.PredictsGraph = (*Operation)(nil)
 
 type Operation struct {
 	context        *context.MainContext
@@ -38,6 +40,7 @@ type Operation struct {
 	Target         intents.ModeTarget
 	targetName     string
 	highlightedIds []string
+	preview        *predict.Preview
 }
 
 func (r *Operation) IsFocused() bool {
@@ -55,10 +58,14 @@ func (r *Operation) Scopes() []dispatch.Scope {
 }
 
 func (r *Operation) Init() tea.Cmd {
-	return nil
+	return r.preview.Load()
 }
 
 func (r *Operation) Update(msg tea.Msg) tea.Cmd {
+	if r.preview.Update(msg) {
+		r.predict()
+		return nil
+	}
 	switch msg := msg.(type) {
 	case target_picker.TargetSelectedMsg:
 		r.targetName = strings.TrimSpace(msg.Target)
@@ -80,6 +87,7 @@ func (r *Operation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		if r.Target == intents.ModeTargetInsert {
 			r.InsertStart = r.To
 		}
+		r.predict()
 		return nil, true
 	case intents.RevertOpenTargetPicker:
 		return common.OpenTargetPicker(), true
@@ -102,6 +110,7 @@ func (r *Operation) SetSelectedRevision(commit *jj.Commit) tea.Cmd {
 	r.highlightedIds = nil
 	r.To = commit
 	r.highlightedIds = r.From.GetIds()
+	r.predict()
 	return nil
 }
 
@@ -193,6 +202,21 @@ func (r *Operation) Name() string {
 
 func (r *Operation) ViewRect(_ *render.DisplayContext, _ layout.Box) {}
 
+func (r *Operation) RenderPredictedGraph(dl *render.DisplayContext, box layout.Box) {
+	r.preview.ViewRect(dl, box)
+}
+
+// predict updates the predicted graph for the current target
+func (r *Operation) predict() {
+	placement, ok := predict.NewPlacement(r.Target, r.To, r.InsertStart)
+	if !ok {
+		return
+	}
+	r.preview.Predict(func(g *predict.Graph) (*predict.Graph, error) {
+		return predict.Revert(g, r.From.GetIds(), placement)
+	})
+}
+
 func (r *Operation) targetArg() string {
 	if strings.TrimSpace(r.targetName) != "" {
 		return r.targetName
@@ -208,5 +232,6 @@ func NewOperation(context *context.MainContext, from jj.SelectedRevisions, targe
 		context: context,
 		From:    from,
 		Target:  target,
+		preview: predict.NewPreview(context, "after revert"),
 	}
 }
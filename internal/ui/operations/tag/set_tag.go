@@ -0,0 +1,170 @@
+package tag
+
+import (
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ operations.Operation = (*SetTagOperation)(nil)
+var _ common.Editable = (*SetTagOperation)(nil)
+var _ dispatch.ScopeProvider = (*SetTagOperation)(nil)
+
+// SetTagOperation creates a tag on a revision, or moves it there if a tag
+// with that name already exists
+type SetTagOperation struct {
+	context         *context.MainContext
+	revision        string
+	name            textinput.Model
+	tags            []string
+	suggestionIndex int
+}
+
+func (s *SetTagOperation) IsEditing() bool {
+	return true
+}
+
+func (s *SetTagOperation) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeSetTag,
+			Leak:    dispatch.LeakNone,
+			Handler: s,
+		},
+	}
+}
+
+func (s *SetTagOperation) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.Cancel:
+		return common.Close, true
+	case intents.Apply:
+		name := strings.TrimSpace(s.name.Value())
+		if name == "" {
+			return nil, true
+		}
+		exists := slices.Contains(s.tags, name)
+		return s.context.RunCommand(jj.TagSet(s.revision, name, exists), common.CloseApplied, common.Refresh), true
+	case intents.AutocompleteCycle:
+		s.cycleSuggestion(intent.Reverse)
+		return nil, true
+	}
+	return nil, false
+}
+
+func (s *SetTagOperation) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case intents.Intent:
+		cmd, _ := s.HandleIntent(msg)
+		return cmd
+	}
+	var cmd tea.Cmd
+	s.name, cmd = s.name.Update(msg)
+	s.name.SetValue(strings.ReplaceAll(s.name.Value(), " ", "-"))
+	s.suggestionIndex = -1
+	return cmd
+}
+
+func (s *SetTagOperation) Init() tea.Cmd {
+	if output, err := s.context.RunCommandImmediate(jj.TagListAll()); err == nil {
+		var tags []string
+		for _, t := range jj.ParseTagListOutput(string(output)) {
+			tags = append(tags, t.Name)
+		}
+		s.tags = tags
+		s.name.SetSuggestions(tags)
+	}
+	return textinput.Blink
+}
+
+func (s *SetTagOperation) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	content := s.viewContent()
+	w, h := lipgloss.Size(content)
+	rect := layout.Rect(box.R.Min.X, box.R.Min.Y, w, h)
+	dl.AddDraw(rect, content, 0)
+}
+
+func (s *SetTagOperation) IsFocused() bool {
+	return true
+}
+
+func (s *SetTagOperation) Render(commit *jj.Commit, pos operations.RenderPosition) string {
+	if pos != operations.RenderBeforeCommitId || commit.GetChangeId() != s.revision {
+		return ""
+	}
+	return s.viewContent() + s.name.Styles().Focused.Text.Render(" ")
+}
+
+func (s *SetTagOperation) Name() string {
+	return "set tag"
+}
+
+func NewSetTagOperation(context *context.MainContext, changeId string) *SetTagOperation {
+	t := textinput.New()
+	t.ShowSuggestions = true
+	t.CharLimit = 120
+	t.Prompt = "tag: "
+	t.Focus()
+
+	return &SetTagOperation{
+		name:     t,
+		revision: changeId,
+		context:  context,
+		// -1 means no active completion cycle.
+		suggestionIndex: -1,
+	}
+}
+
+func (s *SetTagOperation) viewContent() string {
+	dimmedStyle := common.DefaultPalette.Get("revisions dimmed").Inline(true)
+	textStyle := common.DefaultPalette.Get("revisions text").Inline(true)
+	styles := s.name.Styles()
+	styles.Focused.Text = textStyle
+	styles.Focused.Prompt = dimmedStyle
+	styles.Focused.Suggestion = dimmedStyle
+	styles.Focused.Placeholder = dimmedStyle
+	styles.Blurred.Text = textStyle
+	styles.Blurred.Prompt = dimmedStyle
+	styles.Blurred.Suggestion = dimmedStyle
+	styles.Blurred.Placeholder = dimmedStyle
+	s.name.SetStyles(styles)
+
+	return s.name.View()
+}
+
+func (s *SetTagOperation) cycleSuggestion(reverse bool) {
+	var candidates []string
+	needle := strings.TrimSpace(s.name.Value())
+	for _, tag := range s.tags {
+		if strings.HasPrefix(tag, needle) {
+			candidates = append(candidates, tag)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	switch {
+	case s.suggestionIndex < 0 && reverse:
+		s.suggestionIndex = len(candidates) - 1
+	case s.suggestionIndex < 0:
+		s.suggestionIndex = 0
+	case reverse:
+		s.suggestionIndex = (s.suggestionIndex - 1 + len(candidates)) % len(candidates)
+	default:
+		s.suggestionIndex = (s.suggestionIndex + 1) % len(candidates)
+	}
+	s.name.SetValue(candidates[s.suggestionIndex])
+	s.name.CursorEnd()
+}
@@ -0,0 +1,34 @@
+package tag
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+)
+
+func TestSetTagOperation_CreatesTag(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte("v1.0.0\t\tkxqpnrzs\t1a2b3c4d\trelease\n"))
+	commandRunner.Expect(jj.TagSet("revision", "v1.1.0", false))
+	defer commandRunner.Verify()
+
+	op := NewSetTagOperation(test.NewTestContext(commandRunner), "revision")
+	test.SimulateModel(op, op.Init())
+	test.SimulateModel(op, test.Type("v1.1.0"))
+	test.SimulateModel(op, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestSetTagOperation_MovesExistingTag(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte("v1.0.0\t\tkxqpnrzs\t1a2b3c4d\trelease\n"))
+	commandRunner.Expect(jj.TagSet("revision", "v1.0.0", true))
+	defer commandRunner.Verify()
+
+	op := NewSetTagOperation(test.NewTestContext(commandRunner), "revision")
+	test.SimulateModel(op, op.Init())
+	test.SimulateModel(op, func() tea.Msg { return intents.AutocompleteCycle{} })
+	test.SimulateModel(op, func() tea.Msg { return intents.Apply{} })
+}
@@ -0,0 +1,150 @@
+package oplog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/idursun/jjui/internal/jj"
+)
+
+type filterState int
+
+const (
+	filterOff filterState = iota
+	filterEditing
+	filterApplied
+)
+
+// filter narrows the operations down by the terms typed in the filter input:
+//
+//	user:NAME host:NAME cmd:KIND since:WHEN until:WHEN WORD
+//
+// Values of a term can be separated by commas to match any of them. WHEN is
+// either a date like 2026-10-16, a date and time like 2026-10-16T21:00 or a
+// duration back from now like 30m, 12h or 2d. Plain words are looked up in the
+// description.
+type filter struct {
+	users []string
+	hosts []string
+	kinds []string
+	words []string
+	since time.Time
+	until time.Time
+}
+
+func parseFilter(text string, now time.Time) (filter, error) {
+	var f filter
+	for term := range strings.FieldsSeq(text) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok {
+			f.words = append(f.words, strings.ToLower(term))
+			continue
+		}
+		values := strings.Split(strings.ToLower(value), ",")
+		var err error
+		switch strings.ToLower(key) {
+		case "user":
+			f.users = append(f.users, values...)
+		case "host":
+			f.hosts = append(f.hosts, values...)
+		case "cmd", "type":
+			f.kinds = append(f.kinds, values...)
+		case "since":
+			f.since, err = parseWhen(value, now)
+		case "until":
+			f.until, err = parseWhen(value, now)
+			if err == nil && isDate(value) {
+				// a bare date includes the whole day
+				f.until = f.until.AddDate(0, 0, 1)
+			}
+		default:
+			err = fmt.Errorf("unknown filter %q, expected one of user, host, cmd, since or until", key)
+		}
+		if err != nil {
+			return filter{}, err
+		}
+	}
+	return f, nil
+}
+
+func parseWhen(value string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{time.DateOnly, "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot read %q as a date or a duration", value)
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+func (f filter) isEmpty() bool {
+	return len(f.users) == 0 && len(f.hosts) == 0 && len(f.kinds) == 0 && len(f.words) == 0 &&
+		f.since.IsZero() && f.until.IsZero()
+}
+
+func (f filter) match(entry jj.OpLogEntry) bool {
+	if len(f.users) > 0 && !containsAny(entry.User, f.users) {
+		return false
+	}
+	if len(f.hosts) > 0 && !containsAny(entry.Host, f.hosts) {
+		return false
+	}
+	if len(f.kinds) > 0 && !matchesKind(entry.Kind(), f.kinds) {
+		return false
+	}
+	if !f.since.IsZero() && entry.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && entry.Time.After(f.until) {
+		return false
+	}
+	description := strings.ToLower(entry.Description)
+	for _, word := range f.words {
+		if !strings.Contains(description, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(value string, candidates []string) bool {
+	value = strings.ToLower(value)
+	for _, candidate := range candidates {
+		if strings.Contains(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesKind tells if the kind or any word of it, like "fetch" in
+// "git fetch", is one of the candidates
+func matchesKind(kind string, candidates []string) bool {
+	kind = strings.ToLower(kind)
+	words := strings.Fields(kind)
+	for _, candidate := range candidates {
+		if candidate == kind {
+			return true
+		}
+		for _, word := range words {
+			if candidate == word {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package oplog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+func TestParseFilter(t *testing.T) {
+	f, err := parseFilter("user:CI host:build-01,build-02 cmd:fetch since:12h until:2026-10-16 refs", now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci"}, f.users)
+	assert.Equal(t, []string{"build-01", "build-02"}, f.hosts)
+	assert.Equal(t, []string{"fetch"}, f.kinds)
+	assert.Equal(t, []string{"refs"}, f.words)
+	assert.Equal(t, time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC), f.since)
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), f.until)
+
+	f, err = parseFilter("since:2d until:2026-10-16T21:30", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC), f.since)
+	assert.Equal(t, time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), f.until)
+
+	_, err = parseFilter("since:yesterday", now)
+	assert.Error(t, err)
+	_, err = parseFilter("branch:main", now)
+	assert.Error(t, err)
+}
+
+func TestFilter_Match(t *testing.T) {
+	fetch := jj.OpLogEntry{
+		User:        "ci",
+		Host:        "build-01",
+		Time:        time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC),
+		Description: "fetch from git remote(s) origin",
+		Tags:        map[string]string{"args": "jj git fetch"},
+	}
+	snapshot := jj.OpLogEntry{
+		User:        "jane",
+		Host:        "laptop",
+		Time:        time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC),
+		Description: "snapshot working copy",
+	}
+
+	tests := []struct {
+		text     string
+		fetch    bool
+		snapshot bool
+	}{
+		{"", true, true},
+		{"user:ci", true, false},
+		{"host:laptop", false, true},
+		{"cmd:fetch", true, false},
+		{"type:git", true, false},
+		{"cmd:snapshot,fetch", true, true},
+		{"since:12h", true, false},
+		{"until:2026-10-16", false, true},
+		{"origin", true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			f, err := parseFilter(test.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, test.fetch, f.match(fetch))
+			assert.Equal(t, test.snapshot, f.match(snapshot))
+		})
+	}
+}
@@ -2,8 +2,12 @@ package oplog
 
 import (
 	"bytes"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/idursun/jjui/internal/config"
@@ -19,7 +23,10 @@ import (
 )
 
 type updateOpLogMsg struct {
-	Rows []row
+	Entries []jj.OpLogEntry
+	// Complete tells if the entries go back to the first operation rather
+	// than stopping at the op log limit
+	Complete bool
 }
 
 type OpLogClickedMsg struct {
@@ -35,15 +42,31 @@ func (o OpLogScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
 	return OpLogScrollMsg{Delta: delta, Horizontal: horizontal}
 }
 
-var _ common.ImmediateModel = (*Model)(nil)
+var (
+	_ common.ImmediateModel = (*Model)(nil)
+	_ common.Editable       = (*Model)(nil)
+)
 
 type Model struct {
 	context          *context.MainContext
 	listRenderer     *render.ListRenderer
+	entries          []jj.OpLogEntry
+	complete         bool
 	rows             []row
 	cursor           int
 	ensureCursorView bool
 	quickSearch      string
+	// marked is the id of the operation the selected one is compared with
+	marked      string
+	filterInput textinput.Model
+	filterState filterState
+	filter      filter
+	// expanded holds the snapshot runs shown one operation per row
+	expanded map[string]bool
+}
+
+func (m *Model) IsEditing() bool {
+	return m.filterState == filterEditing
 }
 
 func (m *Model) Len() int {
@@ -69,6 +92,20 @@ func (m *Model) HasQuickSearch() bool {
 }
 
 func (m *Model) Scopes() []dispatch.Scope {
+	if m.IsEditing() {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeOplogFilter,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+			{
+				Name:    actions.ScopeOplog,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
 	var ret []dispatch.Scope
 	if m.HasQuickSearch() {
 		ret = append(ret, dispatch.Scope{
@@ -86,7 +123,7 @@ func (m *Model) Scopes() []dispatch.Scope {
 }
 
 func (m *Model) Init() tea.Cmd {
-	return m.load()
+	return m.load(config.Current.OpLog.Limit)
 }
 
 func (m *Model) Scroll(delta int) tea.Cmd {
@@ -107,8 +144,23 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.SetCursor(m.search(0, false))
 		return m.updateSelection()
 	case updateOpLogMsg:
-		m.rows = msg.Rows
+		m.entries = msg.Entries
+		m.complete = m.complete || msg.Complete
+		m.rebuildRows()
 		return m.updateSelection()
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.filterState != filterEditing {
+			return nil
+		}
+		var cmd tea.Cmd
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		// narrow the list down while typing, keeping the last valid filter
+		if f, err := parseFilter(m.filterInput.Value(), time.Now()); err == nil {
+			m.filter = f
+			m.rebuildRows()
+			return tea.Batch(cmd, m.updateSelection(), m.loadAll())
+		}
+		return cmd
 	case OpLogClickedMsg:
 		if msg.Index >= 0 && msg.Index < len(m.rows) {
 			m.cursor = msg.Index
@@ -136,6 +188,35 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return m.restore(intent), true
 	case intents.OpLogRevert:
 		return m.revert(intent), true
+	case intents.OpLogMark:
+		if len(m.rows) == 0 {
+			return nil, true
+		}
+		if opId := m.rows[m.cursor].OperationId; !m.rows[m.cursor].contains(m.marked) {
+			m.marked = opId
+		} else {
+			m.marked = ""
+		}
+		return nil, true
+	case intents.OpLogCompare:
+		return m.compare(), true
+	case intents.OpLogToggleCollapse:
+		return m.toggleCollapse(), true
+	case intents.OpLogOpenFilter:
+		m.filterState = filterEditing
+		m.filterInput.Focus()
+		m.filterInput.CursorEnd()
+		return textinput.Blink, true
+	case intents.Apply:
+		if m.filterState != filterEditing {
+			return nil, false
+		}
+		return m.applyFilter(), true
+	case intents.Cancel:
+		if m.filterState != filterEditing {
+			return nil, false
+		}
+		return m.resetFilter(), true
 	case intents.QuickSearchCycle:
 		offset := 1
 		if intent.Reverse {
@@ -188,6 +269,62 @@ func (m *Model) updateSelection() tea.Cmd {
 	return m.context.SetSelectedItem(context.SelectedOperation{OperationId: m.rows[m.cursor].OperationId})
 }
 
+func (m *Model) rebuildRows() {
+	var selected string
+	if m.cursor < len(m.rows) {
+		selected = m.rows[m.cursor].OperationId
+	}
+	m.rows = buildRows(m.entries, m.filter, m.expanded)
+	m.cursor = max(slices.IndexFunc(m.rows, func(r row) bool { return r.contains(selected) }), 0)
+	m.ensureCursorView = true
+}
+
+// toggleCollapse expands the snapshot run under the cursor into one row per
+// operation, or folds it back into a single row
+func (m *Model) toggleCollapse() tea.Cmd {
+	if len(m.rows) == 0 || m.rows[m.cursor].run == "" {
+		return nil
+	}
+	if m.expanded == nil {
+		m.expanded = map[string]bool{}
+	}
+	run := m.rows[m.cursor].run
+	if m.expanded[run] {
+		delete(m.expanded, run)
+	} else {
+		m.expanded[run] = true
+	}
+	m.rebuildRows()
+	return m.updateSelection()
+}
+
+func (m *Model) applyFilter() tea.Cmd {
+	text := strings.TrimSpace(m.filterInput.Value())
+	f, err := parseFilter(text, time.Now())
+	if err != nil {
+		return intents.Invoke(intents.AddMessage{Text: err.Error(), Err: err})
+	}
+	m.filter = f
+	m.filterInput.Blur()
+	if f.isEmpty() {
+		m.filterState = filterOff
+		m.filterInput.SetValue("")
+	} else {
+		m.filterState = filterApplied
+	}
+	m.rebuildRows()
+	return tea.Batch(m.updateSelection(), m.loadAll())
+}
+
+func (m *Model) resetFilter() tea.Cmd {
+	m.filterState = filterOff
+	m.filterInput.SetValue("")
+	m.filterInput.Blur()
+	m.filter = filter{}
+	m.rebuildRows()
+	return m.updateSelection()
+}
+
 func (m *Model) search(startIndex int, backward bool) int {
 	items := make([]screen.Searchable, len(m.rows))
 	for i := range m.rows {
@@ -197,6 +334,9 @@ func (m *Model) search(startIndex int, backward bool) int {
 }
 
 func (m *Model) close() tea.Cmd {
+	if m.filterState == filterApplied {
+		return m.resetFilter()
+	}
 	return tea.Batch(common.Close, common.Refresh, common.SelectionChanged(m.context.SelectedItem))
 }
 
@@ -214,6 +354,23 @@ func (m *Model) showDiff(intent intents.OpLogShowDiff) tea.Cmd {
 	}
 }
 
+// compare shows what changed between the marked and the selected operations,
+// going from the older one to the newer one whichever was marked first
+func (m *Model) compare() tea.Cmd {
+	if len(m.rows) == 0 {
+		return nil
+	}
+	selected := m.rows[m.cursor].OperationId
+	markedIndex := slices.IndexFunc(m.rows, func(r row) bool { return r.contains(m.marked) })
+	if markedIndex < 0 || markedIndex == m.cursor {
+		return intents.Invoke(intents.AddMessage{Text: "mark an operation and move to another one to compare them"})
+	}
+	if markedIndex > m.cursor {
+		return intents.Invoke(intents.ShowOpDiff{From: m.marked, To: selected})
+	}
+	return intents.Invoke(intents.ShowOpDiff{From: selected, To: m.marked})
+}
+
 func (m *Model) restore(intent intents.OpLogRestore) tea.Cmd {
 	opId := intent.OperationId
 	if opId == "" {
@@ -243,6 +400,12 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 		return
 	}
 
+	if m.filterState != filterOff {
+		var filterBox layout.Box
+		filterBox, box = box.CutTop(1)
+		m.renderFilter(dl, filterBox)
+	}
+
 	measure := func(index int) int {
 		return len(m.rows[index].Lines)
 	}
@@ -250,6 +413,7 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	textStyle := common.DefaultPalette.Get("oplog text")
 	selectedStyle := common.DefaultPalette.Get("oplog selected")
 	matchedStyle := common.DefaultPalette.Get("oplog matched")
+	markerStyle := common.DefaultPalette.Get("oplog source_marker")
 
 	renderItem := func(dl *render.DisplayContext, index int, itemRect layout.Rectangle) {
 		row := m.rows[index]
@@ -262,9 +426,13 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 		y := itemRect.Min.Y
 		for _, line := range row.Lines {
 			var content bytes.Buffer
-			for _, segment := range line.Segments {
+			for i, segment := range line.Segments {
 				text := segment.Text
-				style := segment.Style.Inherit(styleOverride)
+				style := segment.Style
+				if line.styles[i] != "" {
+					style = common.DefaultPalette.Get(line.styles[i])
+				}
+				style = style.Inherit(styleOverride)
 
 				if m.quickSearch != "" && text != "" {
 					lowerText := strings.ToLower(text)
@@ -292,6 +460,15 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 			dl.AddDraw(lineRect, lineContent, 0)
 			y++
 		}
+
+		if m.marked != "" && row.contains(m.marked) {
+			marker := markerStyle.Render("<< marked >>")
+			markerWidth := lipgloss.Width(marker)
+			if markerWidth < itemRect.Dx() {
+				markerRect := layout.Rect(itemRect.Max.X-markerWidth, itemRect.Min.Y, markerWidth, 1)
+				dl.AddDraw(markerRect, marker, 1)
+			}
+		}
 	}
 
 	clickMsg := func(index int, _ tea.Mouse) render.ClickMessage {
@@ -313,16 +490,54 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.ensureCursorView = false
 }
 
-func (m *Model) load() tea.Cmd {
+func (m *Model) renderFilter(dl *render.DisplayContext, box layout.Box) {
+	promptStyle := common.DefaultPalette.Get("oplog title")
+	textStyle := common.DefaultPalette.Get("oplog text")
+	dimmedStyle := common.DefaultPalette.Get("oplog dimmed")
+
+	count := dimmedStyle.Render(fmt.Sprintf(" %d of %d operations", m.matchingCount(), len(m.entries)))
+	if m.filterState == filterEditing {
+		fis := m.filterInput.Styles()
+		fis.Focused.Prompt = promptStyle
+		fis.Focused.Text = textStyle
+		fis.Blurred.Prompt = promptStyle
+		fis.Blurred.Text = textStyle
+		m.filterInput.SetStyles(fis)
+		m.filterInput.SetWidth(max(box.R.Dx()-lipgloss.Width(count)-lipgloss.Width(m.filterInput.Prompt)-1, 0))
+		dl.AddDraw(box.R, m.filterInput.View()+count, 0)
+		return
+	}
+	content := promptStyle.Render(m.filterInput.Prompt) + textStyle.Render(m.filterInput.Value()) + count
+	dl.AddDraw(box.R, content, 0)
+}
+
+func (m *Model) matchingCount() int {
+	count := 0
+	for _, r := range m.rows {
+		count += len(r.Entries)
+	}
+	return count
+}
+
+func (m *Model) load(limit int) tea.Cmd {
 	return func() tea.Msg {
-		output, err := m.context.RunCommandImmediate(jj.OpLog(config.Current.OpLog.Limit))
+		output, err := m.context.RunCommandImmediate(jj.OpLogEntries(limit))
 		if err != nil {
 			panic(err)
 		}
+		entries := jj.ParseOpLog(string(output))
+		return updateOpLogMsg{Entries: entries, Complete: limit <= 0 || len(entries) < limit}
+	}
+}
 
-		rows := parseRows(bytes.NewReader(output))
-		return updateOpLogMsg{Rows: rows}
+// loadAll loads the operations past the op log limit the first time a filter
+// is used, so that the filter looks through the whole op log
+func (m *Model) loadAll() tea.Cmd {
+	if m.complete || m.filter.isEmpty() {
+		return nil
 	}
+	m.complete = true
+	return m.load(0)
 }
 
 func New(context *context.MainContext) *Model {
@@ -332,5 +547,8 @@ func New(context *context.MainContext) *Model {
 		cursor:  0,
 	}
 	m.listRenderer = render.NewListRenderer(OpLogScrollMsg{})
+	m.filterInput = textinput.New()
+	m.filterInput.Prompt = "Filter: "
+	m.expanded = map[string]bool{}
 	return m
 }
@@ -1,15 +1,19 @@
 package oplog
 
 import (
+	"strings"
 	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/actions"
 	"github.com/idursun/jjui/internal/ui/bindings"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/render"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -95,3 +99,136 @@ func TestScopes_ExposeQuickSearchScopeWhenSearchActive(t *testing.T) {
 	assert.Equal(t, bindings.ScopeName(actions.ScopeOplogQuickSearch), scopes[0].Name)
 	assert.Equal(t, bindings.ScopeName(actions.ScopeOplog), scopes[1].Name)
 }
+
+func TestOpLogCompare_GoesFromOlderToNewerOperation(t *testing.T) {
+	m := &Model{
+		context: &context.MainContext{},
+		rows: []row{
+			{OperationId: "op3"},
+			{OperationId: "op2"},
+			{OperationId: "op1"},
+		},
+		cursor: 0,
+	}
+	m.listRenderer = render.NewListRenderer(OpLogScrollMsg{})
+
+	m.Update(intents.OpLogMark{})
+	assert.Equal(t, "op3", m.marked)
+	m.cursor = 2
+
+	cmd := m.Update(intents.OpLogCompare{})
+	require.NotNil(t, cmd)
+	assert.Equal(t, intents.ShowOpDiff{From: "op1", To: "op3"}, cmd())
+}
+
+func TestOpLogCompare_RequiresMarkedOperation(t *testing.T) {
+	m := &Model{
+		context: &context.MainContext{},
+		rows:    []row{{OperationId: "op2"}, {OperationId: "op1"}},
+	}
+
+	cmd := m.Update(intents.OpLogCompare{})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(intents.AddMessage)
+	assert.True(t, ok)
+}
+
+const opLogOutput = "@  \x005f1e2d3c4b5a\tjane@laptop\t2026-10-17T08:30:00+0000\trebase commit 4b3a0c1d\targs: jj rebase -d main\n" +
+	"○  \x004e5f6a7b8c9d\tjane@laptop\t2026-10-17T08:20:00+0000\tsnapshot working copy\targs: jj log\n" +
+	"○  \x003d4e5f6a7b8c\tjane@laptop\t2026-10-17T08:10:00+0000\tsnapshot working copy\targs: jj log\n" +
+	"○  \x002c3d4e5f6a7b\tjane@laptop\t2026-10-17T08:00:00+0000\tsnapshot working copy\targs: jj status\n" +
+	"○  \x001b2c3d4e5f6a\tci@build-01\t2026-10-17T03:00:00+0000\tfetch from git remote(s) origin\targs: jj git fetch\n"
+
+func loadedModel(t *testing.T) *Model {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogEntries(config.Current.OpLog.Limit)).SetOutput([]byte(opLogOutput))
+	t.Cleanup(commandRunner.Verify)
+
+	m := New(test.NewTestContext(commandRunner))
+	test.SimulateModel(m, m.Init())
+	return m
+}
+
+func TestOpLog_CollapsesSnapshotRuns(t *testing.T) {
+	m := loadedModel(t)
+
+	require.Len(t, m.rows, 3)
+	assert.Equal(t, "4e5f6a7b8c9d", m.rows[1].OperationId)
+	assert.Len(t, m.rows[1].Entries, 3)
+
+	rendered := test.Stripped(test.RenderImmediate(m, 120, 10))
+	assert.Contains(t, rendered, "@    5f1e2d3c4b5a jane@laptop 2026-10-17 08:30:00 rebase rebase commit 4b3a0c1d")
+	assert.Contains(t, rendered, "○  ▸ 4e5f6a7b8c9d jane@laptop 2026-10-17 08:20:00 snapshot 3 snapshots since 2026-10-17 08:00:00")
+	assert.Contains(t, rendered, "○    1b2c3d4e5f6a ci@build-01 2026-10-17 03:00:00 git fetch fetch from git remote(s) origin")
+}
+
+func TestOpLog_ToggleCollapse(t *testing.T) {
+	m := loadedModel(t)
+	m.SetCursor(1)
+
+	m.Update(intents.OpLogToggleCollapse{})
+	require.Len(t, m.rows, 5)
+	assert.Equal(t, 1, m.cursor, "expected cursor to stay on the newest snapshot")
+
+	m.SetCursor(3)
+	m.Update(intents.OpLogToggleCollapse{})
+	require.Len(t, m.rows, 3)
+	assert.Equal(t, 1, m.cursor, "expected cursor to move to the collapsed row")
+}
+
+func TestOpLog_FilterByUserAndCommand(t *testing.T) {
+	m := loadedModel(t)
+
+	test.SimulateModel(m, intents.Invoke(intents.OpLogOpenFilter{}))
+	assert.Equal(t, bindings.ScopeName(actions.ScopeOplogFilter), m.Scopes()[0].Name)
+
+	test.SimulateModel(m, test.Type("user:ci cmd:fetch"))
+	require.Len(t, m.rows, 1)
+	assert.Equal(t, "1b2c3d4e5f6a", m.rows[0].OperationId)
+
+	m.Update(intents.Apply{})
+	assert.Equal(t, filterApplied, m.filterState)
+	assert.Contains(t, test.Stripped(test.RenderImmediate(m, 120, 10)), "Filter: user:ci cmd:fetch 1 of 5 operations")
+
+	test.SimulateModel(m, intents.Invoke(intents.OpLogClose{}))
+	assert.Equal(t, filterOff, m.filterState, "expected close to clear the filter first")
+	assert.Len(t, m.rows, 3)
+}
+
+func TestOpLog_KeepsGraphLinesBetweenOperations(t *testing.T) {
+	m := &Model{filter: filter{}}
+	m.entries = jj.ParseOpLog("@    \x00bbbbbbbbbbbb\tjane@laptop\t2026-10-17T08:30:00+0000\treconcile divergent operations\n" +
+		"├─╮\n" +
+		"○ │  \x00aaaaaaaaaaaa\tjane@laptop\t2026-10-17T08:20:00+0000\tnew empty commit\n")
+	m.rebuildRows()
+
+	require.Len(t, m.rows, 2)
+	require.Len(t, m.rows[0].Lines, 2)
+	assert.Equal(t, "├─╮", m.rows[0].Lines[1].Segments[0].Text)
+
+	m.filter, _ = parseFilter("new", now)
+	m.rebuildRows()
+	require.Len(t, m.rows, 1)
+	assert.Len(t, m.rows[0].Lines, 1, "expected graph lines to be dropped while filtering")
+}
+
+func TestOpLog_FilterLoadsOperationsPastTheLimit(t *testing.T) {
+	limit := config.Current.OpLog.Limit
+	config.Current.OpLog.Limit = 2
+	t.Cleanup(func() { config.Current.OpLog.Limit = limit })
+
+	lines := strings.SplitAfter(opLogOutput, "\n")
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogEntries(2)).SetOutput([]byte(lines[0] + lines[1]))
+	commandRunner.Expect(jj.OpLogEntries(0)).SetOutput([]byte(opLogOutput))
+	defer commandRunner.Verify()
+
+	m := New(test.NewTestContext(commandRunner))
+	test.SimulateModel(m, m.Init())
+	require.Len(t, m.rows, 2)
+
+	test.SimulateModel(m, intents.Invoke(intents.OpLogOpenFilter{}))
+	test.SimulateModel(m, test.Type("user:ci"))
+	require.Len(t, m.rows, 1)
+	assert.Equal(t, "1b2c3d4e5f6a", m.rows[0].OperationId)
+}
@@ -1,12 +1,40 @@
 package oplog
 
 import (
+	"fmt"
+
+	"charm.land/lipgloss/v2"
+	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/screen"
 )
 
+const timeLayout = "2006-01-02 15:04:05"
+
 type row struct {
 	OperationId string
 	Lines       []*rowLine
+	// Entries are the operations shown by the row, more than one when a run
+	// of snapshots is collapsed into it
+	Entries []jj.OpLogEntry
+	// run is the id of the newest operation of the snapshot run the row
+	// belongs to, empty for rows outside of a run
+	run string
+}
+
+func (r *row) collapsed() bool {
+	return len(r.Entries) > 1
+}
+
+func (r *row) contains(operationId string) bool {
+	if r.OperationId == operationId {
+		return true
+	}
+	for _, entry := range r.Entries {
+		if entry.Id == operationId {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *row) GetSearchableLines() []screen.SearchableLine {
@@ -19,33 +47,112 @@ func (r *row) GetSearchableLines() []screen.SearchableLine {
 
 type rowLine struct {
 	Segments []*screen.Segment
+	// styles are the palette keys of the segments, looked up when the line is
+	// drawn so that theme changes apply to rows that are already built
+	styles []string
 }
 
 func (rl *rowLine) GetSegments() []*screen.Segment {
 	return rl.Segments
 }
 
-func (rl *rowLine) FindIdIndex() int {
-	for i, segment := range rl.Segments {
-		if isOperationId(segment.Text) {
-			return i
+func (rl *rowLine) add(text string, style string) {
+	rl.Segments = append(rl.Segments, &screen.Segment{Text: text})
+	rl.styles = append(rl.styles, style)
+}
+
+// buildRows turns the operations matching the filter into rows. Consecutive
+// snapshots are collapsed into a single row unless their run is expanded. The
+// lines of the op log graph between operations are only kept while nothing is
+// filtered out, as they would connect operations that are not next to each
+// other otherwise.
+func buildRows(entries []jj.OpLogEntry, f filter, expanded map[string]bool) []row {
+	var matching []jj.OpLogEntry
+	for _, entry := range entries {
+		if f.match(entry) {
+			matching = append(matching, entry)
 		}
 	}
-	return -1
-}
 
-func isOperationId(text string) bool {
-	if len(text) != 12 {
-		return false
+	rows := make([]row, 0, len(matching))
+	for i := 0; i < len(matching); {
+		end := i + 1
+		if matching[i].IsSnapshot() {
+			for end < len(matching) && matching[end].IsSnapshot() {
+				end++
+			}
+		}
+		run := matching[i:end]
+		switch {
+		case len(run) == 1:
+			rows = append(rows, row{OperationId: run[0].Id, Entries: run})
+		case expanded[run[0].Id]:
+			for j := range run {
+				rows = append(rows, row{OperationId: run[j].Id, Entries: run[j : j+1], run: run[0].Id})
+			}
+		default:
+			rows = append(rows, row{OperationId: run[0].Id, Entries: run, run: run[0].Id})
+		}
+		i = end
+	}
+
+	userWidth, graphWidth := 0, 0
+	for _, r := range rows {
+		userWidth = max(userWidth, lipgloss.Width(userAtHost(r.Entries[0])))
+		graphWidth = max(graphWidth, lipgloss.Width(r.Entries[0].Graph))
 	}
-	for _, r := range text {
-		if !(r >= 'a' && r <= 'f' || r >= '0' && r <= '9') {
-			return false
+	for i := range rows {
+		r := &rows[i]
+		r.Lines = []*rowLine{newRowLine(r, userWidth, graphWidth)}
+		if !f.isEmpty() {
+			continue
+		}
+		for _, graph := range r.Entries[len(r.Entries)-1].GraphLines {
+			line := &rowLine{}
+			line.add(graph, "oplog dimmed")
+			r.Lines = append(r.Lines, line)
 		}
 	}
-	return true
+	return rows
+}
+
+func newRowLine(r *row, userWidth int, graphWidth int) *rowLine {
+	entry := r.Entries[0]
+	marker := "  "
+	if r.collapsed() {
+		marker = "▸ "
+	} else if r.run != "" && r.run == entry.Id {
+		marker = "▾ "
+	}
+	description := entry.Description
+	if r.collapsed() {
+		oldest := r.Entries[len(r.Entries)-1]
+		description = fmt.Sprintf("%d snapshots since %s", len(r.Entries), oldest.Time.Format(timeLayout))
+	}
+	graphStyle := "oplog dimmed"
+	if entry.Current {
+		graphStyle = "oplog current"
+	}
+	user := userAtHost(entry)
+
+	line := &rowLine{}
+	line.add(entry.Graph+fmt.Sprintf("%*s", graphWidth-lipgloss.Width(entry.Graph), ""), graphStyle)
+	line.add(marker, "oplog dimmed")
+	line.add(entry.Id, "oplog id")
+	line.add(" ", "")
+	line.add(user+fmt.Sprintf("%*s", userWidth-lipgloss.Width(user), ""), "oplog user")
+	line.add(" ", "")
+	line.add(entry.Time.Format(timeLayout), "oplog time")
+	line.add(" ", "")
+	line.add(entry.Kind(), "oplog kind")
+	line.add(" ", "")
+	line.add(description, "")
+	return line
 }
 
-func newRowLine(segments []*screen.Segment) rowLine {
-	return rowLine{Segments: segments}
+func userAtHost(entry jj.OpLogEntry) string {
+	if entry.Host == "" {
+		return entry.User
+	}
+	return entry.User + "@" + entry.Host
 }
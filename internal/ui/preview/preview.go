@@ -13,6 +13,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/actions"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/diff"
 	"github.com/idursun/jjui/internal/ui/dispatch"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
@@ -27,7 +28,11 @@ type Model struct {
 	previewAutoPosition bool
 	previewAtBottom     bool
 	content             string
-	context             *context.MainContext
+	sideBySide          bool
+	// renderedWidth is the width the side-by-side layout was last rendered
+	// at, -1 when the content needs to be laid out again.
+	renderedWidth int
+	context       *context.MainContext
 }
 
 const (
@@ -125,6 +130,20 @@ func (m *Model) AtBottom() bool {
 	return m.previewAtBottom
 }
 
+func (m *Model) SideBySide() bool {
+	return m.sideBySide
+}
+
+// ToggleSideBySide switches between showing diffs as they are produced by the
+// preview command and laying them out side by side. Only git style diffs can
+// be laid out side by side, so while it is on the preview commands of jj are
+// run with --git; anything else is always shown as is.
+func (m *Model) ToggleSideBySide() {
+	m.sideBySide = !m.sideBySide
+	m.renderedWidth = -1
+	m.view.SetContent(m.content)
+}
+
 func (m *Model) YOffset() int {
 	return m.view.YOffset()
 }
@@ -207,12 +226,19 @@ func (m *Model) SetContent(content string) {
 	}
 	m.reset()
 	m.content = content
+	m.renderedWidth = -1
 	m.view.SetContent(content)
 }
 
 func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.view.SetWidth(box.R.Dx())
 	m.view.SetHeight(box.R.Dy())
+	if m.sideBySide && m.renderedWidth != box.R.Dx() {
+		m.renderedWidth = box.R.Dx()
+		if content, ok := diff.RenderSideBySide(m.content, box.R.Dx()); ok {
+			m.view.SetContent(content)
+		}
+	}
 	dl.AddDraw(box.R, m.view.View(), render.ZPreview)
 
 	scrollRect := layout.Rect(box.R.Min.X, box.R.Min.Y, box.R.Dx(), box.R.Dy())
@@ -229,6 +255,7 @@ func (m *Model) refreshPreview() tea.Cmd {
 }
 
 func (m *Model) refreshPreviewForItem(item common.SelectedItem) tea.Cmd {
+	sideBySide := m.sideBySide
 	return common.Debounce(debounceId, debounceDuration, func() tea.Msg {
 		var args []string
 		previewWidth := strconv.Itoa(m.view.Width())
@@ -262,6 +289,10 @@ func (m *Model) refreshPreviewForItem(item common.SelectedItem) tea.Cmd {
 			})
 		}
 
+		if sideBySide {
+			args = withGitFormat(args)
+		}
+
 		env := []string{
 			// The preview subprocess does not run in a pane-sized PTY, so let
 			// width-sensitive tools like `jj diff` see the preview size via the
@@ -276,6 +307,38 @@ func (m *Model) refreshPreviewForItem(item common.SelectedItem) tea.Cmd {
 	})
 }
 
+// withGitFormat adds --git to a jj command that shows diffs in jj's default
+// format, leaving commands that already pick a format or show no diff alone.
+func withGitFormat(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	switch {
+	case args[0] == "diff", args[0] == "show", args[0] == "evolog":
+	case args[0] == "op" && len(args) > 1 && (args[1] == "show" || args[1] == "diff"):
+	default:
+		return args
+	}
+	end := len(args)
+	for i, arg := range args {
+		if arg == "--" {
+			end = i
+			break
+		}
+		switch arg {
+		case "--git", "--color-words", "--tool", "--summary", "-s", "--stat", "--name-only", "--types":
+			return args
+		}
+		if strings.HasPrefix(arg, "--tool=") {
+			return args
+		}
+	}
+	ret := make([]string, 0, len(args)+1)
+	ret = append(ret, args[:end]...)
+	ret = append(ret, "--git")
+	return append(ret, args[end:]...)
+}
+
 func New(context *context.MainContext) *Model {
 	previewAutoPosition := false
 	previewAtBottom := false
@@ -1,6 +1,7 @@
 package preview
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/idursun/jjui/internal/config"
@@ -197,3 +198,69 @@ func TestSetContent_ResetsTabStopsAfterNewlines(t *testing.T) {
 	rendered := test.RenderImmediate(model, 12, 2)
 	assert.Equal(t, "a   b\nab  c", rendered)
 }
+
+func TestModel_SideBySideLaysOutDiffs(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetContent("diff --git a/f b/f\n@@ -1 +1 @@\n-old\n+new")
+	model.ToggleSideBySide()
+
+	rendered := test.Stripped(test.RenderImmediate(model, 21, 4))
+	assert.Contains(t, rendered, "-old      │+new")
+
+	model.ToggleSideBySide()
+	rendered = test.Stripped(test.RenderImmediate(model, 21, 4))
+	assert.Contains(t, rendered, "-old\n+new")
+}
+
+func TestModel_SideBySideKeepsOtherContent(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.SetContent("Commit ID: abc\nno diff here")
+	model.ToggleSideBySide()
+
+	assert.Equal(t, "Commit ID: abc\nno diff here", test.Stripped(test.RenderImmediate(model, 21, 2)))
+}
+
+func TestModel_SideBySideRunsPreviewWithGitDiffs(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := New(ctx)
+	model.ToggleSideBySide()
+
+	selected := common.SelectedFile{ChangeId: "change", CommitId: "commit", File: "f"}
+	args := jj.TemplatedArgs(config.Current.Preview.FileCommand, map[string]string{
+		jj.ChangeIdPlaceholder:     selected.ChangeId,
+		jj.CommitIdPlaceholder:     selected.CommitId,
+		jj.FilePlaceholder:         selected.File,
+		jj.PreviewWidthPlaceholder: "0",
+	})
+	commandRunner.Expect(append(args, "--git")).SetOutput([]byte("diff --git a/f b/f"))
+
+	test.SimulateModel(model, model.Update(common.SelectionChangedMsg{Item: selected}))
+	assert.Equal(t, "diff --git a/f b/f", model.content)
+}
+
+func TestWithGitFormat(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"show", "-r", "x"}, []string{"show", "-r", "x", "--git"}},
+		{[]string{"diff", "-r", "x", "--", "f"}, []string{"diff", "-r", "x", "--git", "--", "f"}},
+		{[]string{"op", "show", "abc"}, []string{"op", "show", "abc", "--git"}},
+		{[]string{"diff", "--color-words", "-r", "x"}, []string{"diff", "--color-words", "-r", "x"}},
+		{[]string{"diff", "--tool=difft"}, []string{"diff", "--tool=difft"}},
+		{[]string{"op", "log"}, []string{"op", "log"}},
+		{[]string{"log", "-r", "x"}, []string{"log", "-r", "x"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, withGitFormat(tt.args), strings.Join(tt.args, " "))
+	}
+}
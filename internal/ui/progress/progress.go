@@ -0,0 +1,201 @@
+package progress
+
+import (
+	"strings"
+	"sync"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ common.Disposable = (*Model)(nil)
+
+type startedMsg struct {
+	command *context.ProgressCommand
+	err     error
+}
+
+type outputMsg struct {
+	chunk string
+}
+
+type finishedMsg struct {
+	output string
+	err    error
+}
+
+// Model runs a long running command like fetch or push, showing its output
+// as it comes in. Once the command exits it is closed and the output is
+// reported like any other command's, keeping it in the command history. The
+// command is killed when the model is disposed before it exits.
+type Model struct {
+	context    *context.MainContext
+	args       jj.CommandArgs
+	command    *context.ProgressCommand
+	lines      []string
+	replacing  bool
+	cancelling bool
+	// mu guards started and disposed, which are set from the command starting
+	// in the background and from Dispose
+	mu       sync.Mutex
+	started  *context.ProgressCommand
+	disposed bool
+}
+
+func NewModel(c *context.MainContext, args jj.CommandArgs) *Model {
+	return &Model{
+		context: c,
+		args:    args,
+	}
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeProgress,
+			Leak:    dispatch.LeakNone,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return func() tea.Msg {
+		command, err := m.context.RunCommandWithProgress(m.args)
+		if err == nil {
+			m.mu.Lock()
+			m.started = command
+			if m.disposed {
+				command.Cancel()
+			}
+			m.mu.Unlock()
+		}
+		return startedMsg{command: command, err: err}
+	}
+}
+
+// Dispose kills the command when it is still running, as nothing reads its
+// output anymore
+func (m *Model) Dispose() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.disposed = true
+	if m.started != nil {
+		m.started.Cancel()
+	}
+}
+
+// next waits for the command to write more output or to exit
+func (m *Model) next() tea.Msg {
+	if chunk, ok := m.command.Next(); ok {
+		return outputMsg{chunk: chunk}
+	}
+	output, err := m.command.Wait()
+	return finishedMsg{output: output, err: err}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case startedMsg:
+		if msg.err != nil {
+			return tea.Sequence(
+				func() tea.Msg { return common.CommandCompletedMsg{Err: msg.err} },
+				common.Close,
+			)
+		}
+		m.command = msg.command
+		return m.next
+	case outputMsg:
+		m.addOutput(msg.chunk)
+		return m.next
+	case finishedMsg:
+		id, command := m.command.ID, m.command.Command
+		return tea.Sequence(
+			func() tea.Msg { return common.CommandRunningMsg{ID: id, Command: command} },
+			func() tea.Msg { return common.CommandCompletedMsg{ID: id, Output: msg.output, Err: msg.err} },
+			common.CloseApplied,
+			common.Refresh,
+		)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent.(type) {
+	case intents.Cancel:
+		if m.command != nil && !m.cancelling {
+			m.cancelling = true
+			m.command.Cancel()
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// addOutput adds a chunk of output, replacing the last line when it was a
+// progress line ending with "\r"
+func (m *Model) addOutput(chunk string) {
+	line := strings.TrimRight(chunk, "\r\n")
+	if m.replacing && chunk == "\n" {
+		// the "\n" of a "\r\n" ending keeps the last progress line
+		m.replacing = false
+		return
+	}
+	if m.replacing && len(m.lines) > 0 {
+		m.lines[len(m.lines)-1] = line
+	} else {
+		m.lines = append(m.lines, line)
+	}
+	m.replacing = strings.HasSuffix(chunk, "\r")
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 20))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("progress text")
+	dimmedStyle := common.DefaultPalette.Get("progress dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("progress border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled(ansi.Truncate("jj "+strings.Join(m.args, " "), titleBox.R.Dx(), "…"), common.DefaultPalette.Get("progress title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	contentBox, statusBox := contentBox.CutBottom(1)
+	status := "running, esc to cancel"
+	if m.cancelling {
+		status = "cancelling..."
+	}
+	dl.AddDraw(statusBox.R, dimmedStyle.Render(status), render.ZMenuContent)
+
+	height := contentBox.R.Dy()
+	lines := m.lines[max(len(m.lines)-height, 0):]
+	for i, line := range lines {
+		rect := layout.Rect(contentBox.R.Min.X, contentBox.R.Min.Y+i, contentBox.R.Dx(), 1)
+		dl.AddDraw(rect, textStyle.Render(ansi.Truncate(line, rect.Dx(), "…")), render.ZMenuContent)
+	}
+}
@@ -0,0 +1,60 @@
+package progress
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOutput_ReplacesProgressLines(t *testing.T) {
+	model := NewModel(nil, jj.GitFetch())
+	for _, chunk := range []string{"remote: Counting objects: 10%\r", "remote: Counting objects: 100%\r", "\n", "Nothing changed.\n"} {
+		model.addOutput(chunk)
+	}
+	assert.Equal(t, []string{"remote: Counting objects: 100%", "Nothing changed."}, model.lines)
+}
+
+func TestModel_ReportsOutputWhenCommandExits(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitFetch("--remote", "origin")).SetOutput([]byte("bookmark: main@origin [updated] tracked\n"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), jj.GitFetch("--remote", "origin"))
+	var completed *common.CommandCompletedMsg
+	closed := false
+	test.SimulateModel(model, model.Init(), func(msg tea.Msg) {
+		switch msg := msg.(type) {
+		case common.CommandCompletedMsg:
+			completed = &msg
+		case common.CloseViewMsg:
+			closed = true
+		}
+	})
+
+	assert.Equal(t, []string{"bookmark: main@origin [updated] tracked"}, model.lines)
+	if assert.NotNil(t, completed) {
+		assert.Equal(t, "bookmark: main@origin [updated] tracked\n", completed.Output)
+		assert.NoError(t, completed.Err)
+	}
+	assert.True(t, closed)
+}
+
+func TestModel_ReportsFailure(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GitPush("--remote", "origin")).SetError(errors.New("Error: failed to connect"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), jj.GitPush("--remote", "origin"))
+	var err error
+	test.SimulateModel(model, model.Init(), func(msg tea.Msg) {
+		if completed, ok := msg.(common.CommandCompletedMsg); ok {
+			err = completed.Err
+		}
+	})
+	assert.EqualError(t, err, "Error: failed to connect")
+}
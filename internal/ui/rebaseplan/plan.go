@@ -0,0 +1,102 @@
+package rebaseplan
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+)
+
+// entry is a revision of the stack along with what to do with it
+type entry struct {
+	changeId    string
+	commitId    string
+	description string
+	action      intents.RebasePlanAction
+	message     string
+}
+
+var errSquashFirst = errors.New("the first revision cannot be squashed, there is nothing to squash it into")
+
+func selected(changeIds ...string) jj.SelectedRevisions {
+	var revisions []*jj.Commit
+	for _, id := range changeIds {
+		revisions = append(revisions, &jj.Commit{ChangeId: id})
+	}
+	return jj.NewSelectedRevisions(revisions...)
+}
+
+// planCommands turns a plan into the jj commands carrying it out. original is
+// the order of the stack before it was edited, and plan is the edited stack,
+// both oldest first.
+//
+// Dropped revisions are abandoned first. The remaining ones are then put in
+// order from the bottom up, each moved revision being inserted after the one
+// that should precede it (or before the bottom of the stack). Finally
+// revisions are squashed into their predecessors and reworded. A revision
+// squashed into another one adds its description to the other one's, unless
+// that one is reworded.
+func planCommands(original []string, plan []*entry) ([]jj.CommandArgs, error) {
+	var commands []jj.CommandArgs
+
+	var dropped []string
+	var kept []*entry
+	for _, e := range plan {
+		if e.action == intents.RebasePlanDrop {
+			dropped = append(dropped, e.changeId)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) > 0 && kept[0].action == intents.RebasePlanSquash {
+		return nil, errSquashFirst
+	}
+	if len(dropped) > 0 {
+		commands = append(commands, jj.Abandon(selected(dropped...), false))
+	}
+
+	current := slices.DeleteFunc(slices.Clone(original), func(id string) bool {
+		return slices.Contains(dropped, id)
+	})
+	for i, e := range kept {
+		if current[i] == e.changeId {
+			continue
+		}
+		if i == 0 {
+			commands = append(commands, jj.Rebase(selected(e.changeId), "-r", current[0], "--insert-before", false, false))
+		} else {
+			commands = append(commands, jj.Rebase(selected(e.changeId), "-r", kept[i-1].changeId, "--insert-after", false, false))
+		}
+		current = slices.DeleteFunc(current, func(id string) bool { return id == e.changeId })
+		current = slices.Insert(current, i, e.changeId)
+	}
+
+	var into *entry
+	descriptions := map[*entry][]string{}
+	for _, e := range kept {
+		if e.action != intents.RebasePlanSquash {
+			into = e
+			continue
+		}
+		commands = append(commands, jj.Squash(selected(e.changeId), into.changeId, nil, false, true, false, false))
+		if description := strings.TrimSpace(e.description); description != "" {
+			descriptions[into] = append(descriptions[into], description)
+		}
+	}
+
+	for _, e := range kept {
+		switch {
+		case e.action == intents.RebasePlanReword:
+			commands = append(commands, jj.Reword(e.changeId, e.message))
+		case len(descriptions[e]) > 0:
+			combined := descriptions[e]
+			if description := strings.TrimSpace(e.description); description != "" {
+				combined = append([]string{description}, combined...)
+			}
+			commands = append(commands, jj.Reword(e.changeId, strings.Join(combined, "\n\n")))
+		}
+	}
+	return commands, nil
+}
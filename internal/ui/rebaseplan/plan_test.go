@@ -0,0 +1,84 @@
+package rebaseplan
+
+import (
+	"testing"
+
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/stretchr/testify/assert"
+)
+
+func stack(ids ...string) []*entry {
+	var entries []*entry
+	for _, id := range ids {
+		entries = append(entries, &entry{changeId: id, action: intents.RebasePlanPick})
+	}
+	return entries
+}
+
+func TestPlanCommands_UnchangedStackDoesNothing(t *testing.T) {
+	commands, err := planCommands([]string{"a", "b", "c"}, stack("a", "b", "c"))
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+}
+
+func TestPlanCommands_Reorder(t *testing.T) {
+	commands, err := planCommands([]string{"a", "b", "c"}, stack("c", "a", "b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []jj.CommandArgs{
+		{"rebase", "-r", "c", "--insert-before", "a"},
+	}, commands)
+
+	commands, err = planCommands([]string{"a", "b", "c"}, stack("b", "c", "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []jj.CommandArgs{
+		{"rebase", "-r", "b", "--insert-before", "a"},
+		{"rebase", "-r", "c", "--insert-after", "b"},
+	}, commands)
+}
+
+func TestPlanCommands_DropSquashAndReword(t *testing.T) {
+	plan := stack("a", "b", "c", "d")
+	plan[1].action = intents.RebasePlanDrop
+	plan[2].action = intents.RebasePlanSquash
+	plan[3].action = intents.RebasePlanReword
+	plan[3].message = "new message"
+
+	commands, err := planCommands([]string{"a", "b", "c", "d"}, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, []jj.CommandArgs{
+		{"abandon", "--retain-bookmarks", "-r", "b"},
+		{"squash", "--from", "c", "--into", "a", "--use-destination-message"},
+		{"describe", "-r", "d", "-m", "new message"},
+	}, commands)
+}
+
+func TestPlanCommands_SquashKeepsBothDescriptions(t *testing.T) {
+	plan := stack("a", "b", "c", "d")
+	plan[0].description = "add parser"
+	plan[1].description = "fix typo\n"
+	plan[2].action = intents.RebasePlanSquash
+	plan[3].action = intents.RebasePlanSquash
+	plan[3].description = "handle empty input"
+
+	commands, err := planCommands([]string{"a", "b", "c", "d"}, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, []jj.CommandArgs{
+		{"squash", "--from", "c", "--into", "b", "--use-destination-message"},
+		{"squash", "--from", "d", "--into", "b", "--use-destination-message"},
+		{"describe", "-r", "b", "-m", "fix typo\n\nhandle empty input"},
+	}, commands)
+
+	plan[1].action = intents.RebasePlanReword
+	plan[1].message = "fix parser"
+	commands, err = planCommands([]string{"a", "b", "c", "d"}, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, jj.CommandArgs{"describe", "-r", "b", "-m", "fix parser"}, commands[len(commands)-1])
+}
+
+func TestPlanCommands_SquashingFirstRevisionFails(t *testing.T) {
+	plan := stack("a", "b")
+	plan[0].action = intents.RebasePlanSquash
+	_, err := planCommands([]string{"a", "b"}, plan)
+	assert.ErrorIs(t, err, errSquashFirst)
+}
@@ -0,0 +1,392 @@
+package rebaseplan
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/textarea"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ common.Editable = (*Model)(nil)
+
+// DefaultRevset is the stack planned when no revisions are checked
+const DefaultRevset = "trunk()..@"
+
+type loadedMsg struct {
+	entries []*entry
+	err     error
+}
+
+type appliedMsg struct {
+	before string
+	after  string
+	err    error
+}
+
+type entryClickMsg struct {
+	Index int
+}
+
+type entryScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m entryScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type Model struct {
+	context             *context.MainContext
+	revset              string
+	entries             []*entry
+	original            []string
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	editing             bool
+	input               textarea.Model
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	if m.editing {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeRebasePlanReword,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeRebasePlan,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) IsEditing() bool {
+	return m.editing
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.StackLog(m.revset))
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	entries, err := parseStack(string(output))
+	if err != nil {
+		return loadedMsg{err: fmt.Errorf("%s: %w", m.revset, err)}
+	}
+	return loadedMsg{entries: entries}
+}
+
+// parseStack parses the output of jj.StackLog and checks that the revisions
+// form a linear stack, each revision being the only parent of the next one.
+func parseStack(output string) ([]*entry, error) {
+	var entries []*entry
+	var previous string
+	for _, row := range strings.Split(output, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(row, "\n"), "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		parents := strings.Split(fields[2], ",")
+		if len(parents) != 1 || (previous != "" && parents[0] != previous) {
+			return nil, fmt.Errorf("revisions do not form a linear stack")
+		}
+		entries = append(entries, &entry{
+			changeId:    fields[0],
+			commitId:    fields[1],
+			description: strings.TrimSpace(fields[3]),
+			action:      intents.RebasePlanPick,
+		})
+		previous = fields[0]
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no revisions to plan")
+	}
+	return entries, nil
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.entries = msg.entries
+		m.original = nil
+		for _, e := range m.entries {
+			m.original = append(m.original, e.changeId)
+		}
+	case appliedMsg:
+		changed := msg.before != "" && msg.after != "" && msg.before != msg.after
+		if changed {
+			m.context.UndoPoint = &context.UndoPoint{
+				Description: "rebase plan for " + m.revset,
+				Before:      msg.before,
+				After:       msg.after,
+			}
+		}
+		if msg.err != nil {
+			text := fmt.Sprintf("rebase plan failed: %s", strings.TrimSpace(msg.err.Error()))
+			if changed {
+				text = fmt.Sprintf("rebase plan failed at operation %s, undo goes back to %s: %s", msg.after, msg.before, strings.TrimSpace(msg.err.Error()))
+			}
+			return tea.Batch(common.Close, common.Refresh, intents.Invoke(intents.AddMessage{Text: text, Err: msg.err}))
+		}
+		text := fmt.Sprintf("rebase plan applied as operation %s, undo goes back to %s", msg.after, msg.before)
+		return tea.Batch(common.Close, common.Refresh, intents.Invoke(intents.AddMessage{Text: text}))
+	case entryClickMsg:
+		if !m.editing && msg.Index >= 0 && msg.Index < len(m.entries) {
+			m.cursor = msg.Index
+		}
+	case entryScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.editing {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.RebasePlanNavigate:
+		if len(m.entries) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.entries)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.RebasePlanMove:
+		target := m.cursor + intent.Delta
+		if target >= 0 && target < len(m.entries) {
+			m.entries[m.cursor], m.entries[target] = m.entries[target], m.entries[m.cursor]
+			m.cursor = target
+			m.ensureCursorVisible = true
+			m.message = ""
+		}
+		return nil, true
+	case intents.RebasePlanSetAction:
+		e := m.current()
+		if e == nil {
+			return nil, true
+		}
+		m.message = ""
+		if intent.Action == intents.RebasePlanReword {
+			m.editing = true
+			if e.action == intents.RebasePlanReword {
+				m.input.SetValue(e.message)
+			} else {
+				m.input.SetValue(e.description)
+			}
+			return m.input.Focus(), true
+		}
+		e.action = intent.Action
+		return nil, true
+	case intents.RebasePlanRewordAccept:
+		if e := m.current(); e != nil {
+			e.action = intents.RebasePlanReword
+			e.message = m.input.Value()
+		}
+		m.editing = false
+		m.input.Blur()
+		return nil, true
+	case intents.Apply:
+		if !m.loaded || m.err != nil {
+			return nil, true
+		}
+		commands, err := planCommands(m.original, m.entries)
+		if err != nil {
+			m.message = err.Error()
+			return nil, true
+		}
+		if len(commands) == 0 {
+			m.message = "nothing to do, the plan does not change the stack"
+			return nil, true
+		}
+		return m.apply(commands), true
+	case intents.Cancel:
+		if m.editing {
+			m.editing = false
+			m.input.Blur()
+			return nil, true
+		}
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) current() *entry {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return nil
+	}
+	return m.entries[m.cursor]
+}
+
+// apply runs the commands one after another, stopping at the first failure.
+// The operations the repo was at before and after are reported so that undo
+// can revert the whole plan at once.
+func (m *Model) apply(commands []jj.CommandArgs) tea.Cmd {
+	return func() tea.Msg {
+		before, err := m.context.RunCommandImmediate(jj.OpLogId(true))
+		if err != nil {
+			return appliedMsg{err: err}
+		}
+		for _, args := range commands {
+			if _, err = m.context.RunCommandImmediate(args); err != nil {
+				break
+			}
+		}
+		after, _ := m.context.RunCommandImmediate(jj.OpLogId(false))
+		return appliedMsg{before: string(before), after: string(after), err: err}
+	}
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 40))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("rebase_plan text")
+	borderStyle := common.DefaultPalette.GetBorder("rebase_plan border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled(fmt.Sprintf("rebase plan for %s (oldest first)", m.revset), common.DefaultPalette.Get("rebase_plan title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	if m.editing {
+		var editBox layout.Box
+		contentBox, editBox = contentBox.CutBottom(min(8, contentBox.R.Dy()/2))
+		labelBox, editBox := editBox.CutTop(1)
+		dl.AddDraw(labelBox.R, common.DefaultPalette.Get("rebase_plan reword").Render("new description:"), render.ZMenuContent)
+		m.input.SetWidth(editBox.R.Dx())
+		m.input.SetHeight(editBox.R.Dy())
+		dl.AddDraw(editBox.R, m.input.View(), render.ZMenuContent)
+	}
+	m.renderEntries(dl, contentBox)
+}
+
+func (m *Model) renderEntries(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.entries))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.entries),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			e := m.entries[index]
+			actionStyle := common.DefaultPalette.Get("rebase_plan " + string(e.action))
+			changeIdStyle := common.DefaultPalette.Get("rebase_plan change_id")
+			textStyle := common.DefaultPalette.Get("rebase_plan text")
+			if e.action == intents.RebasePlanDrop {
+				changeIdStyle = common.DefaultPalette.Get("rebase_plan dimmed")
+				textStyle = changeIdStyle.Strikethrough(true)
+			}
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("rebase_plan selected")
+				actionStyle = actionStyle.Inherit(selected)
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				textStyle = textStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			description, _, _ := strings.Cut(e.description, "\n")
+			if e.action == intents.RebasePlanReword {
+				description, _, _ = strings.Cut(e.message, "\n")
+			}
+			if description == "" {
+				description = "(no description set)"
+			}
+			prefix := fmt.Sprintf("%-6s %s ", e.action, e.changeId)
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(fmt.Sprintf("%-6s ", e.action), actionStyle).
+				Styled(e.changeId+" ", changeIdStyle).
+				Styled(ansi.Truncate(description, max(rect.Dx()-ansi.StringWidth(prefix), 0), "…"), textStyle).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return entryClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+func NewModel(c *context.MainContext, revset string) *Model {
+	input := textarea.New()
+	input.CharLimit = 0
+	input.Prompt = ""
+	input.ShowLineNumbers = false
+	styles := input.Styles()
+	styles.Focused.Base = lipgloss.NewStyle()
+	styles.Focused.CursorLine = styles.Focused.Base
+	input.SetStyles(styles)
+
+	m := &Model{
+		context:      c,
+		revset:       revset,
+		input:        input,
+		listRenderer: render.NewListRenderer(entryScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
@@ -0,0 +1,81 @@
+package rebaseplan
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const stackOutput = "aaaaaaaa\t11111111\tzzzzzzzz\tfirst\n\nbody\n\x00" +
+	"bbbbbbbb\t22222222\taaaaaaaa\tsecond\n\x00"
+
+func TestLoad_ShowsStackOldestFirst(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.StackLog(DefaultRevset)).SetOutput([]byte(stackOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), DefaultRevset)
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "pick   aaaaaaaa first")
+	assert.Contains(t, rendered, "pick   bbbbbbbb second")
+}
+
+func TestLoad_RejectsNonLinearStack(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.StackLog(DefaultRevset)).SetOutput([]byte("aaaaaaaa\t11111111\tzzzzzzzz\tfirst\n\x00" +
+		"bbbbbbbb\t22222222\tzzzzzzzz,aaaaaaaa\tmerge\n\x00"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), DefaultRevset)
+	test.SimulateModel(model, model.Init())
+
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "revisions do not form a linear stack")
+}
+
+func TestApply_RunsPlanAndReportsOperation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.StackLog(DefaultRevset)).SetOutput([]byte(stackOutput))
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.Rebase(selected("bbbbbbbb"), "-r", "aaaaaaaa", "--insert-before", false, false))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("4567cdef"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewModel(ctx, DefaultRevset)
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.RebasePlanNavigate{Delta: 1})
+	model.Update(intents.RebasePlanMove{Delta: -1})
+
+	var message string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if added, ok := msg.(intents.AddMessage); ok {
+			message = added.Text
+		}
+	})
+	assert.Equal(t, "rebase plan applied as operation 4567cdef, undo goes back to 0123abcd", message)
+	assert.Equal(t, &context.UndoPoint{Description: "rebase plan for " + DefaultRevset, Before: "0123abcd", After: "4567cdef"}, ctx.UndoPoint)
+}
+
+func TestReword_EditsFullDescription(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.StackLog(DefaultRevset)).SetOutput([]byte(stackOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), DefaultRevset)
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.RebasePlanSetAction{Action: intents.RebasePlanReword} })
+
+	assert.True(t, model.IsEditing())
+	assert.Equal(t, "first\n\nbody", model.input.Value())
+	model.input.SetValue("reworded")
+	model.Update(intents.RebasePlanRewordAccept{})
+	assert.False(t, model.IsEditing())
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "reword aaaaaaaa reworded")
+}
@@ -88,6 +88,11 @@ func (dl *DisplayContext) AddPaint(rect layout.Rectangle, style lipgloss.Style,
 	dl.AddEffect(HighlightEffect{Rect: rect, Style: style, Z: z, Force: true})
 }
 
+// AddStyle adds a StyleEffect, applying the colors and attributes of the style.
+func (dl *DisplayContext) AddStyle(rect layout.Rectangle, style lipgloss.Style, z int) {
+	dl.AddEffect(StyleEffect{Rect: rect, Style: style, Z: z})
+}
+
 // AddInteraction adds an InteractionOp to the display context.
 func (dl *DisplayContext) AddInteraction(rect layout.Rectangle, msg tea.Msg, typ InteractionType, z int) {
 	dl.interactions = append(dl.interactions, interactionOp{
@@ -74,6 +74,9 @@ func TestEffectOp_AppliesWithoutPanic(t *testing.T) {
 		{"Fill", func(dl *DisplayContext, rect layout.Rectangle) {
 			dl.AddFill(rect, 'x', lipgloss.NewStyle(), 0)
 		}},
+		{"Style", func(dl *DisplayContext, rect layout.Rectangle) {
+			dl.AddStyle(rect, lipgloss.NewStyle().Reverse(true), 0)
+		}},
 	}
 
 	for _, tc := range tests {
@@ -116,6 +119,23 @@ func TestEffectOp_MultipleEffects(t *testing.T) {
 	}
 }
 
+func TestStyleEffect_KeepsUnsetColors(t *testing.T) {
+	dl := NewDisplayContext()
+	dl.AddDraw(layout.Rect(0, 0, 4, 1), lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("Text"), 0)
+	dl.AddStyle(layout.Rect(0, 0, 2, 1), lipgloss.NewStyle().Background(lipgloss.Color("4")).Bold(true), 0)
+
+	buf := uv.NewScreenBuffer(4, 1)
+	dl.Render(buf)
+
+	styled := buf.CellAt(0, 0)
+	if styled.Style.Fg == nil || styled.Style.Bg == nil || styled.Style.Attrs&uv.AttrBold == 0 {
+		t.Errorf("Expected foreground kept and background and bold applied, got: %+v", styled.Style)
+	}
+	if plain := buf.CellAt(2, 0); plain.Style.Bg != nil {
+		t.Errorf("Expected no background outside of the effect, got: %+v", plain.Style)
+	}
+}
+
 func TestEffectOp_EffectAfterDraw(t *testing.T) {
 	dl := NewDisplayContext()
 
@@ -68,6 +68,38 @@ func (e HighlightEffect) Apply(buf uv.Screen) {
 func (e HighlightEffect) GetZ() int                 { return e.Z }
 func (e HighlightEffect) GetRect() layout.Rectangle { return e.Rect }
 
+// StyleEffect applies the colors and attributes set on a style to the content,
+// leaving the rest of the cell style untouched.
+type StyleEffect struct {
+	Rect  layout.Rectangle
+	Style lipgloss.Style
+	Z     int
+}
+
+func (e StyleEffect) Apply(buf uv.Screen) {
+	style := lipglossToStyle(e.Style)
+	iterateCells(buf, e.Rect, func(cell *uv.Cell) *uv.Cell {
+		if cell == nil {
+			return nil
+		}
+		newCell := cell.Clone()
+		if style.Fg != nil {
+			newCell.Style.Fg = style.Fg
+		}
+		if style.Bg != nil {
+			newCell.Style.Bg = style.Bg
+		}
+		if style.Underline != uv.UnderlineNone {
+			newCell.Style.Underline = style.Underline
+		}
+		newCell.Style.Attrs |= style.Attrs
+		return newCell
+	})
+}
+
+func (e StyleEffect) GetZ() int                 { return e.Z }
+func (e StyleEffect) GetRect() layout.Rectangle { return e.Rect }
+
 type FillEffect struct {
 	Rect  layout.Rectangle
 	Char  rune
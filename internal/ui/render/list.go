@@ -48,6 +48,16 @@ type ListRenderer struct {
 	Z             int // Interaction z-index; default is 0.
 	FirstRowIndex int
 	LastRowIndex  int
+	// DragMsg makes items draggable when set. Pressing an item sends it
+	// instead of the click message.
+	DragMsg  ClickMessageFunc
+	rowRects []rowRect
+}
+
+// rowRect is where a visible item was rendered on the screen
+type rowRect struct {
+	index int
+	rect  layout.Rectangle
 }
 
 func NewListRenderer(scrollMsg tea.Msg) *ListRenderer {
@@ -104,6 +114,7 @@ func (r *ListRenderer) Render(
 	}
 
 	spans, _ := layoutAll(viewport, itemCount, measureAdapter)
+	r.rowRects = r.rowRects[:0]
 	if len(spans) > 0 {
 		r.FirstRowIndex = spans[0].Index
 		r.LastRowIndex = spans[len(spans)-1].Index
@@ -121,13 +132,31 @@ func (r *ListRenderer) Render(
 			render(itemDL, span.Index, rect)
 		})
 		idx := span.Index
+		r.rowRects = append(r.rowRects, rowRect{index: idx, rect: span.Rect})
 		dl.AddInteractionFn(span.Rect, func(mouseMsg tea.MouseMsg) tea.Msg {
 			return clickMsg(idx, mouseMsg.Mouse())
 		}, InteractionClick, r.Z)
+		if r.DragMsg != nil {
+			dragMsg := r.DragMsg
+			dl.AddInteractionFn(span.Rect, func(mouseMsg tea.MouseMsg) tea.Msg {
+				return dragMsg(idx, mouseMsg.Mouse())
+			}, InteractionDrag, r.Z)
+		}
 	}
 
 }
 
+// IndexAt returns the index of the item rendered at the given screen
+// position by the last Render, or -1 if there is none.
+func (r *ListRenderer) IndexAt(x, y int) int {
+	for _, row := range r.rowRects {
+		if x >= row.rect.Min.X && x < row.rect.Max.X && y >= row.rect.Min.Y && y < row.rect.Max.Y {
+			return row.index
+		}
+	}
+	return -1
+}
+
 func (r *ListRenderer) ensureCursorVisible(
 	cursor int,
 	itemCount int,
@@ -124,3 +124,27 @@ func TestListRendererRenderUsesVisibleHeightForBottomClippedItem(t *testing.T) {
 
 	assert.Equal(t, 3, seenHeight)
 }
+
+func TestListRendererDragMsgTakesPrecedenceOverClick(t *testing.T) {
+	type pressedMsg struct{ index int }
+	renderer := NewListRenderer(nil)
+	renderer.DragMsg = func(index int, _ tea.Mouse) tea.Msg { return pressedMsg{index: index} }
+
+	dl := NewDisplayContext()
+	renderer.Render(
+		dl,
+		layout.NewBox(layout.Rect(0, 0, 8, 6)),
+		3,
+		-1,
+		false,
+		func(_ int) int { return 2 },
+		func(_ *DisplayContext, _ int, _ layout.Rectangle) {},
+		func(index int, _ tea.Mouse) tea.Msg { return index },
+	)
+
+	msg, handled := dl.ProcessMouseEvent(tea.MouseClickMsg{X: 1, Y: 3, Button: tea.MouseLeft})
+	assert.True(t, handled)
+	assert.Equal(t, pressedMsg{index: 1}, msg)
+	assert.Equal(t, 2, renderer.IndexAt(0, 5))
+	assert.Equal(t, -1, renderer.IndexAt(0, 6))
+}
@@ -1,6 +1,7 @@
 package revisions
 
 import (
+	"slices"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
@@ -15,12 +16,18 @@ import (
 
 // DisplayContextRenderer renders the revisions list using the DisplayContext approach
 type DisplayContextRenderer struct {
-	listRenderer  *render.ListRenderer
-	selections    map[string]bool
-	textStyle     lipgloss.Style
-	dimmedStyle   lipgloss.Style
-	selectedStyle lipgloss.Style
-	matchedStyle  lipgloss.Style
+	listRenderer     *render.ListRenderer
+	selections       map[string]bool
+	workingCopies    map[string][]string
+	upstreamMoved    map[string][]string
+	bookmarkSyncs    map[string][]jj.BookmarkSync
+	textStyle        lipgloss.Style
+	dimmedStyle      lipgloss.Style
+	selectedStyle    lipgloss.Style
+	matchedStyle     lipgloss.Style
+	workingCopyStyle lipgloss.Style
+	upstreamStyle    lipgloss.Style
+	syncStyle        lipgloss.Style
 }
 
 // itemRenderer is a helper for rendering individual revision items
@@ -86,8 +93,16 @@ func (ir *itemRenderer) renderSegmentForLine(tb *render.TextBuilder, segment *sc
 
 // NewDisplayContextRenderer creates a new DisplayContext-based renderer
 func NewDisplayContextRenderer() *DisplayContextRenderer {
+	listRenderer := render.NewListRenderer(ViewportScrollMsg{})
+	listRenderer.DragMsg = func(index int, mouse tea.Mouse) tea.Msg {
+		return ItemPressedMsg{
+			Index: index,
+			Ctrl:  mouse.Mod&tea.ModCtrl != 0,
+			Alt:   mouse.Mod&tea.ModAlt != 0,
+		}
+	}
 	return &DisplayContextRenderer{
-		listRenderer: render.NewListRenderer(ViewportScrollMsg{}),
+		listRenderer: listRenderer,
 	}
 }
 
@@ -96,6 +111,51 @@ func (r *DisplayContextRenderer) SetSelections(selections map[string]bool) {
 	r.selections = selections
 }
 
+// SetWorkingCopies sets the names of the other workspaces whose working copy
+// is each commit, by commit id
+func (r *DisplayContextRenderer) SetWorkingCopies(workingCopies map[string][]string) {
+	r.workingCopies = workingCopies
+}
+
+// SetUpstreamMoved sets the names of the tracked bookmarks that moved upstream
+// to each commit, by full commit id
+func (r *DisplayContextRenderer) SetUpstreamMoved(upstreamMoved map[string][]string) {
+	r.upstreamMoved = upstreamMoved
+}
+
+// upstreamMovedTo returns the names of the tracked bookmarks that moved
+// upstream to the commit, whose id is shortened in the log
+func (r *DisplayContextRenderer) upstreamMovedTo(commitId string) []string {
+	if commitId == "" {
+		return nil
+	}
+	var names []string
+	for id, bookmarks := range r.upstreamMoved {
+		if strings.HasPrefix(id, commitId) {
+			names = append(names, bookmarks...)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetBookmarkSyncs sets how far the local bookmarks drifted from their tracked
+// remotes, by bookmark name
+func (r *DisplayContextRenderer) SetBookmarkSyncs(bookmarkSyncs map[string][]jj.BookmarkSync) {
+	r.bookmarkSyncs = bookmarkSyncs
+}
+
+// bookmarkSyncLabel returns the ahead/behind counts to show after text when it
+// is the label of a local bookmark pointing at the commit. jj marks the labels
+// of bookmarks that differ from their remotes with a trailing *.
+func (r *DisplayContextRenderer) bookmarkSyncLabel(commitId string, text string) string {
+	syncs := r.bookmarkSyncs[strings.TrimSuffix(text, "*")]
+	if len(syncs) == 0 || commitId == "" || !strings.HasPrefix(commitId, syncs[0].CommitId) {
+		return ""
+	}
+	return jj.SyncLabel(syncs)
+}
+
 // Render renders the revisions list to a DisplayContext
 func (r *DisplayContextRenderer) Render(
 	dl *render.DisplayContext,
@@ -564,6 +624,12 @@ func (ir *itemRenderer) renderLine(tb *render.TextBuilder, line *parser.GraphRow
 		if ir.isChecked {
 			tb.Styled("✓ ", ir.renderer.selectedStyle)
 		}
+		for _, name := range ir.renderer.workingCopies[ir.row.Commit.CommitId] {
+			tb.Styled(name+"@", ir.renderer.workingCopyStyle).Styled(" ", ir.renderer.textStyle)
+		}
+		for _, name := range ir.renderer.upstreamMovedTo(ir.row.Commit.CommitId) {
+			tb.Styled("↓"+name, ir.renderer.upstreamStyle).Styled(" ", ir.renderer.textStyle)
+		}
 		if ir.op != nil {
 			beforeChangeID := ir.op.Render(ir.row.Commit, operations.RenderBeforeChangeId)
 			if beforeChangeID != "" {
@@ -589,15 +655,21 @@ func (ir *itemRenderer) renderLine(tb *render.TextBuilder, line *parser.GraphRow
 			beforeCommitIDRendered = true
 		}
 
-		style := ir.getSegmentStyleForLine(*segment, lineIsHighlightable)
+		rendered := ""
 		if ir.segmentRenderer != nil {
-			rendered := ir.segmentRenderer.RenderSegment(style, segment, ir.row)
-			if rendered != "" {
-				tb.Write(rendered)
-				continue
+			style := ir.getSegmentStyleForLine(*segment, lineIsHighlightable)
+			rendered = ir.segmentRenderer.RenderSegment(style, segment, ir.row)
+		}
+		if rendered != "" {
+			tb.Write(rendered)
+		} else {
+			ir.renderSegmentForLine(tb, segment, lineIsHighlightable)
+		}
+		if line.Flags&parser.Revision == parser.Revision {
+			if label := ir.renderer.bookmarkSyncLabel(ir.row.Commit.CommitId, segment.Text); label != "" {
+				tb.Styled(" "+label, ir.renderer.syncStyle)
 			}
 		}
-		ir.renderSegmentForLine(tb, segment, lineIsHighlightable)
 	}
 	if beforeCommitID != "" && !beforeCommitIDRendered {
 		// Add a space before blinking cursor for aesthetics
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	uv "github.com/charmbracelet/ultraviolet"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/parser"
@@ -110,3 +111,33 @@ func TestDisplayContextRenderer_SingleRowDescriptionOverlay(t *testing.T) {
 	assert.Contains(t, out, overlayContent,
 		"describe overlay should render for single-line commits")
 }
+
+func TestDisplayContextRenderer_MarksWorkingCopiesOfOtherWorkspaces(t *testing.T) {
+	f, err := os.Open("testdata/single-line-log.log")
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	rows := parser.ParseRows(f)
+	require.NotEmpty(t, rows)
+
+	r := NewDisplayContextRenderer()
+	r.SetWorkingCopies(map[string][]string{rows[0].Commit.CommitId: {"docs", "ci"}})
+
+	width, height := 100, 5
+	dl := render.NewDisplayContext()
+	r.Render(dl, rows[:1], 0, layout.NewBox(layout.Rect(0, 0, width, height)), nil, nil, false, "", true)
+
+	screen := uv.NewScreenBuffer(width, height)
+	dl.Render(screen)
+	assert.Contains(t, ansi.Strip(screen.Render()), "docs@ ci@ "+rows[0].Commit.ChangeId)
+}
+
+func TestDisplayContextRenderer_BookmarkSyncLabel(t *testing.T) {
+	r := NewDisplayContextRenderer()
+	r.SetBookmarkSyncs(map[string][]jj.BookmarkSync{
+		"main": {{Name: "main", Remote: "origin", CommitId: "a1", Ahead: 2, Behind: 1}},
+	})
+	assert.Equal(t, "↑2↓1", r.bookmarkSyncLabel("a1b2c3", "main*"))
+	assert.Empty(t, r.bookmarkSyncLabel("ffff", "main*"), "label of another commit")
+	assert.Empty(t, r.bookmarkSyncLabel("a1b2c3", "docs"))
+}
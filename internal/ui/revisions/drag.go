@@ -0,0 +1,108 @@
+package revisions
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// ItemPressedMsg is sent when the mouse is pressed on a revision. It selects
+// the revision like a click, and starts dragging it when no modifier is held.
+type ItemPressedMsg struct {
+	Index int
+	Ctrl  bool
+	Alt   bool
+}
+
+// drag is a revision being dragged onto another one
+type drag struct {
+	from   int
+	over   int
+	x, y   int
+	target intents.ModeTarget
+}
+
+var dropTargetNames = map[intents.ModeTarget]string{
+	intents.ModeTargetDestination: "onto",
+	intents.ModeTargetAfter:       "after",
+	intents.ModeTargetBefore:      "before",
+}
+
+// dropTarget returns where the dragged revision goes for the held modifiers:
+// onto the revision under the pointer by default, after it with alt and
+// before it with ctrl.
+func dropTarget(mod tea.KeyMod) intents.ModeTarget {
+	switch {
+	case mod&tea.ModAlt != 0:
+		return intents.ModeTargetAfter
+	case mod&tea.ModCtrl != 0:
+		return intents.ModeTargetBefore
+	}
+	return intents.ModeTargetDestination
+}
+
+func (m *Model) startDrag(msg ItemPressedMsg) tea.Cmd {
+	cmd := m.internalUpdate(ItemClickedMsg(msg))
+	if !msg.Ctrl && !msg.Alt && m.InNormalMode() && m.commitAt(msg.Index) != nil {
+		m.drag = &drag{from: msg.Index, over: msg.Index}
+	}
+	return cmd
+}
+
+func (m *Model) dragTo(mouse tea.Mouse) {
+	m.drag.x, m.drag.y = mouse.X, mouse.Y
+	m.drag.over = m.displayContextRenderer.listRenderer.IndexAt(mouse.X, mouse.Y)
+	m.drag.target = dropTarget(mouse.Mod)
+}
+
+// drop opens the rebase operation for the dragged revision, or all checked
+// revisions if it is one of them, with the revision under the pointer as
+// the target.
+func (m *Model) drop(mouse tea.Mouse) tea.Cmd {
+	m.dragTo(mouse)
+	d := m.drag
+	m.drag = nil
+	if d.over == d.from || m.commitAt(d.over) == nil {
+		return nil
+	}
+	from := m.commitAt(d.from)
+	selected := jj.NewSelectedRevisions(from)
+	if m.context.GetSelectedRevisions()[from.GetChangeId()] {
+		selected = m.SelectedRevisions()
+	}
+	m.SetCursor(d.over)
+	return tea.Batch(
+		m.startRebase(intents.OpenRebase{Selected: selected, Source: intents.RebaseSourceRevision, Target: d.target}),
+		m.updateSelection(),
+	)
+}
+
+func (m *Model) commitAt(index int) *jj.Commit {
+	if index < 0 || index >= len(m.rows) {
+		return nil
+	}
+	return m.rows[index].Commit
+}
+
+// renderDragGhost draws the dragged revision next to the pointer along with
+// where it would be dropped
+func (m *Model) renderDragGhost(dl *render.DisplayContext, box layout.Box) {
+	d := m.drag
+	if d == nil || d.over == d.from || m.commitAt(d.from) == nil || m.commitAt(d.over) == nil {
+		return
+	}
+	ghost := fmt.Sprintf(" %s %s %s ", m.commitAt(d.from).GetChangeId(), dropTargetNames[d.target], m.commitAt(d.over).GetChangeId())
+	hint := "alt: after, ctrl: before "
+	width := ansi.StringWidth(ghost) + ansi.StringWidth(hint)
+	x := max(min(d.x+2, box.R.Max.X-width), box.R.Min.X)
+	dl.Text(x, d.y, render.ZOverlay).
+		Styled(ghost, common.DefaultPalette.Get("revisions drag")).
+		Styled(hint, common.DefaultPalette.Get("revisions drag hint")).
+		Done()
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations/ace_jump"
+	"github.com/idursun/jjui/internal/ui/operations/compare"
 	"github.com/idursun/jjui/internal/ui/operations/duplicate"
 	"github.com/idursun/jjui/internal/ui/operations/revert"
 	"github.com/idursun/jjui/internal/ui/operations/set_parents"
@@ -43,6 +44,7 @@ import (
 	"github.com/idursun/jjui/internal/ui/operations/evolog"
 	"github.com/idursun/jjui/internal/ui/operations/rebase"
 	"github.com/idursun/jjui/internal/ui/operations/squash"
+	"github.com/idursun/jjui/internal/ui/operations/tag"
 )
 
 var (
@@ -53,24 +55,29 @@ var (
 )
 
 type Model struct {
-	rows                   []parser.Row
-	tag                    atomic.Uint64
-	revisionToSelect       string
-	offScreenRows          []parser.Row
-	streamer               *graph.GraphStreamer
-	hasMore                bool
-	baseOp                 operations.Operation
-	layers                 []common.ImmediateModel
-	cursor                 int
-	context                *appContext.MainContext
-	output                 string
-	err                    error
-	quickSearch            string
-	previousOpLogId        string
+	rows             []parser.Row
+	tag              atomic.Uint64
+	revisionToSelect string
+	offScreenRows    []parser.Row
+	streamer         *graph.GraphStreamer
+	hasMore          bool
+	baseOp           operations.Operation
+	layers           []common.ImmediateModel
+	cursor           int
+	context          *appContext.MainContext
+	output           string
+	err              error
+	quickSearch      string
+	previousOpLogId  string
+	// workingCopiesOpId is the operation the working copies were loaded at
+	workingCopiesOpId string
+	// bookmarkSyncsOpId is the operation the bookmark syncs were counted at
+	bookmarkSyncsOpId      string
 	isLoading              bool
 	displayContextRenderer *DisplayContextRenderer
 	ensureCursorView       bool
 	requestInFlight        bool
+	drag                   *drag
 }
 
 type revisionsMsg struct {
@@ -105,6 +112,20 @@ type updateRevisionsMsg struct {
 	selectedRevision string
 }
 
+// workingCopiesMsg carries the names of the other workspaces whose working
+// copy is each commit, by commit id, as of the operation opId
+type workingCopiesMsg struct {
+	opId          string
+	workingCopies map[string][]string
+}
+
+// bookmarkSyncsMsg carries how far the local bookmarks drifted from their
+// tracked remotes, by bookmark name, as of the operation opId
+type bookmarkSyncsMsg struct {
+	opId          string
+	bookmarkSyncs map[string][]jj.BookmarkSync
+}
+
 type streamingReadyMsg struct {
 	streamer         *graph.GraphStreamer
 	selectedRevision string
@@ -265,6 +286,12 @@ func (m *Model) InNormalMode() bool {
 	return isDefault && len(m.layers) == 0
 }
 
+// SetUpstreamMoved marks the commits that tracked bookmarks moved to upstream,
+// keyed by full commit id, replacing the previous marks
+func (m *Model) SetUpstreamMoved(bookmarks map[string][]string) {
+	m.displayContextRenderer.SetUpstreamMoved(bookmarks)
+}
+
 func (m *Model) HasQuickSearch() bool {
 	return m.quickSearch != ""
 }
@@ -386,6 +413,17 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 			m.SetCursor(msg.Index)
 		}
 		return m.updateSelection()
+	case ItemPressedMsg:
+		return m.startDrag(msg)
+	case tea.MouseMotionMsg:
+		if m.drag != nil {
+			m.dragTo(msg.Mouse())
+			return nil
+		}
+	case tea.MouseReleaseMsg:
+		if m.drag != nil {
+			return m.drop(msg.Mouse())
+		}
 	case ViewportScrollMsg:
 		if msg.Horizontal {
 			return nil
@@ -445,6 +483,14 @@ func (m *Model) internalUpdate(msg tea.Msg) tea.Cmd {
 			KeepSelections:   msg.KeepSelections,
 			SelectedRevision: msg.SelectedRevision,
 		}), m.activeModel().Update(msg))
+	case workingCopiesMsg:
+		m.workingCopiesOpId = msg.opId
+		m.displayContextRenderer.SetWorkingCopies(msg.workingCopies)
+		return nil
+	case bookmarkSyncsMsg:
+		m.bookmarkSyncsOpId = msg.opId
+		m.displayContextRenderer.SetBookmarkSyncs(msg.bookmarkSyncs)
+		return nil
 	case updateRevisionsMsg:
 		m.isLoading = false
 		m.updateGraphRows(msg.rows, msg.selectedRevision)
@@ -640,10 +686,14 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return m.startRevert(intent), true
 	case intents.OpenDuplicate:
 		return m.startDuplicate(intent), true
+	case intents.OpenCompare:
+		return m.startCompare(intent), true
 	case intents.OpenSetParents:
 		return m.startSetParents(intent), true
 	case intents.OpenSetBookmark:
 		return m.startBookmarkSet(intent), true
+	case intents.OpenSetTag:
+		return m.startTagSet(), true
 	case intents.RevisionsToggleSelect:
 		commit := m.SelectedRevision()
 		if commit == nil {
@@ -655,6 +705,9 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		return nil, true
 	case intents.Navigate:
 		return m.navigate(intent), true
+	case intents.JumpToRevision:
+		m.resetOperations()
+		return m.navigate(intents.Navigate{ChangeID: intent.ChangeID, FallbackID: intent.FallbackID}), true
 	case intents.Describe:
 		return m.startDescribe(intent), true
 	case intents.OpenEvolog:
@@ -689,6 +742,14 @@ func (m *Model) startBookmarkSet(intent intents.OpenSetBookmark) tea.Cmd {
 	return m.setBaseOperation(bookmark.NewSetBookmarkOperation(m.context, rev.GetChangeId(), intent.Value))
 }
 
+func (m *Model) startTagSet() tea.Cmd {
+	rev := m.SelectedRevision()
+	if rev == nil {
+		return nil
+	}
+	return m.setBaseOperation(tag.NewSetTagOperation(m.context, rev.GetChangeId()))
+}
+
 func (m *Model) refresh(intent intents.Refresh) tea.Cmd {
 	if !intent.KeepSelections {
 		m.context.ClearCheckedItems(reflect.TypeFor[appContext.SelectedRevision]())
@@ -696,9 +757,54 @@ func (m *Model) refresh(intent intents.Refresh) tea.Cmd {
 	m.isLoading = true
 	if config.Current.Revisions.LogBatching {
 		currentTag := m.tag.Add(1)
-		return m.loadStreaming(m.context.CurrentRevset, intent.SelectedRevision, currentTag)
+		return tea.Batch(m.loadStreaming(m.context.CurrentRevset, intent.SelectedRevision, currentTag), m.loadWorkingCopies(m.workingCopiesOpId), m.loadBookmarkSyncs(m.bookmarkSyncsOpId))
+	}
+	return tea.Batch(m.load(m.context.CurrentRevset, intent.SelectedRevision), m.loadWorkingCopies(m.workingCopiesOpId), m.loadBookmarkSyncs(m.bookmarkSyncsOpId))
+}
+
+// loadWorkingCopies finds the working-copy commits of the other workspaces,
+// which jj does not mark in the graph like it does @. They only move with a new
+// operation, so nothing is loaded while the repo is still at previousOpId.
+func (m *Model) loadWorkingCopies(previousOpId string) tea.Cmd {
+	return func() tea.Msg {
+		opId, err := m.context.RunCommandImmediate(jj.OpLogId(false))
+		if err != nil || (previousOpId != "" && string(opId) == previousOpId) {
+			return nil
+		}
+		output, err := m.context.RunCommandImmediate(jj.WorkspaceList())
+		if err != nil {
+			return workingCopiesMsg{}
+		}
+		workingCopies := map[string][]string{}
+		for _, w := range jj.ParseWorkspaceListOutput(string(output)) {
+			if !w.Current {
+				workingCopies[w.CommitId] = append(workingCopies[w.CommitId], w.Name)
+			}
+		}
+		return workingCopiesMsg{opId: string(opId), workingCopies: workingCopies}
+	}
+}
+
+// loadBookmarkSyncs counts the commits the local bookmarks are ahead and
+// behind of their tracked remotes, to annotate their labels in the graph. The
+// counts only change with a new operation, so nothing is counted while the
+// repo is still at previousOpId.
+func (m *Model) loadBookmarkSyncs(previousOpId string) tea.Cmd {
+	return func() tea.Msg {
+		opId, err := m.context.RunCommandImmediate(jj.OpLogId(false))
+		if err != nil || (previousOpId != "" && string(opId) == previousOpId) {
+			return nil
+		}
+		output, err := m.context.RunCommandImmediate(jj.BookmarkListAll())
+		if err != nil {
+			return bookmarkSyncsMsg{}
+		}
+		bookmarkSyncs := map[string][]jj.BookmarkSync{}
+		for _, s := range appContext.LoadBookmarkSyncs(m.context, jj.ParseBookmarkListOutput(string(output))) {
+			bookmarkSyncs[s.Name] = append(bookmarkSyncs[s.Name], s)
+		}
+		return bookmarkSyncsMsg{opId: string(opId), bookmarkSyncs: bookmarkSyncs}
 	}
-	return m.load(m.context.CurrentRevset, intent.SelectedRevision)
 }
 
 func (m *Model) openDetails(_ intents.OpenDetails) tea.Cmd {
@@ -785,6 +891,28 @@ func (m *Model) startDuplicate(intent intents.OpenDuplicate) tea.Cmd {
 	return m.setBaseOperation(duplicate.NewOperation(m.context, selected, intents.ModeTargetDestination))
 }
 
+// startCompare compares two checked revisions right away, the lower one in
+// the graph being the base. Otherwise the selected revision is compared with
+// a base picked afterwards.
+func (m *Model) startCompare(intent intents.OpenCompare) tea.Cmd {
+	selected := intent.Selected
+	if len(selected.Revisions) == 0 {
+		selected = m.SelectedRevisions()
+	}
+	switch len(selected.Revisions) {
+	case 0:
+		return nil
+	case 1:
+		return m.setBaseOperation(compare.NewOperation(m.context, selected.Revisions[0]))
+	case 2:
+		return intents.Invoke(intents.ShowCompare{
+			From: selected.Revisions[1].GetChangeId(),
+			To:   selected.Revisions[0].GetChangeId(),
+		})
+	}
+	return intents.Invoke(intents.AddMessage{Text: "check exactly two revisions to compare them"})
+}
+
 func (m *Model) startSetParents(intent intents.OpenSetParents) tea.Cmd {
 	commit := intent.Selected
 	if commit == nil {
@@ -1078,6 +1206,9 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 	m.displayContextRenderer.dimmedStyle = dimmedStyle
 	m.displayContextRenderer.selectedStyle = selectedStyle
 	m.displayContextRenderer.matchedStyle = matchedStyle
+	m.displayContextRenderer.workingCopyStyle = common.DefaultPalette.Get("revisions working_copy")
+	m.displayContextRenderer.upstreamStyle = common.DefaultPalette.Get("revisions upstream_moved")
+	m.displayContextRenderer.syncStyle = common.DefaultPalette.Get("revisions bookmark_sync")
 
 	if len(m.rows) == 0 {
 		content := ""
@@ -1114,10 +1245,15 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 		m.ensureCursorView,
 	)
 
+	if predicts, ok := renderOp.(operations.PredictsGraph); ok {
+		predicts.RenderPredictedGraph(dl, box)
+	}
+
 	// Render transient layers over the base operation.
 	for _, layer := range m.layers {
 		layer.ViewRect(dl, box)
 	}
+	m.renderDragGhost(dl, box)
 
 	// Reset the flag after ensuring cursor is visible
 	m.ensureCursorView = false
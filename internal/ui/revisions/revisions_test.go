@@ -11,9 +11,11 @@ import (
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/operations"
+	"github.com/idursun/jjui/internal/ui/operations/rebase"
 	"github.com/idursun/jjui/internal/ui/render"
 	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModel_highlightChanges(t *testing.T) {
@@ -133,10 +135,17 @@ func TestModel_OperationIntents(t *testing.T) {
 			intent:   intents.OpenDuplicate{},
 			expected: "duplicate",
 		},
+		{
+			name:     "compare",
+			intent:   intents.OpenCompare{},
+			expected: "<< to >>",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+			commandRunner := test.NewTestCommandRunner(t)
+			commandRunner.Expect(jj.GraphLog("", 0))
+			ctx := test.NewTestContext(commandRunner)
 
 			model := New(ctx)
 			model.updateGraphRows(rows, "a")
@@ -148,8 +157,24 @@ func TestModel_OperationIntents(t *testing.T) {
 	}
 }
 
+func TestModel_CompareTwoCheckedRevisions(t *testing.T) {
+	ctx := test.NewTestContext(test.NewTestCommandRunner(t))
+	model := New(ctx)
+	model.updateGraphRows(rows, "a")
+	for _, row := range rows {
+		ctx.AddCheckedItem(common.SelectedRevision{ChangeId: row.Commit.ChangeId, CommitId: row.Commit.CommitId})
+	}
+
+	cmd := model.Update(intents.OpenCompare{})
+	assert.True(t, model.InNormalMode())
+	if assert.NotNil(t, cmd) {
+		assert.Equal(t, intents.ShowCompare{From: "b", To: "a"}, cmd())
+	}
+}
+
 func TestModel_ForwardsOperationIntentToFocusedOperation(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GraphLog("", 0))
 	commandRunner.Expect(jj.BookmarkListAll())
 	commandRunner.Expect(jj.TagList())
 	defer commandRunner.Verify()
@@ -168,6 +193,7 @@ func TestModel_ForwardsOperationIntentToFocusedOperation(t *testing.T) {
 
 func TestModel_TargetPickerCancelClosesEditing(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GraphLog("", 0))
 	commandRunner.Expect(jj.BookmarkListAll())
 	commandRunner.Expect(jj.TagList())
 	defer commandRunner.Verify()
@@ -183,3 +209,72 @@ func TestModel_TargetPickerCancelClosesEditing(t *testing.T) {
 	test.SimulateModel(model, model.Update(intents.TargetPickerCancel{}))
 	assert.False(t, model.IsEditing(), "target picker cancel should exit editing mode")
 }
+
+func TestModel_DragAndDropOpensRebase(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.GraphLog("", 0))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	model.updateGraphRows(rows, "a")
+	_ = test.RenderImmediate(model, 100, 10)
+
+	test.SimulateModel(model, model.Update(ItemPressedMsg{Index: 1}))
+	model.Update(tea.MouseMotionMsg{X: 0, Y: 0, Mod: tea.ModAlt})
+	assert.Contains(t, test.RenderImmediate(model, 100, 10), "b after a")
+
+	test.SimulateModel(model, model.Update(tea.MouseReleaseMsg{X: 0, Y: 0, Mod: tea.ModAlt}))
+	op, ok := model.baseOperation().(*rebase.Operation)
+	if assert.True(t, ok, "dropping should open rebase") {
+		assert.Equal(t, []string{"b"}, op.From.GetIds())
+		assert.Equal(t, "a", op.To.GetChangeId())
+		assert.Equal(t, intents.ModeTargetAfter, op.Target)
+	}
+}
+
+func TestModel_PressAndReleaseOnSameRevisionOnlySelectsIt(t *testing.T) {
+	model := New(test.NewTestContext(test.NewTestCommandRunner(t)))
+	model.updateGraphRows(rows, "a")
+	_ = test.RenderImmediate(model, 100, 10)
+
+	test.SimulateModel(model, model.Update(ItemPressedMsg{Index: 1}))
+	test.SimulateModel(model, model.Update(tea.MouseReleaseMsg{X: 0, Y: 1}))
+	assert.True(t, model.InNormalMode())
+	assert.Equal(t, 1, model.Cursor())
+}
+
+func TestLoadWorkingCopies_SkipsUnchangedOperation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.WorkspaceList()).SetOutput([]byte("default\t@\tqpvuntsm\t2c8e1a9b\t\nsecond\t\tkmkuslsw\t7d3f0e21\t\n"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("0123abcd"))
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	msg, ok := model.loadWorkingCopies(model.workingCopiesOpId)().(workingCopiesMsg)
+	require.True(t, ok)
+	assert.Equal(t, map[string][]string{"7d3f0e21": {"second"}}, msg.workingCopies)
+	model.Update(msg)
+
+	assert.Nil(t, model.loadWorkingCopies(model.workingCopiesOpId)(), "expected no workspace list at the same operation")
+}
+
+func TestLoadBookmarkSyncs_SkipsUnchangedOperation(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.BookmarkListAll())
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("0123abcd"))
+	commandRunner.Expect(jj.OpLogId(false)).SetOutput([]byte("4567cdef"))
+	commandRunner.Expect(jj.BookmarkListAll())
+	defer commandRunner.Verify()
+
+	model := New(test.NewTestContext(commandRunner))
+	msg, ok := model.loadBookmarkSyncs(model.bookmarkSyncsOpId)().(bookmarkSyncsMsg)
+	require.True(t, ok)
+	model.Update(msg)
+	assert.Equal(t, "0123abcd", model.bookmarkSyncsOpId)
+
+	assert.Nil(t, model.loadBookmarkSyncs(model.bookmarkSyncsOpId)(), "expected no bookmark list at the same operation")
+	_, ok = model.loadBookmarkSyncs(model.bookmarkSyncsOpId)().(bookmarkSyncsMsg)
+	assert.True(t, ok, "expected the bookmarks to be counted again after a new operation")
+}
@@ -0,0 +1,112 @@
+package sparse
+
+import (
+	"slices"
+	"strings"
+)
+
+// rootPattern is the pattern jj uses for the whole repository
+const rootPattern = "."
+
+type patternState int
+
+const (
+	patternKept patternState = iota
+	patternAdded
+	patternRemoved
+)
+
+// pattern is a sparse pattern along with how applying the edits changes it
+type pattern struct {
+	path  string
+	state patternState
+}
+
+// parsePatterns parses the output of jj.SparseList
+func parsePatterns(output string) []*pattern {
+	var patterns []*pattern
+	for line := range strings.SplitSeq(output, "\n") {
+		if path := strings.TrimSpace(line); path != "" {
+			patterns = append(patterns, &pattern{path: path})
+		}
+	}
+	return patterns
+}
+
+// effective returns the patterns the working copy has once the edits are
+// applied, or before if they are not
+func effective(patterns []*pattern, applied bool) []string {
+	var paths []string
+	for _, p := range patterns {
+		switch {
+		case p.state == patternKept,
+			applied && p.state == patternAdded,
+			!applied && p.state == patternRemoved:
+			paths = append(paths, p.path)
+		}
+	}
+	return paths
+}
+
+// matches returns whether file is in the working copy with patterns, each
+// pattern including the file or directory at its path
+func matches(patterns []string, file string) bool {
+	for _, p := range patterns {
+		p = strings.TrimSuffix(p, "/")
+		if p == rootPattern || p == "" || file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// changes counts the files that enter and leave the working copy when the
+// patterns change from before to after
+func changes(files []string, before []string, after []string) (entering int, leaving int) {
+	for _, file := range files {
+		was, is := matches(before, file), matches(after, file)
+		switch {
+		case is && !was:
+			entering++
+		case was && !is:
+			leaving++
+		}
+	}
+	return entering, leaving
+}
+
+// add adds path to the patterns, undoing its removal if it was removed
+func add(patterns []*pattern, path string) []*pattern {
+	path = strings.TrimSuffix(path, "/")
+	if i := slices.IndexFunc(patterns, func(p *pattern) bool { return p.path == path }); i >= 0 {
+		if patterns[i].state == patternRemoved {
+			patterns[i].state = patternKept
+		}
+		return patterns
+	}
+	return append(patterns, &pattern{path: path, state: patternAdded})
+}
+
+// remove toggles the removal of the pattern at index, dropping it if it was
+// only added
+func remove(patterns []*pattern, index int) []*pattern {
+	switch p := patterns[index]; p.state {
+	case patternAdded:
+		return slices.Delete(patterns, index, index+1)
+	case patternRemoved:
+		p.state = patternKept
+	default:
+		p.state = patternRemoved
+	}
+	return patterns
+}
+
+// reset removes all patterns but the root one, which includes every file
+func reset(patterns []*pattern) []*pattern {
+	for i := len(patterns) - 1; i >= 0; i-- {
+		if patterns[i].path != rootPattern && patterns[i].state != patternRemoved {
+			patterns = remove(patterns, i)
+		}
+	}
+	return add(patterns, rootPattern)
+}
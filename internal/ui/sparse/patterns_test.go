@@ -0,0 +1,48 @@
+package sparse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var files = []string{"docs/index.md", "src/app/main.go", "src/lib/util.go", "README.md"}
+
+func TestChanges_CountsFilesEnteringAndLeaving(t *testing.T) {
+	entering, leaving := changes(files, []string{"docs"}, []string{"src/app", "README.md"})
+	assert.Equal(t, 2, entering)
+	assert.Equal(t, 1, leaving)
+}
+
+func TestChanges_RootPatternIncludesEverything(t *testing.T) {
+	entering, leaving := changes(files, []string{"src"}, []string{"."})
+	assert.Equal(t, 2, entering)
+	assert.Equal(t, 0, leaving)
+}
+
+func TestMatches_DoesNotMatchSiblingWithSamePrefix(t *testing.T) {
+	assert.False(t, matches([]string{"src/app"}, "src/application.go"))
+	assert.True(t, matches([]string{"src/app/"}, "src/app/main.go"))
+}
+
+func TestRemove_DropsAddedAndTogglesExisting(t *testing.T) {
+	patterns := []*pattern{{path: "docs"}}
+	patterns = add(patterns, "src/")
+	assert.Equal(t, []string{"docs", "src"}, effective(patterns, true))
+
+	patterns = remove(patterns, 1)
+	assert.Len(t, patterns, 1)
+
+	patterns = remove(patterns, 0)
+	assert.Empty(t, effective(patterns, true))
+	assert.Equal(t, []string{"docs"}, effective(patterns, false))
+
+	patterns = remove(patterns, 0)
+	assert.Equal(t, []string{"docs"}, effective(patterns, true))
+}
+
+func TestReset_LeavesOnlyRootPattern(t *testing.T) {
+	patterns := reset([]*pattern{{path: "docs"}, {path: "src"}})
+	assert.Equal(t, []string{"."}, effective(patterns, true))
+	assert.Equal(t, []string{"docs", "src"}, effective(patterns, false))
+}
@@ -0,0 +1,403 @@
+package sparse
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/fuzzy_files"
+	"github.com/idursun/jjui/internal/ui/fuzzy_search"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+	"github.com/sahilm/fuzzy"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ common.Editable = (*Model)(nil)
+
+// maxMatches is the most directories the picker lists
+const maxMatches = 100
+
+type loadedMsg struct {
+	patterns []*pattern
+	files    []string
+	err      error
+}
+
+type patternClickMsg struct {
+	Index int
+}
+
+type patternScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m patternScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+// directories are the directories of the working-copy commit the picker
+// searches
+type directories []string
+
+func (d directories) Len() int {
+	return len(d)
+}
+
+func (d directories) String(i int) string {
+	return d[i]
+}
+
+// Model edits the sparse patterns of the working copy, showing how many
+// files the edits bring into or take out of it before they are applied.
+type Model struct {
+	context             *context.MainContext
+	patterns            []*pattern
+	files               []string
+	directories         directories
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+	picking             bool
+	input               textinput.Model
+	matches             fuzzy.Matches
+	pickerCursor        int
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	if m.picking {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeSparsePicker,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeSparse,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) IsEditing() bool {
+	return m.picking
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.SparseList())
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	files, err := m.context.RunCommandImmediate(jj.FilesInRevision(&jj.Commit{CommitId: "@"}))
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	var paths []string
+	for line := range strings.SplitSeq(string(files), "\n") {
+		if line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return loadedMsg{patterns: parsePatterns(string(output)), files: paths}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.patterns = msg.patterns
+		m.files = msg.files
+		m.directories = nil
+		for _, entry := range fuzzy_files.BuildPathEntries([]byte(strings.Join(msg.files, "\n"))) {
+			if strings.HasSuffix(entry, "/") {
+				m.directories = append(m.directories, entry)
+			}
+		}
+	case patternClickMsg:
+		if !m.picking && msg.Index >= 0 && msg.Index < len(m.patterns) {
+			m.cursor = msg.Index
+		}
+	case patternScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.picking {
+			value := m.input.Value()
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			if m.input.Value() != value {
+				m.search()
+			}
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	if m.picking {
+		return m.handlePickerIntent(intent)
+	}
+	switch intent := intent.(type) {
+	case intents.SparseNavigate:
+		if len(m.patterns) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.patterns)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.SparseAdd:
+		if !m.loaded || m.err != nil {
+			return nil, true
+		}
+		m.picking = true
+		m.message = ""
+		m.input.SetValue("")
+		m.search()
+		return m.input.Focus(), true
+	case intents.SparseRemove:
+		if m.cursor < len(m.patterns) {
+			m.patterns = remove(m.patterns, m.cursor)
+			m.cursor = min(m.cursor, max(len(m.patterns)-1, 0))
+			m.message = ""
+		}
+		return nil, true
+	case intents.SparseReset:
+		if m.loaded && m.err == nil {
+			m.patterns = reset(m.patterns)
+			m.message = ""
+		}
+		return nil, true
+	case intents.Apply:
+		if !m.loaded || m.err != nil {
+			return nil, true
+		}
+		args := m.command()
+		if args == nil {
+			m.message = "nothing to apply, the patterns are unchanged"
+			return nil, true
+		}
+		return m.context.RunCommand(args, common.Refresh, common.CloseApplied), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) handlePickerIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.SparseNavigate:
+		// matches are listed bottom up like in file search
+		if len(m.matches) > 0 {
+			m.pickerCursor = max(min(m.pickerCursor-intent.Delta, len(m.matches)-1), 0)
+		}
+		return nil, true
+	case intents.Apply:
+		path := strings.TrimSpace(m.input.Value())
+		// forcing adds the input as typed, for files and paths that do not exist yet
+		if !intent.Force && m.pickerCursor < len(m.matches) {
+			path = m.directories[m.matches[m.pickerCursor].Index]
+		}
+		if path != "" {
+			m.patterns = add(m.patterns, path)
+			m.cursor = slices.IndexFunc(m.patterns, func(p *pattern) bool { return p.path == strings.TrimSuffix(path, "/") })
+			m.ensureCursorVisible = true
+		}
+		m.picking = false
+		m.input.Blur()
+		return nil, true
+	case intents.Cancel:
+		m.picking = false
+		m.input.Blur()
+		return nil, true
+	}
+	return nil, false
+}
+
+func (m *Model) search() {
+	source := &fuzzy_search.RefinedSource{Source: m.directories}
+	m.matches = source.Search(m.input.Value(), maxMatches)
+	m.pickerCursor = 0
+}
+
+// command returns the command applying the edits, resetting the patterns if
+// only the root one is left, or nil if nothing changed
+func (m *Model) command() jj.CommandArgs {
+	var added, removed []string
+	for _, p := range m.patterns {
+		switch p.state {
+		case patternAdded:
+			added = append(added, p.path)
+		case patternRemoved:
+			removed = append(removed, p.path)
+		}
+	}
+	switch {
+	case len(added) == 0 && len(removed) == 0:
+		return nil
+	case slices.Equal(effective(m.patterns, true), []string{rootPattern}):
+		return jj.SparseReset()
+	}
+	return jj.SparseSet(added, removed)
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("sparse text")
+	dimmedStyle := common.DefaultPalette.Get("sparse dimmed")
+	borderStyle := common.DefaultPalette.GetBorder("sparse border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("sparse patterns of the working copy", common.DefaultPalette.Get("sparse title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	var summaryBox layout.Box
+	contentBox, summaryBox = contentBox.CutBottom(1)
+	entering, leaving := changes(m.files, effective(m.patterns, false), effective(m.patterns, true))
+	summary := fmt.Sprintf("%d files would enter and %d would leave the working copy", entering, leaving)
+	dl.AddDraw(summaryBox.R, dimmedStyle.Render(ansi.Truncate(summary, summaryBox.R.Dx(), "…")), render.ZMenuContent)
+	contentBox, _ = contentBox.CutBottom(1)
+
+	if m.picking {
+		m.renderPicker(dl, contentBox)
+		return
+	}
+	m.renderPatterns(dl, contentBox)
+}
+
+func (m *Model) renderPatterns(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.patterns))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.patterns),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			p := m.patterns[index]
+			marker, style := "  ", common.DefaultPalette.Get("sparse text")
+			switch p.state {
+			case patternAdded:
+				marker, style = "+ ", common.DefaultPalette.Get("sparse added")
+			case patternRemoved:
+				marker, style = "- ", common.DefaultPalette.Get("sparse removed")
+			}
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("sparse selected")
+				style = style.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(ansi.Truncate(marker+p.path, rect.Dx(), "…"), style).
+				Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return patternClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// renderPicker draws the directory picker, the input at the bottom and the
+// best match right above it
+func (m *Model) renderPicker(dl *render.DisplayContext, box layout.Box) {
+	if box.R.Dx() <= 0 || box.R.Dy() <= 0 {
+		return
+	}
+	box, inputBox := box.CutBottom(1)
+	label := "add: "
+	dl.AddDraw(inputBox.R, common.DefaultPalette.Get("sparse dimmed").Render(label), render.ZMenuContent)
+	_, inputBox = inputBox.CutLeft(ansi.StringWidth(label))
+	m.input.SetWidth(max(inputBox.R.Dx()-1, 1))
+	dl.AddDraw(inputBox.R, m.input.View(), render.ZMenuContent)
+
+	textStyle := common.DefaultPalette.Get("sparse dimmed")
+	matchedStyle := common.DefaultPalette.Get("sparse matched")
+	for i, match := range m.matches {
+		y := box.R.Max.Y - 1 - i
+		if y < box.R.Min.Y {
+			break
+		}
+		lineStyle, matchStyle := textStyle, matchedStyle
+		if i == m.pickerCursor {
+			selected := common.DefaultPalette.Get("sparse selected")
+			lineStyle, matchStyle = common.DefaultPalette.Get("sparse text").Inherit(selected), matchedStyle.Inherit(selected)
+			dl.AddFill(layout.Rect(box.R.Min.X, y, box.R.Dx(), 1), ' ', selected, render.ZMenuContent)
+		}
+		entry := fuzzy_search.HighlightMatched(m.directories[match.Index], match, lineStyle, matchStyle)
+		dl.AddDraw(layout.Rect(box.R.Min.X, y, box.R.Dx(), 1), entry, render.ZMenuContent)
+	}
+}
+
+func NewModel(c *context.MainContext) *Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 0
+
+	m := &Model{
+		context:      c,
+		input:        input,
+		listRenderer: render.NewListRenderer(patternScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
@@ -0,0 +1,79 @@
+package sparse
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const fileList = "docs/index.md\nsrc/app/main.go\nsrc/lib/util.go\nREADME.md"
+
+func newLoadedModel(t *testing.T, commandRunner *test.CommandRunner) *Model {
+	commandRunner.Expect(jj.SparseList()).SetOutput([]byte("docs\n"))
+	commandRunner.Expect(jj.FilesInRevision(&jj.Commit{CommitId: "@"})).SetOutput([]byte(fileList))
+	model := NewModel(test.NewTestContext(commandRunner))
+	test.SimulateModel(model, model.Init())
+	return model
+}
+
+func TestAdd_PicksDirectoryAndShowsChanges(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	model := newLoadedModel(t, commandRunner)
+	commandRunner.Expect(jj.SparseSet([]string{"src/app"}, []string{"docs"}))
+	defer commandRunner.Verify()
+
+	test.SimulateModel(model, func() tea.Msg { return intents.SparseAdd{} })
+	assert.True(t, model.IsEditing())
+	test.SimulateModel(model, test.Type("app"))
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "src/app/")
+	model.Update(intents.Apply{})
+	assert.False(t, model.IsEditing())
+
+	model.Update(intents.SparseNavigate{Delta: -1})
+	model.Update(intents.SparseRemove{})
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "- docs")
+	assert.Contains(t, rendered, "+ src/app")
+	assert.Contains(t, rendered, "1 files would enter and 1 would leave the working copy")
+
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestReset_RunsSparseReset(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	model := newLoadedModel(t, commandRunner)
+	commandRunner.Expect(jj.SparseReset())
+	defer commandRunner.Verify()
+
+	model.Update(intents.SparseReset{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "3 files would enter and 0 would leave the working copy")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestApply_RefusesUnchangedPatterns(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	model := newLoadedModel(t, commandRunner)
+	defer commandRunner.Verify()
+
+	model.Update(intents.Apply{})
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "nothing to apply")
+}
+
+func TestAdd_ForceAddsInputAsTyped(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	model := newLoadedModel(t, commandRunner)
+	defer commandRunner.Verify()
+
+	test.SimulateModel(model, func() tea.Msg { return intents.SparseAdd{} })
+	test.SimulateModel(model, test.Type("src/ap"))
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 80, 20)), "src/app/")
+	model.Update(intents.Apply{Force: true})
+	assert.False(t, model.IsEditing())
+	rendered := test.Stripped(test.RenderImmediate(model, 80, 20))
+	assert.Contains(t, rendered, "+ src/ap ")
+	assert.NotContains(t, rendered, "+ src/app")
+}
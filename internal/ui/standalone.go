@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/config"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+// StandaloneModel is a full screen model that runs on its own, without the
+// revisions view around it. It is used when jj invokes jjui as an external
+// tool (e.g. as a diff editor).
+type StandaloneModel interface {
+	common.ImmediateModel
+	dispatch.ScopeProvider
+	dispatch.ScopeHandler
+}
+
+type standalone struct {
+	model          StandaloneModel
+	resolver       *dispatch.Resolver
+	displayContext *render.DisplayContext
+	width          int
+	height         int
+}
+
+// NewStandalone wraps model into a tea.Model resolving key bindings against
+// the scopes of the model.
+func NewStandalone(model StandaloneModel) tea.Model {
+	s := &standalone{model: model}
+	bindings := config.BindingsToRuntime(config.Current.Bindings)
+	if dispatcher, err := dispatch.NewDispatcher(bindings); err == nil {
+		s.resolver = dispatch.NewResolver(dispatcher)
+	}
+	return s
+}
+
+func (s *standalone) Init() tea.Cmd {
+	return s.model.Init()
+}
+
+func (s *standalone) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		s.height = msg.Height
+		return s, nil
+	case tea.MouseClickMsg, tea.MouseWheelMsg:
+		if s.displayContext != nil {
+			if interactionMsg, handled := s.displayContext.ProcessMouseEvent(msg.(tea.MouseMsg)); handled && interactionMsg != nil {
+				return s, func() tea.Msg { return interactionMsg }
+			}
+		}
+		return s, nil
+	case tea.KeyMsg:
+		if s.resolver == nil {
+			return s, s.model.Update(msg)
+		}
+		scopes := s.model.Scopes()
+		result := s.resolver.ResolveKey(msg, scopes)
+		if result.Intent != nil {
+			cmd, _ := dispatch.RouteIntent(scopes, result.Intent)
+			return s, cmd
+		}
+		if result.Consumed || result.Pending {
+			return s, nil
+		}
+		return s, s.model.Update(msg)
+	case intents.Intent:
+		cmd, _ := dispatch.RouteIntent(s.model.Scopes(), msg)
+		return s, cmd
+	}
+	return s, s.model.Update(msg)
+}
+
+func (s *standalone) View() tea.View {
+	var content string
+	if s.width > 0 && s.height > 0 {
+		s.displayContext = render.NewDisplayContext()
+		box := layout.NewBox(layout.Rect(0, 0, s.width, s.height))
+		s.model.ViewRect(s.displayContext, box)
+		screenBuf := render.NewScreenBuffer(s.width, s.height)
+		s.displayContext.Render(screenBuf)
+		content = strings.ReplaceAll(screenBuf.Render(), "\r", "")
+	}
+	v := tea.NewView(content)
+	v.AltScreen = true
+	v.MouseMode = tea.MouseModeCellMotion
+	return v
+}
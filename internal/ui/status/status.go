@@ -24,6 +24,10 @@ import (
 
 var expandFallback = help.Entry{Label: "?", Desc: "expand status"}
 
+// diffSearchMode is the mode of the quick search started from the diff view.
+// Unlike the other searches, it is applied as the query is typed.
+const diffSearchMode = "diff search"
+
 type FocusKind int
 
 const (
@@ -90,8 +94,20 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 			if fuzzy != nil && strings.HasSuffix(editMode, "file") {
 				return fuzzy.Update(intents.FileSearchCancel{}), true
 			}
+			if editMode == diffSearchMode {
+				return func() tea.Msg { return common.DiffSearchMsg("") }, true
+			}
 			return nil, true
 		}
+	case intents.FileSearchHistory:
+		if m.IsFocused() && m.fuzzy != nil {
+			fuzzy := m.fuzzy
+			m.fuzzy = nil
+			m.focusKind = FocusNone
+			m.mode = ""
+			m.input.Reset()
+			return fuzzy.Update(intent), true
+		}
 	case intents.Apply:
 		if m.IsFocused() {
 			editMode := m.mode
@@ -119,6 +135,8 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 				return nil, true
 			case strings.HasPrefix(editMode, "exec"):
 				return func() tea.Msg { return exec_process.ExecMsgFromLine(prompt, input) }, true
+			case editMode == diffSearchMode:
+				return func() tea.Msg { return common.DiffSearchMsg(input) }, true
 			}
 			return func() tea.Msg { return common.QuickSearchMsg(input) }, true
 		}
@@ -166,6 +184,10 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			if m.fuzzy != nil && m.input.Value() != previous {
 				cmd = tea.Batch(cmd, fuzzy_search.Search(m.input.Value()))
 			}
+			if m.mode == diffSearchMode && m.input.Value() != previous {
+				value := m.input.Value()
+				cmd = tea.Batch(cmd, func() tea.Msg { return common.DiffSearchMsg(value) })
+			}
 			return cmd
 		}
 		return nil
@@ -195,6 +217,14 @@ func (m *Model) StartQuickSearch() tea.Cmd {
 	return m.input.Focus()
 }
 
+func (m *Model) StartDiffSearch() tea.Cmd {
+	m.focusKind = FocusQuickSearch
+	m.mode = diffSearchMode
+	m.input.Prompt = "/ "
+	m.loadEditingSuggestions()
+	return m.input.Focus()
+}
+
 func (m *Model) saveEditingSuggestions() {
 	input := m.input.Value()
 	if len(strings.TrimSpace(input)) == 0 {
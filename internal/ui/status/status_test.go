@@ -9,6 +9,7 @@ import (
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
 	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/test"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -103,3 +104,17 @@ func TestStatus_Update_FileSearchFocusesAndTypingUpdatesInput(t *testing.T) {
 	m.Update(tea.KeyPressMsg{Text: "x", Code: 'x'})
 	assert.Equal(t, "x", m.InputValue())
 }
+
+func TestStatus_DiffSearchEmitsQueryWhileTyping(t *testing.T) {
+	ctx := &context.MainContext{
+		Histories: config.NewHistories(),
+	}
+	m := New(ctx)
+	m.StartDiffSearch()
+	assert.Equal(t, FocusQuickSearch, m.FocusKind())
+
+	var msgs []tea.Msg
+	test.SimulateModel(m, test.Type("ab"), func(msg tea.Msg) { msgs = append(msgs, msg) })
+	assert.Contains(t, msgs, common.DiffSearchMsg("a"))
+	assert.Contains(t, msgs, common.DiffSearchMsg("ab"))
+}
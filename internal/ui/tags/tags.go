@@ -0,0 +1,248 @@
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+
+type loadedMsg struct {
+	tags []jj.Tag
+	err  error
+}
+
+type tagClickMsg struct {
+	Index int
+}
+
+type tagScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m tagScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type Model struct {
+	context             *context.MainContext
+	revision            string
+	tags                []jj.Tag
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	listRenderer        *render.ListRenderer
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeTags,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.TagListAll())
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	return loadedMsg{tags: jj.ParseTagListOutput(string(output))}
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.tags = msg.tags
+		m.cursor = min(m.cursor, max(len(m.tags)-1, 0))
+	case tagClickMsg:
+		if msg.Index >= 0 && msg.Index < len(m.tags) {
+			m.cursor = msg.Index
+		}
+	case tagScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	switch intent := intent.(type) {
+	case intents.TagsNavigate:
+		if len(m.tags) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.tags)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.TagsMove:
+		if t := m.current(); t != nil {
+			return m.run(jj.TagSet(m.revision, t.Name, true)), true
+		}
+		return nil, true
+	case intents.TagsDelete:
+		if t := m.current(); t != nil {
+			return m.run(jj.TagDelete(t.Name)), true
+		}
+		return nil, true
+	case intents.Apply:
+		t := m.current()
+		if t == nil {
+			return nil, true
+		}
+		if t.Conflict {
+			m.message = fmt.Sprintf("tag %s is conflicted, move it to resolve the conflict", t.Name)
+			return nil, true
+		}
+		return tea.Sequence(common.Close, intents.Invoke(intents.Navigate{ChangeID: t.ChangeId})), true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) current() *jj.Tag {
+	if m.cursor < 0 || m.cursor >= len(m.tags) {
+		return nil
+	}
+	return &m.tags[m.cursor]
+}
+
+// run runs args and reloads the tags, refreshing the revisions too so the
+// graph shows where the tags are now
+func (m *Model) run(args jj.CommandArgs) tea.Cmd {
+	m.message = ""
+	return m.context.RunCommand(args, m.load, common.Refresh)
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("tags text")
+	borderStyle := common.DefaultPalette.GetBorder("tags border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("tags", common.DefaultPalette.Get("tags title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	case len(m.tags) == 0:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("tags dimmed").Render("no tags"), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	m.renderTags(dl, contentBox)
+}
+
+func (m *Model) renderTags(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	nameWidth := 0
+	for _, t := range m.tags {
+		nameWidth = max(nameWidth, ansi.StringWidth(t.Name))
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.tags))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.tags),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			t := m.tags[index]
+			textStyle := common.DefaultPalette.Get("tags text")
+			changeIdStyle := common.DefaultPalette.Get("tags change_id")
+			conflictStyle := common.DefaultPalette.Get("tags conflict")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("tags selected")
+				textStyle = textStyle.Inherit(selected)
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				conflictStyle = conflictStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			name := fmt.Sprintf("%-*s ", nameWidth, t.Name)
+			tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(name, textStyle)
+			remaining := max(rect.Dx()-ansi.StringWidth(name), 0)
+			if t.Conflict {
+				tb.Styled(ansi.Truncate("(conflicted)", remaining, "…"), conflictStyle).Done()
+				return
+			}
+			description := t.Description
+			if description == "" {
+				description = "(no description set)"
+			}
+			tb.Styled(t.ChangeId+" ", changeIdStyle)
+			remaining = max(remaining-ansi.StringWidth(t.ChangeId)-1, 0)
+			tb.Styled(ansi.Truncate(description, remaining, "…"), textStyle).Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return tagClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// NewModel creates the tags view, tags being moved to revision
+func NewModel(c *context.MainContext, revision string) *Model {
+	m := &Model{
+		context:      c,
+		revision:     revision,
+		listRenderer: render.NewListRenderer(tagScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
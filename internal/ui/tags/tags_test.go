@@ -0,0 +1,76 @@
+package tags
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const listOutput = "v1.0.0\t\tkxqpnrzs\t1a2b3c4d\trelease 1.0\n" +
+	"v1.1.0\tconflict\t\t\t\n" +
+	"nightly\t\tyqosqzyt\t5e6f7a8b\t\n"
+
+func TestLoad_ListsTags(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte(listOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "zzzzzzzz")
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "v1.0.0  kxqpnrzs release 1.0")
+	assert.Contains(t, rendered, "v1.1.0  (conflicted)")
+	assert.Contains(t, rendered, "nightly yqosqzyt (no description set)")
+}
+
+func TestMoveAndDelete_ChangeTheSelectedTag(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte(listOutput))
+	commandRunner.Expect(jj.TagSet("zzzzzzzz", "v1.1.0", true))
+	commandRunner.Expect(jj.TagDelete("nightly"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "zzzzzzzz")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.TagsNavigate{Delta: 1})
+	test.SimulateModel(model, func() tea.Msg { return intents.TagsMove{} })
+	model.Update(intents.TagsNavigate{Delta: 1})
+	test.SimulateModel(model, func() tea.Msg { return intents.TagsDelete{} })
+}
+
+func TestApply_NavigatesToTaggedRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte(listOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "zzzzzzzz")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.TagsNavigate{Delta: 2})
+
+	var navigated string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if navigate, ok := msg.(intents.Navigate); ok {
+			navigated = navigate.ChangeID
+		}
+	})
+	assert.Equal(t, "yqosqzyt", navigated)
+}
+
+func TestApply_ConflictedTagShowsMessage(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.TagListAll()).SetOutput([]byte(listOutput))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "zzzzzzzz")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.TagsNavigate{Delta: 1})
+	model.Update(intents.Apply{})
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "tag v1.1.0 is conflicted")
+}
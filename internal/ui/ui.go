@@ -12,8 +12,12 @@ import (
 	"github.com/idursun/jjui/internal/scripting"
 	"github.com/idursun/jjui/internal/ui/actionmeta"
 	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/autofetch"
 	keybindings "github.com/idursun/jjui/internal/ui/bindings"
 	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/divergence"
+	"github.com/idursun/jjui/internal/ui/explode"
+	"github.com/idursun/jjui/internal/ui/filehistory"
 	"github.com/idursun/jjui/internal/ui/flash"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
@@ -23,9 +27,12 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/idursun/jjui/internal/config"
 	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/annotate"
 	"github.com/idursun/jjui/internal/ui/bookmarks"
 	"github.com/idursun/jjui/internal/ui/choose"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/comparefiles"
+	"github.com/idursun/jjui/internal/ui/conflicts"
 	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/diff"
 	"github.com/idursun/jjui/internal/ui/exec_process"
@@ -33,13 +40,18 @@ import (
 	"github.com/idursun/jjui/internal/ui/help"
 
 	"github.com/idursun/jjui/internal/ui/input"
+	"github.com/idursun/jjui/internal/ui/opdiff"
 	"github.com/idursun/jjui/internal/ui/oplog"
 	"github.com/idursun/jjui/internal/ui/preview"
+	"github.com/idursun/jjui/internal/ui/rebaseplan"
 	"github.com/idursun/jjui/internal/ui/redo"
 	"github.com/idursun/jjui/internal/ui/revisions"
 	"github.com/idursun/jjui/internal/ui/revset"
+	"github.com/idursun/jjui/internal/ui/sparse"
 	"github.com/idursun/jjui/internal/ui/status"
+	"github.com/idursun/jjui/internal/ui/tags"
 	"github.com/idursun/jjui/internal/ui/undo"
+	"github.com/idursun/jjui/internal/ui/workspaces"
 )
 
 type Model struct {
@@ -54,6 +66,7 @@ type Model struct {
 	password         *password.Model
 	context          *context.MainContext
 	scriptRunner     *scripting.Runner
+	autoFetch        *autofetch.Model
 	sequenceHelp     []help.Entry
 	sequenceAutoOpen bool
 	resolver         *dispatch.Resolver
@@ -78,7 +91,15 @@ const scopeUi keybindings.ScopeName = "ui"
 var colorSchemePollInterval = time.Second
 
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(m.revisions.Init(), m.scheduleAutoRefresh())
+	return tea.Batch(m.revisions.Init(), m.scheduleAutoRefresh(), m.autoFetch.Init())
+}
+
+// setStacked replaces the stacked view, disposing the one it replaces
+func (m *Model) setStacked(model common.StackedModel) {
+	if disposable, ok := m.stacked.(common.Disposable); ok && m.stacked != model {
+		disposable.Dispose()
+	}
+	m.stacked = model
 }
 
 func (m *Model) closeTopScope(msg common.CloseViewMsg) (tea.Cmd, bool) {
@@ -88,7 +109,7 @@ func (m *Model) closeTopScope(msg common.CloseViewMsg) (tea.Cmd, bool) {
 	}
 	if m.stacked != nil {
 		cmd := m.stacked.Update(msg)
-		m.stacked = nil
+		m.setStacked(nil)
 		return cmd, true
 	}
 	if m.oplog != nil {
@@ -213,6 +234,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		return tea.Batch(m.scheduleAutoRefresh(), func() tea.Msg {
 			return common.AutoRefreshMsg{}
 		})
+	case autofetch.MovedMsg:
+		m.revisions.SetUpstreamMoved(msg.Bookmarks)
+		return nil
 	case common.UpdateRevSetMsg:
 		m.context.CurrentRevset = string(msg)
 		if m.context.CurrentRevset == "" {
@@ -264,18 +288,18 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		return nil
 	case common.ShowChooseMsg:
 		model := choose.NewWithOptions(msg.Options, msg.Title, msg.Filter, msg.Ordered)
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init()
 	case choose.SelectedMsg:
-		m.stacked = nil
+		m.setStacked(nil)
 	case choose.CancelledMsg:
-		m.stacked = nil
+		m.setStacked(nil)
 	case common.ShowInputMsg:
 		model := input.NewWithTitle(msg.Title, msg.Prompt)
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init()
 	case input.SelectedMsg, input.CancelledMsg:
-		m.stacked = nil
+		m.setStacked(nil)
 	case common.ShowPreview:
 		m.previewModel.SetVisible(bool(msg))
 		cmds = append(cmds, common.SelectionChanged(m.context.SelectedItem))
@@ -318,6 +342,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	cmds = append(cmds, m.revsetModel.Update(msg))
 	cmds = append(cmds, m.status.Update(msg))
 	cmds = append(cmds, m.flash.Update(msg))
+	cmds = append(cmds, m.autoFetch.Update(msg))
 	if m.diff != nil {
 		cmds = append(cmds, m.diff.Update(msg))
 	}
@@ -414,7 +439,8 @@ func (m *Model) View() string {
 		}
 	}
 
-	if m.stacked != nil {
+	// a diff opened from a stacked view is drawn on its own
+	if m.stacked != nil && m.diff == nil {
 		m.stacked.ViewRect(m.displayContext, box)
 	}
 
@@ -500,6 +526,13 @@ func (m *Model) scheduleAutoRefresh() tea.Cmd {
 	return nil
 }
 
+// busy reports whether the user is in the middle of something that a
+// background fetch and the refresh following it should not interrupt
+func (m *Model) busy() bool {
+	return m.stacked != nil || m.password != nil || m.scriptRunner != nil ||
+		m.revsetModel.Editing || !m.revisions.InNormalMode()
+}
+
 func (m *Model) dispatchScopes() []dispatch.Scope {
 	var scopes []dispatch.Scope
 
@@ -558,7 +591,7 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 	// --- Open stacked views ---
 	case intents.OpenGit:
 		model := git.NewModel(m.context, m.revisions.SelectedRevisions())
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init(), true
 	case intents.OpenBookmarks:
 		current := m.revisions.SelectedRevision()
@@ -567,32 +600,98 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		}
 		changeIds := m.revisions.GetCommitIds()
 		model := bookmarks.NewModel(m.context, current, changeIds)
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init(), true
 	case intents.OpLogOpen:
 		m.oplog = oplog.New(m.context)
 		return m.oplog.Init(), true
+	case intents.OpenAnnotate:
+		model := annotate.NewModel(m.context, intent.ChangeId, intent.File)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenFileHistory:
+		model := filehistory.NewModel(m.context, intent.File)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenExplode:
+		model := explode.NewModel(m.context, intent.Revision, intent.Files, intent.Grouped)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenSparse:
+		model := sparse.NewModel(m.context)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenWorkspaces:
+		revision := "@"
+		if selected := m.revisions.SelectedRevision(); selected != nil {
+			revision = selected.GetChangeId()
+		}
+		model := workspaces.NewModel(m.context, revision)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenTags:
+		revision := "@"
+		if selected := m.revisions.SelectedRevision(); selected != nil {
+			revision = selected.GetChangeId()
+		}
+		model := tags.NewModel(m.context, revision)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenConflicts:
+		model := conflicts.NewModel(m.context)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenDivergence:
+		model := divergence.NewModel(m.context)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.ShowCompare:
+		model := comparefiles.NewModel(m.context, intent.From, intent.To)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.JumpToRevision:
+		var cmds []tea.Cmd
+		if m.stacked != nil {
+			cmds = append(cmds, m.stacked.Update(common.CloseViewMsg{}))
+			m.setStacked(nil)
+		}
+		m.diff = nil
+		m.oplog = nil
+		cmd, _ := m.revisions.HandleIntent(intent)
+		return tea.Batch(append(cmds, cmd)...), true
+	case intents.ShowOpDiff:
+		model := opdiff.NewModel(m.context, intent.From, intent.To)
+		m.setStacked(model)
+		return m.stacked.Init(), true
+	case intents.OpenRebasePlan:
+		revset := rebaseplan.DefaultRevset
+		if selected := m.revisions.SelectedRevisions(); len(selected.Revisions) > 1 {
+			revset = strings.Join(selected.GetIds(), " | ")
+		}
+		model := rebaseplan.NewModel(m.context, revset)
+		m.setStacked(model)
+		return m.stacked.Init(), true
 	case intents.Undo:
 		model := undo.NewModel(m.context)
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init(), true
 	case intents.Redo:
 		model := redo.NewModel(m.context)
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init(), true
 	case intents.OpenHelp:
 		if m.stacked != nil || m.diff != nil {
 			return nil, true
 		}
 		model := help.New()
-		m.stacked = model
+		m.setStacked(model)
 		return m.stacked.Init(), true
 	case intents.CommandHistoryToggle:
 		if scope, ok := m.stackedScope(); ok && scope == actions.ScopeCommandHistory {
-			m.stacked = nil
+			m.setStacked(nil)
 			return nil, true
 		}
-		m.stacked = m.flash.NewHistory()
+		m.setStacked(m.flash.NewHistory())
 		return m.stacked.Init(), true
 
 	// --- Activate input modes ---
@@ -603,6 +702,9 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 	case intents.ExecShell:
 		return m.status.StartExec(common.ExecShell), true
 	case intents.QuickSearch:
+		if m.diff != nil {
+			return m.status.StartDiffSearch(), true
+		}
 		return m.status.StartQuickSearch(), true
 	case intents.FileSearchToggle:
 		rev := m.revisions.SelectedRevision()
@@ -624,6 +726,9 @@ func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
 		}
 		m.previewModel.ToggleVisible()
 		return common.SelectionChanged(m.context.SelectedItem), true
+	case intents.PreviewToggleSideBySide:
+		m.previewModel.ToggleSideBySide()
+		return common.SelectionChanged(m.context.SelectedItem), true
 	case intents.PreviewExpand:
 		if !m.previewModel.Visible() {
 			return nil, true
@@ -789,6 +894,7 @@ func NewUI(c *context.MainContext) *Model {
 		revsetModel:  revsetModel,
 		flash:        flashView,
 	}
+	ui.autoFetch = autofetch.New(c, ui.busy)
 	ui.initResolver()
 	ui.initSplit()
 	return ui
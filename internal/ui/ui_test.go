@@ -22,11 +22,13 @@ import (
 	"github.com/idursun/jjui/internal/ui/help"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/opdiff"
 	"github.com/idursun/jjui/internal/ui/operations/bookmark"
 	"github.com/idursun/jjui/internal/ui/operations/describe"
 	"github.com/idursun/jjui/internal/ui/operations/details"
 	"github.com/idursun/jjui/internal/ui/operations/rebase"
 	"github.com/idursun/jjui/internal/ui/operations/set_parents"
+	"github.com/idursun/jjui/internal/ui/oplog"
 	"github.com/idursun/jjui/internal/ui/render"
 	"github.com/idursun/jjui/internal/ui/revset"
 	"github.com/idursun/jjui/test"
@@ -606,6 +608,31 @@ func (m *scopeOnlyStackedModel) HandleIntent(intent intents.Intent) (tea.Cmd, bo
 	return nil, true
 }
 
+type disposableStackedModel struct {
+	scopeOnlyStackedModel
+	disposed bool
+}
+
+func (m *disposableStackedModel) Dispose() {
+	m.disposed = true
+}
+
+func Test_Update_DisposesStackedModelWhenClosedOrReplaced(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+
+	closed := &disposableStackedModel{scopeOnlyStackedModel: scopeOnlyStackedModel{scope: actions.ScopeGit}}
+	model.stacked = closed
+	model.Update(common.CloseViewMsg{})
+	assert.True(t, closed.disposed, "closing the stacked model should dispose it")
+
+	replaced := &disposableStackedModel{scopeOnlyStackedModel: scopeOnlyStackedModel{scope: actions.ScopeGit}}
+	model.stacked = replaced
+	model.Update(common.ShowChooseMsg{Options: []string{"a"}, Title: "pick"})
+	assert.True(t, replaced.disposed, "replacing the stacked model should dispose it")
+}
+
 func Test_DispatchScopes_UsesStackedScope(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	ctx := test.NewTestContext(commandRunner)
@@ -1491,3 +1518,18 @@ func Test_ResumeMsg_ReEnablesMode2031AndQueriesBackground(t *testing.T) {
 	assert.False(t, foundProbe2031, "resume should not re-probe mode 2031 support; the initial probe result still applies")
 	assert.False(t, foundPollTick, "resume should not restart polling; the existing poll loop survives suspension")
 }
+
+func TestJumpToRevisionClosesEveryViewAboveRevisions(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	model := NewUI(ctx)
+	model.oplog = oplog.New(ctx)
+	model.stacked = opdiff.NewModel(ctx, "8f4a4c2bd2d8", "0c9e1b7a3f21")
+
+	_, handled := model.HandleIntent(intents.JumpToRevision{ChangeID: "qpvuntsm"})
+	assert.True(t, handled)
+	assert.Nil(t, model.stacked)
+	assert.Nil(t, model.oplog)
+}
@@ -1,6 +1,9 @@
 package undo
 
 import (
+	"fmt"
+	"strings"
+
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -68,13 +71,39 @@ func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
 }
 
 func NewModel(context *context.MainContext) *Model {
+	if model := undoPointModel(context); model != nil {
+		return model
+	}
 	output, _ := context.RunCommandImmediate(jj.OpLog(1))
 	lastOperation := lipgloss.NewStyle().PaddingBottom(1).Render(string(output))
+	return newConfirmation([]string{lastOperation, "Are you sure you want to undo last change?"}, context.RunCommand(jj.Undo(), common.Refresh, common.Close))
+}
+
+// undoPointModel offers to restore the repo to where it was before the last
+// action made of several jj commands, when that action is still the last
+// thing that happened to the repo
+func undoPointModel(context *context.MainContext) *Model {
+	point := context.UndoPoint
+	if point == nil {
+		return nil
+	}
+	head, err := context.RunCommandImmediate(jj.OpLogId(true))
+	if err != nil || strings.TrimSpace(string(head)) != point.After {
+		context.UndoPoint = nil
+		return nil
+	}
+	return newConfirmation(
+		[]string{fmt.Sprintf("Are you sure you want to undo the %s?", point.Description), fmt.Sprintf("The repo goes back to operation %s.", point.Before)},
+		context.RunCommand(jj.OpRestore(point.Before), common.Refresh, common.Close),
+	)
+}
+
+func newConfirmation(messages []string, undo tea.Cmd) *Model {
 	model := confirmation.New(
-		[]string{lastOperation, "Are you sure you want to undo last change?"},
+		messages,
 		confirmation.WithStylePrefix("undo"),
 		confirmation.WithZIndex(render.ZDialogs),
-		confirmation.WithOption("Yes", context.RunCommand(jj.Undo(), common.Refresh, common.Close), key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
+		confirmation.WithOption("Yes", undo, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "yes"))),
 		confirmation.WithOption("No", common.Close, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "no"))),
 	)
 	return &Model{
@@ -7,6 +7,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/idursun/jjui/internal/jj"
 	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/context"
 	"github.com/idursun/jjui/internal/ui/intents"
 	"github.com/idursun/jjui/internal/ui/layout"
 	"github.com/idursun/jjui/internal/ui/render"
@@ -27,6 +28,36 @@ func TestConfirm(t *testing.T) {
 	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
 }
 
+func TestConfirm_RestoresUndoPoint(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("4567cdef\n"))
+	commandRunner.Expect(jj.OpRestore("0123abcd"))
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.UndoPoint = &context.UndoPoint{Description: "explode of rrrrrrrr", Before: "0123abcd", After: "4567cdef"}
+	model := NewModel(ctx)
+	test.SimulateModel(model, model.Init())
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 20)), "undo the explode of rrrrrrrr?")
+
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestConfirm_FallsBackWhenUndoPointIsStale(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	commandRunner.Expect(jj.OpLogId(true)).SetOutput([]byte("89abef01\n"))
+	commandRunner.Expect(jj.OpLog(1))
+	commandRunner.Expect(jj.Undo())
+	defer commandRunner.Verify()
+
+	ctx := test.NewTestContext(commandRunner)
+	ctx.UndoPoint = &context.UndoPoint{Description: "explode of rrrrrrrr", Before: "0123abcd", After: "4567cdef"}
+	model := NewModel(ctx)
+	assert.Nil(t, ctx.UndoPoint)
+
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
 func TestCancel(t *testing.T) {
 	commandRunner := test.NewTestCommandRunner(t)
 	commandRunner.Expect(jj.OpLog(1))
@@ -0,0 +1,494 @@
+package workspaces
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/actions"
+	"github.com/idursun/jjui/internal/ui/common"
+	"github.com/idursun/jjui/internal/ui/confirmation"
+	"github.com/idursun/jjui/internal/ui/context"
+	"github.com/idursun/jjui/internal/ui/dispatch"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/internal/ui/layout"
+	"github.com/idursun/jjui/internal/ui/render"
+)
+
+var _ common.ImmediateModel = (*Model)(nil)
+var _ common.Editable = (*Model)(nil)
+
+// workspace is a workspace along with where it is checked out, root being
+// empty if jj could not tell, and whether its working copy was found stale
+// by the last stale check
+type workspace struct {
+	jj.Workspace
+	root string
+	staleStatus
+}
+
+// staleStatus is what checking a working copy for staleness found out, err
+// being the first line of any other error jj reported
+type staleStatus struct {
+	stale bool
+	err   string
+}
+
+type loadedMsg struct {
+	workspaces []*workspace
+	err        error
+}
+
+// forgetConfirmedMsg is sent when forgetting the workspace is confirmed
+type forgetConfirmedMsg struct {
+	name string
+}
+
+// forgetCancelledMsg is sent when the workspace is kept
+type forgetCancelledMsg struct{}
+
+type workspaceClickMsg struct {
+	Index int
+}
+
+type workspaceScrollMsg struct {
+	Delta      int
+	Horizontal bool
+}
+
+func (m workspaceScrollMsg) SetDelta(delta int, horizontal bool) tea.Msg {
+	m.Delta = delta
+	m.Horizontal = horizontal
+	return m
+}
+
+type inputKind int
+
+const (
+	inputNone inputKind = iota
+	inputAdd
+	inputRename
+)
+
+type Model struct {
+	context             *context.MainContext
+	revision            string
+	workspaces          []*workspace
+	loaded              bool
+	err                 error
+	message             string
+	cursor              int
+	ensureCursorVisible bool
+	editing             inputKind
+	input               textinput.Model
+	listRenderer        *render.ListRenderer
+	// confirm asks before forgetting a workspace
+	confirm *confirmation.Model
+}
+
+func (m *Model) Scopes() []dispatch.Scope {
+	if m.confirm != nil {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeWorkspacesConfirmation,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	if m.editing != inputNone {
+		return []dispatch.Scope{
+			{
+				Name:    actions.ScopeWorkspacesInput,
+				Leak:    dispatch.LeakNone,
+				Handler: m,
+			},
+		}
+	}
+	return []dispatch.Scope{
+		{
+			Name:    actions.ScopeWorkspaces,
+			Leak:    dispatch.LeakGlobal,
+			Handler: m,
+		},
+	}
+}
+
+func (m *Model) IsEditing() bool {
+	return m.editing != inputNone
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.load
+}
+
+// load lists the workspaces without touching their working copies
+func (m *Model) load() tea.Msg {
+	output, err := m.context.RunCommandImmediate(jj.WorkspaceList())
+	if err != nil {
+		return loadedMsg{err: err}
+	}
+	var workspaces []*workspace
+	for _, w := range jj.ParseWorkspaceListOutput(string(output)) {
+		ws := &workspace{Workspace: w}
+		if root, err := m.context.RunCommandImmediate(jj.WorkspaceRoot(w.Name)); err == nil {
+			ws.root = strings.TrimSpace(string(root))
+		}
+		workspaces = append(workspaces, ws)
+	}
+	return loadedMsg{workspaces: workspaces}
+}
+
+// checkStale finds out which working copies are stale. The only way jj tells
+// is by failing to snapshot one, so it is left to an explicit key rather than
+// done on load, as snapshotting writes to the other workspaces.
+func (m *Model) checkStale() tea.Cmd {
+	var targets []*workspace
+	for _, w := range m.workspaces {
+		if w.root != "" || w.Current {
+			targets = append(targets, &workspace{Workspace: w.Workspace, root: w.root})
+		}
+	}
+	check := func() tea.Msg {
+		statuses := map[string]staleStatus{}
+		for _, w := range targets {
+			var status staleStatus
+			if _, err := m.context.RunCommandImmediate(jj.WorkspaceSnapshot(w.root)); err != nil {
+				message := strings.TrimSpace(err.Error())
+				status.stale = strings.Contains(message, "stale")
+				if !status.stale {
+					status.err, _, _ = strings.Cut(message, "\n")
+				}
+			}
+			statuses[w.Name] = status
+		}
+		// snapshots may have created new working-copy commits
+		msg := m.load().(loadedMsg)
+		for _, w := range msg.workspaces {
+			w.staleStatus = statuses[w.Name]
+		}
+		return msg
+	}
+	return tea.Sequence(check, common.Refresh)
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case forgetConfirmedMsg:
+		m.confirm = nil
+		return m.run(jj.WorkspaceForget(msg.name))
+	case forgetCancelledMsg:
+		m.confirm = nil
+		return nil
+	}
+	if m.confirm != nil {
+		return m.confirm.Update(msg)
+	}
+	switch msg := msg.(type) {
+	case loadedMsg:
+		m.loaded = true
+		m.err = msg.err
+		m.workspaces = msg.workspaces
+		m.cursor = min(m.cursor, max(len(m.workspaces)-1, 0))
+	case workspaceClickMsg:
+		if m.editing == inputNone && msg.Index >= 0 && msg.Index < len(m.workspaces) {
+			m.cursor = msg.Index
+		}
+	case workspaceScrollMsg:
+		if msg.Horizontal {
+			return nil
+		}
+		m.listRenderer.StartLine = max(m.listRenderer.StartLine+msg.Delta, 0)
+	case intents.Intent:
+		cmd, _ := m.HandleIntent(msg)
+		return cmd
+	case tea.KeyMsg, tea.PasteMsg:
+		if m.editing != inputNone {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) HandleIntent(intent intents.Intent) (tea.Cmd, bool) {
+	if m.confirm != nil {
+		switch intent.(type) {
+		case intents.Apply, intents.Cancel, intents.OptionSelect:
+			return m.confirm.Update(intent), true
+		}
+		return nil, false
+	}
+	if m.editing != inputNone {
+		switch intent.(type) {
+		case intents.Apply:
+			return m.accept(), true
+		case intents.Cancel:
+			m.stopEditing()
+			return nil, true
+		}
+		return nil, false
+	}
+	switch intent := intent.(type) {
+	case intents.WorkspacesNavigate:
+		if len(m.workspaces) > 0 {
+			m.cursor = max(min(m.cursor+intent.Delta, len(m.workspaces)-1), 0)
+			m.ensureCursorVisible = true
+		}
+		return nil, true
+	case intents.WorkspacesAdd:
+		return m.startEditing(inputAdd, ""), true
+	case intents.WorkspacesRename:
+		if w := m.current(); w != nil {
+			return m.startEditing(inputRename, w.Name), true
+		}
+		return nil, true
+	case intents.WorkspacesForget:
+		if w := m.current(); w != nil {
+			m.confirmForget(w)
+		}
+		return nil, true
+	case intents.WorkspacesCheckStale:
+		m.message = ""
+		return m.checkStale(), true
+	case intents.WorkspacesUpdateStale:
+		w := m.current()
+		if w == nil {
+			return nil, true
+		}
+		if args, ok := m.inWorkspace(w, jj.WorkspaceUpdateStale); ok {
+			return m.run(args), true
+		}
+		return nil, true
+	case intents.Apply:
+		if w := m.current(); w != nil {
+			return tea.Sequence(common.Close, intents.Invoke(intents.Navigate{ChangeID: w.ChangeId})), true
+		}
+		return nil, true
+	case intents.Cancel:
+		return common.Close, true
+	}
+	return nil, false
+}
+
+func (m *Model) current() *workspace {
+	if m.cursor < 0 || m.cursor >= len(m.workspaces) {
+		return nil
+	}
+	return m.workspaces[m.cursor]
+}
+
+func (m *Model) startEditing(kind inputKind, value string) tea.Cmd {
+	m.editing = kind
+	m.message = ""
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m *Model) stopEditing() {
+	m.editing = inputNone
+	m.input.Blur()
+}
+
+func (m *Model) accept() tea.Cmd {
+	value := strings.TrimSpace(m.input.Value())
+	kind := m.editing
+	m.stopEditing()
+	if value == "" {
+		return nil
+	}
+	switch kind {
+	case inputAdd:
+		return m.run(jj.WorkspaceAdd(value, m.revision))
+	case inputRename:
+		w := m.current()
+		if w == nil || value == w.Name {
+			return nil
+		}
+		if args, ok := m.inWorkspace(w, func(root string) jj.CommandArgs { return jj.WorkspaceRename(root, value) }); ok {
+			return m.run(args)
+		}
+	}
+	return nil
+}
+
+func (m *Model) confirmForget(w *workspace) {
+	messages := []string{fmt.Sprintf("Forget workspace %s?", w.Name), "Its files stay on disk but jj stops tracking its working copy."}
+	if w.Current {
+		messages = append(messages, "This is the workspace jjui is running in.")
+	}
+	forget := func() tea.Msg { return forgetConfirmedMsg{name: w.Name} }
+	keep := func() tea.Msg { return forgetCancelledMsg{} }
+	m.confirm = confirmation.New(
+		append(messages, ""),
+		confirmation.WithOption("Forget", forget, key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "forget"))),
+		confirmation.WithOption("Cancel", keep, key.NewBinding(key.WithKeys("n", "esc"), key.WithHelp("n/esc", "cancel"))),
+		confirmation.WithStylePrefix("workspaces"),
+		confirmation.WithZIndex(render.ZMenuContent+2),
+	)
+}
+
+// inWorkspace returns the command to run in the workspace w, which needs its
+// root unless it is the current one
+func (m *Model) inWorkspace(w *workspace, command func(root string) jj.CommandArgs) (jj.CommandArgs, bool) {
+	if w.root == "" && !w.Current {
+		m.message = fmt.Sprintf("cannot tell where workspace %s is checked out", w.Name)
+		return nil, false
+	}
+	return command(w.root), true
+}
+
+// run runs args and reloads the workspaces, refreshing the revisions too as
+// working-copy commits may have changed
+func (m *Model) run(args jj.CommandArgs) tea.Cmd {
+	return m.context.RunCommand(args, m.load, common.Refresh)
+}
+
+func (m *Model) ViewRect(dl *render.DisplayContext, box layout.Box) {
+	pw, ph := box.R.Dx(), box.R.Dy()
+	frame := box.Center(min(pw, 100), min(ph, 30))
+	if frame.R.Dx() <= 2 || frame.R.Dy() <= 2 {
+		return
+	}
+	textStyle := common.DefaultPalette.Get("workspaces text")
+	borderStyle := common.DefaultPalette.GetBorder("workspaces border", lipgloss.NormalBorder())
+
+	dl.AddBackdrop(box.R, render.ZMenuBorder-1)
+	contentBox := frame.Inset(1)
+	dl.AddFill(contentBox.R, ' ', textStyle, render.ZMenuContent)
+	borderBase := lipgloss.NewStyle().Width(contentBox.R.Dx()).Height(contentBox.R.Dy()).Render("")
+	dl.AddDraw(frame.R, borderStyle.Render(borderBase), render.ZMenuBorder)
+
+	titleBox, contentBox := contentBox.CutTop(1)
+	dl.
+		Text(titleBox.R.Min.X, titleBox.R.Min.Y, render.ZMenuContent).
+		Styled("workspaces", common.DefaultPalette.Get("workspaces title")).
+		Done()
+	_, contentBox = contentBox.CutTop(1)
+
+	switch {
+	case m.err != nil:
+		dl.AddDraw(contentBox.R, common.DefaultPalette.Get("error").Render(strings.TrimSpace(m.err.Error())), render.ZMenuContent)
+		return
+	case !m.loaded:
+		dl.AddDraw(contentBox.R, textStyle.Render("loading..."), render.ZMenuContent)
+		return
+	}
+
+	if m.message != "" {
+		var messageBox layout.Box
+		contentBox, messageBox = contentBox.CutBottom(1)
+		dl.AddDraw(messageBox.R, common.DefaultPalette.Get("error").Render(ansi.Truncate(m.message, messageBox.R.Dx(), "…")), render.ZMenuContent)
+	}
+	if m.editing != inputNone {
+		var inputBox layout.Box
+		contentBox, inputBox = contentBox.CutBottom(1)
+		label := fmt.Sprintf("path of the new workspace on %s: ", m.revision)
+		if w := m.current(); m.editing == inputRename && w != nil {
+			label = fmt.Sprintf("rename %s to: ", w.Name)
+		}
+		dl.AddDraw(inputBox.R, common.DefaultPalette.Get("workspaces dimmed").Render(label), render.ZMenuContent)
+		_, inputBox = inputBox.CutLeft(ansi.StringWidth(label))
+		m.input.SetWidth(max(inputBox.R.Dx()-1, 1))
+		dl.AddDraw(inputBox.R, m.input.View(), render.ZMenuContent)
+		contentBox, _ = contentBox.CutBottom(1)
+	}
+	m.renderWorkspaces(dl, contentBox)
+
+	if m.confirm != nil {
+		m.confirm.Styles.Border = common.DefaultPalette.GetBorder("workspaces confirmation border", lipgloss.NormalBorder()).Padding(1)
+		v := m.confirm.View()
+		w, h := lipgloss.Size(v)
+		confirmBox := layout.Box{R: layout.Rect(frame.R.Min.X+max((frame.R.Dx()-w)/2, 0), frame.R.Min.Y+max((frame.R.Dy()-h)/2, 0), w, h)}
+		m.confirm.ViewRect(dl, confirmBox)
+	}
+}
+
+func (m *Model) renderWorkspaces(dl *render.DisplayContext, listBox layout.Box) {
+	if listBox.R.Dx() <= 0 || listBox.R.Dy() <= 0 {
+		return
+	}
+	nameWidth := 0
+	for _, w := range m.workspaces {
+		nameWidth = max(nameWidth, ansi.StringWidth(w.Name))
+	}
+	m.listRenderer.StartLine = render.ClampStartLine(m.listRenderer.StartLine, listBox.R.Dy(), len(m.workspaces))
+	m.listRenderer.Render(
+		dl,
+		listBox,
+		len(m.workspaces),
+		m.cursor,
+		m.ensureCursorVisible,
+		func(_ int) int { return 1 },
+		func(dl *render.DisplayContext, index int, rect layout.Rectangle) {
+			w := m.workspaces[index]
+			textStyle := common.DefaultPalette.Get("workspaces text")
+			changeIdStyle := common.DefaultPalette.Get("workspaces change_id")
+			dimmedStyle := common.DefaultPalette.Get("workspaces dimmed")
+			staleStyle := common.DefaultPalette.Get("workspaces stale")
+			if index == m.cursor {
+				selected := common.DefaultPalette.Get("workspaces selected")
+				textStyle = textStyle.Inherit(selected)
+				changeIdStyle = changeIdStyle.Inherit(selected)
+				dimmedStyle = dimmedStyle.Inherit(selected)
+				staleStyle = staleStyle.Inherit(selected)
+				dl.AddFill(rect, ' ', selected, render.ZMenuContent)
+			}
+			marker := "  "
+			if w.Current {
+				marker = "@ "
+			}
+			status := ""
+			switch {
+			case w.stale:
+				status = "stale "
+			case w.err != "":
+				status = w.err + " "
+			}
+			description := w.Description
+			if description == "" {
+				description = "(no description set)"
+			}
+			line := fmt.Sprintf("%s%-*s %s ", marker, nameWidth, w.Name, w.ChangeId)
+			remaining := max(rect.Dx()-ansi.StringWidth(line), 0)
+			tb := dl.Text(rect.Min.X, rect.Min.Y, render.ZMenuContent).
+				Styled(fmt.Sprintf("%s%-*s ", marker, nameWidth, w.Name), textStyle).
+				Styled(w.ChangeId+" ", changeIdStyle).
+				Styled(ansi.Truncate(status, remaining, "…"), staleStyle)
+			remaining = max(remaining-ansi.StringWidth(status), 0)
+			tb.Styled(ansi.Truncate(description, remaining, "…"), textStyle)
+			remaining = max(remaining-ansi.StringWidth(description), 0)
+			if w.root != "" && remaining > 2 {
+				tb.Styled(ansi.Truncate("  "+w.root, remaining, "…"), dimmedStyle)
+			}
+			tb.Done()
+		},
+		func(index int, _ tea.Mouse) tea.Msg { return workspaceClickMsg{Index: index} },
+	)
+	m.listRenderer.RegisterScroll(dl, listBox)
+	m.ensureCursorVisible = false
+}
+
+// NewModel creates the workspaces view, new workspaces being created on
+// revision
+func NewModel(c *context.MainContext, revision string) *Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 0
+
+	m := &Model{
+		context:      c,
+		revision:     revision,
+		input:        input,
+		listRenderer: render.NewListRenderer(workspaceScrollMsg{}),
+	}
+	m.listRenderer.Z = render.ZMenuContent
+	return m
+}
@@ -0,0 +1,133 @@
+package workspaces
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/idursun/jjui/internal/jj"
+	"github.com/idursun/jjui/internal/ui/intents"
+	"github.com/idursun/jjui/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const listOutput = "default\t@\tkxqpnrzs\t1a2b3c4d\tadd workspaces view\n" +
+	"docs\t\tyqosqzyt\t5e6f7a8b\tupdate readme"
+
+func expectLoad(commandRunner *test.CommandRunner) {
+	commandRunner.Expect(jj.WorkspaceList()).SetOutput([]byte(listOutput))
+	commandRunner.Expect(jj.WorkspaceRoot("default")).SetOutput([]byte("/src/repo"))
+	commandRunner.Expect(jj.WorkspaceRoot("docs")).SetOutput([]byte("/src/docs"))
+}
+
+func TestLoad_ListsWorkspacesWithoutSnapshotting(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "@ default kxqpnrzs add workspaces view  /src/repo")
+	assert.Contains(t, rendered, "docs    yqosqzyt update readme  /src/docs")
+}
+
+func TestCheckStale_SnapshotsToFindStaleWorkspaces(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.WorkspaceSnapshot("/src/repo"))
+	commandRunner.Expect(jj.WorkspaceSnapshot("/src/docs")).SetError(errors.New("Error: The working copy is stale (not updated since operation 0123abcd)."))
+	expectLoad(commandRunner)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesCheckStale{} })
+
+	rendered := test.Stripped(test.RenderImmediate(model, 100, 20))
+	assert.Contains(t, rendered, "@ default kxqpnrzs add workspaces view  /src/repo")
+	assert.Contains(t, rendered, "docs    yqosqzyt stale update readme  /src/docs")
+}
+
+func TestAdd_CreatesWorkspaceOnRevision(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.WorkspaceAdd("../feature", "kxqpnrzs"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesAdd{} })
+	assert.True(t, model.IsEditing())
+
+	model.input.SetValue("../feature")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.False(t, model.IsEditing())
+}
+
+func TestRenameAndUpdateStale_RunInTheSelectedWorkspace(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.WorkspaceUpdateStale("/src/docs"))
+	commandRunner.Expect(jj.WorkspaceRename("/src/docs", "manual"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.WorkspacesNavigate{Delta: 1})
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesUpdateStale{} })
+
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesRename{} })
+	assert.Equal(t, "docs", model.input.Value())
+	model.input.SetValue("manual")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+}
+
+func TestForget_ForgetsSelectedWorkspace(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	commandRunner.Expect(jj.WorkspaceForget("docs"))
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.WorkspacesNavigate{Delta: 1})
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesForget{} })
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 30)), "Forget workspace docs?")
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} })
+	assert.Nil(t, model.confirm)
+}
+
+func TestForget_CanBeCancelled(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	test.SimulateModel(model, func() tea.Msg { return intents.WorkspacesForget{} })
+	assert.Contains(t, test.Stripped(test.RenderImmediate(model, 100, 30)), "This is the workspace jjui is running in.")
+	test.SimulateModel(model, func() tea.Msg { return intents.Cancel{} })
+
+	assert.Nil(t, model.confirm)
+	assert.NotContains(t, test.Stripped(test.RenderImmediate(model, 100, 30)), "Forget workspace")
+}
+
+func TestApply_NavigatesToWorkingCopy(t *testing.T) {
+	commandRunner := test.NewTestCommandRunner(t)
+	expectLoad(commandRunner)
+	defer commandRunner.Verify()
+
+	model := NewModel(test.NewTestContext(commandRunner), "kxqpnrzs")
+	test.SimulateModel(model, model.Init())
+	model.Update(intents.WorkspacesNavigate{Delta: 1})
+
+	var navigated string
+	test.SimulateModel(model, func() tea.Msg { return intents.Apply{} }, func(msg tea.Msg) {
+		if navigate, ok := msg.(intents.Navigate); ok {
+			navigated = navigate.ChangeID
+		}
+	})
+	assert.Equal(t, "yqosqzyt", navigated)
+}
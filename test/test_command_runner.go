@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"slices"
+	"strings"
 	"sync"
 	"testing"
 
@@ -49,12 +50,18 @@ func (t *CommandRunner) RunCommandImmediate(args []string) ([]byte, error) {
 		assert.Fail(t, "unexpected command", subCommand)
 	}
 
+	// the same command may be expected several times with different outputs,
+	// so the ones not called yet are answered first
+	var matched *ExpectedCommand
 	for _, e := range expectations {
-		if slices.Equal(e.args, args) {
-			e.called = true
-			return e.output, e.err
+		if slices.Equal(e.args, args) && (matched == nil || matched.called && !e.called) {
+			matched = e
 		}
 	}
+	if matched != nil {
+		matched.called = true
+		return matched.output, matched.err
+	}
 	assert.Fail(t, "unexpected command", subCommand)
 	return nil, nil
 }
@@ -71,6 +78,11 @@ func (t *CommandRunner) RunCommandStreaming(_ context.Context, args []string) (*
 	}, err
 }
 
+func (t *CommandRunner) RunCommandWithProgress(args []string) (*appContext.ProgressCommand, error) {
+	output, err := t.RunCommandImmediate(args)
+	return appContext.NewCompletedProgressCommand(0, "jj "+strings.Join(args, " "), string(output), err), nil
+}
+
 func (t *CommandRunner) RunCommandWithInput(args []string, input string, continuations ...tea.Cmd) tea.Cmd {
 	cmds := make([]tea.Cmd, 0)
 	cmds = append(cmds, func() tea.Msg {